@@ -26,17 +26,20 @@ const (
 type Status int32
 
 const (
-	Status_UNKNOWN     Status = 0
-	Status_PASS        Status = 1
-	Status_FAIL        Status = 2
-	Status_SKIPPED     Status = 3  // This task was not performed
-	Status_FILTERED    Status = 4  // Subcategory of skipped; this is represents filtering by a planning node
-	Status_TASK_ERROR  Status = 5  // Encountered an error executing the task itself
-	Status_INFRA_ERROR Status = 6  // The agent framework encountered and error
-	Status_TIMEOUT     Status = 7  // subcategory of infra error
-	Status_CRASH       Status = 8  // subcategory of infra error
-	Status_BLOCKED     Status = 9  // Waiting for dependencies
-	Status_RUNNING     Status = 10 // Dispatched to Node Service
+	Status_UNKNOWN              Status = 0
+	Status_PASS                 Status = 1
+	Status_FAIL                 Status = 2
+	Status_SKIPPED              Status = 3  // This task was not performed
+	Status_FILTERED             Status = 4  // Subcategory of skipped; this is represents filtering by a planning node
+	Status_TASK_ERROR           Status = 5  // Encountered an error executing the task itself
+	Status_INFRA_ERROR          Status = 6  // The agent framework encountered and error
+	Status_TIMEOUT              Status = 7  // subcategory of infra error
+	Status_CRASH                Status = 8  // subcategory of infra error
+	Status_BLOCKED              Status = 9  // Waiting for dependencies
+	Status_RUNNING              Status = 10 // Dispatched to Node Service
+	Status_READY                Status = 11 // Dependencies satisfied, waiting to be dispatched
+	Status_WAITING_FOR_APPROVAL Status = 12 // An APPROVAL node parked until a human approves or rejects it
+	Status_WAITING_FOR_WORKFLOW Status = 13 // A SUBWORKFLOW node waiting for its child workflow to complete
 )
 
 // Enum value maps for Status.
@@ -53,19 +56,25 @@ var (
 		8:  "CRASH",
 		9:  "BLOCKED",
 		10: "RUNNING",
+		11: "READY",
+		12: "WAITING_FOR_APPROVAL",
+		13: "WAITING_FOR_WORKFLOW",
 	}
 	Status_value = map[string]int32{
-		"UNKNOWN":     0,
-		"PASS":        1,
-		"FAIL":        2,
-		"SKIPPED":     3,
-		"FILTERED":    4,
-		"TASK_ERROR":  5,
-		"INFRA_ERROR": 6,
-		"TIMEOUT":     7,
-		"CRASH":       8,
-		"BLOCKED":     9,
-		"RUNNING":     10,
+		"UNKNOWN":              0,
+		"PASS":                 1,
+		"FAIL":                 2,
+		"SKIPPED":              3,
+		"FILTERED":             4,
+		"TASK_ERROR":           5,
+		"INFRA_ERROR":          6,
+		"TIMEOUT":              7,
+		"CRASH":                8,
+		"BLOCKED":              9,
+		"RUNNING":              10,
+		"READY":                11,
+		"WAITING_FOR_APPROVAL": 12,
+		"WAITING_FOR_WORKFLOW": 13,
 	}
 )
 
@@ -96,6 +105,222 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return file_protos_workflow_node_proto_rawDescGZIP(), []int{0}
 }
 
+// What happens to the rest of a workflow when one of its nodes ends in a
+// failure status (FAIL, TASK_ERROR, INFRA_ERROR, TIMEOUT or CRASH).
+type FailurePolicy int32
+
+const (
+	FailurePolicy_FAILURE_POLICY_UNSPECIFIED FailurePolicy = 0 // Nodes inherit the workflow's policy; workflows default to SKIP_DESCENDANTS
+	FailurePolicy_SKIP_DESCENDANTS           FailurePolicy = 1 // Mark nodes downstream of the failure SKIPPED; independent branches keep running
+	FailurePolicy_FAIL_FAST                  FailurePolicy = 2 // Skip every unfinished node in the workflow, canceling those in flight
+	FailurePolicy_CONTINUE                   FailurePolicy = 3 // Leave downstream nodes blocked; independent branches keep running
+)
+
+// Enum value maps for FailurePolicy.
+var (
+	FailurePolicy_name = map[int32]string{
+		0: "FAILURE_POLICY_UNSPECIFIED",
+		1: "SKIP_DESCENDANTS",
+		2: "FAIL_FAST",
+		3: "CONTINUE",
+	}
+	FailurePolicy_value = map[string]int32{
+		"FAILURE_POLICY_UNSPECIFIED": 0,
+		"SKIP_DESCENDANTS":           1,
+		"FAIL_FAST":                  2,
+		"CONTINUE":                   3,
+	}
+)
+
+func (x FailurePolicy) Enum() *FailurePolicy {
+	p := new(FailurePolicy)
+	*p = x
+	return p
+}
+
+func (x FailurePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FailurePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_workflow_node_proto_enumTypes[1].Descriptor()
+}
+
+func (FailurePolicy) Type() protoreflect.EnumType {
+	return &file_protos_workflow_node_proto_enumTypes[1]
+}
+
+func (x FailurePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FailurePolicy.Descriptor instead.
+func (FailurePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{1}
+}
+
+// What kind of work a node does
+type NodeType int32
+
+const (
+	NodeType_AGENT       NodeType = 0 // Executed by an agent on a NodeService backend
+	NodeType_TIMER       NodeType = 1 // Does nothing but wait; passes as soon as not_before and delay have elapsed
+	NodeType_APPROVAL    NodeType = 2 // Waits in WAITING_FOR_APPROVAL until ApproveNode (PASS) or RejectNode (FAIL)
+	NodeType_MAP         NodeType = 3 // Once it passes, expands into one child node per item; see MapOptions
+	NodeType_REDUCE      NodeType = 4 // Collects its parents' tasks, with their results, into assigned_task.subtasks
+	NodeType_BRANCH      NodeType = 5 // Once it passes, takes the children selected by BranchOptions and filters the rest
+	NodeType_SUBWORKFLOW NodeType = 6 // Runs a child workflow from SubworkflowOptions and ends with its aggregate status
+)
+
+// Enum value maps for NodeType.
+var (
+	NodeType_name = map[int32]string{
+		0: "AGENT",
+		1: "TIMER",
+		2: "APPROVAL",
+		3: "MAP",
+		4: "REDUCE",
+		5: "BRANCH",
+		6: "SUBWORKFLOW",
+	}
+	NodeType_value = map[string]int32{
+		"AGENT":       0,
+		"TIMER":       1,
+		"APPROVAL":    2,
+		"MAP":         3,
+		"REDUCE":      4,
+		"BRANCH":      5,
+		"SUBWORKFLOW": 6,
+	}
+)
+
+func (x NodeType) Enum() *NodeType {
+	p := new(NodeType)
+	*p = x
+	return p
+}
+
+func (x NodeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NodeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_workflow_node_proto_enumTypes[2].Descriptor()
+}
+
+func (NodeType) Type() protoreflect.EnumType {
+	return &file_protos_workflow_node_proto_enumTypes[2]
+}
+
+func (x NodeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NodeType.Descriptor instead.
+func (NodeType) EnumDescriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{2}
+}
+
+// What a trigger does about scheduled runs it missed, e.g. while no workflow
+// service was running
+type MissedRunPolicy int32
+
+const (
+	MissedRunPolicy_MISSED_RUN_POLICY_UNSPECIFIED MissedRunPolicy = 0 // Defaults to CATCH_UP_ONCE
+	MissedRunPolicy_CATCH_UP_ONCE                 MissedRunPolicy = 1 // Start a single workflow for any number of missed runs
+	MissedRunPolicy_SKIP_MISSED                   MissedRunPolicy = 2 // Start no workflow for missed runs; wait for the next scheduled one
+	MissedRunPolicy_CATCH_UP_ALL                  MissedRunPolicy = 3 // Start a workflow for every missed run
+)
+
+// Enum value maps for MissedRunPolicy.
+var (
+	MissedRunPolicy_name = map[int32]string{
+		0: "MISSED_RUN_POLICY_UNSPECIFIED",
+		1: "CATCH_UP_ONCE",
+		2: "SKIP_MISSED",
+		3: "CATCH_UP_ALL",
+	}
+	MissedRunPolicy_value = map[string]int32{
+		"MISSED_RUN_POLICY_UNSPECIFIED": 0,
+		"CATCH_UP_ONCE":                 1,
+		"SKIP_MISSED":                   2,
+		"CATCH_UP_ALL":                  3,
+	}
+)
+
+func (x MissedRunPolicy) Enum() *MissedRunPolicy {
+	p := new(MissedRunPolicy)
+	*p = x
+	return p
+}
+
+func (x MissedRunPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MissedRunPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_workflow_node_proto_enumTypes[3].Descriptor()
+}
+
+func (MissedRunPolicy) Type() protoreflect.EnumType {
+	return &file_protos_workflow_node_proto_enumTypes[3]
+}
+
+func (x MissedRunPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MissedRunPolicy.Descriptor instead.
+func (MissedRunPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{3}
+}
+
+type MapOptions_Source int32
+
+const (
+	MapOptions_SUBTASKS     MapOptions_Source = 0 // One item per subtask of the MAP node's assigned task
+	MapOptions_OUTPUT_ITEMS MapOptions_Source = 1 // One item per element of the JSON array in its assigned task's latest result output
+)
+
+// Enum value maps for MapOptions_Source.
+var (
+	MapOptions_Source_name = map[int32]string{
+		0: "SUBTASKS",
+		1: "OUTPUT_ITEMS",
+	}
+	MapOptions_Source_value = map[string]int32{
+		"SUBTASKS":     0,
+		"OUTPUT_ITEMS": 1,
+	}
+)
+
+func (x MapOptions_Source) Enum() *MapOptions_Source {
+	p := new(MapOptions_Source)
+	*p = x
+	return p
+}
+
+func (x MapOptions_Source) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MapOptions_Source) Descriptor() protoreflect.EnumDescriptor {
+	return file_protos_workflow_node_proto_enumTypes[4].Descriptor()
+}
+
+func (MapOptions_Source) Type() protoreflect.EnumType {
+	return &file_protos_workflow_node_proto_enumTypes[4]
+}
+
+func (x MapOptions_Source) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MapOptions_Source.Descriptor instead.
+func (MapOptions_Source) EnumDescriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{2, 0}
+}
+
 type NodeEdit_Type int32
 
 const (
@@ -132,11 +357,11 @@ func (x NodeEdit_Type) String() string {
 }
 
 func (NodeEdit_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_protos_workflow_node_proto_enumTypes[1].Descriptor()
+	return file_protos_workflow_node_proto_enumTypes[5].Descriptor()
 }
 
 func (NodeEdit_Type) Type() protoreflect.EnumType {
-	return &file_protos_workflow_node_proto_enumTypes[1]
+	return &file_protos_workflow_node_proto_enumTypes[5]
 }
 
 func (x NodeEdit_Type) Number() protoreflect.EnumNumber {
@@ -145,7 +370,7 @@ func (x NodeEdit_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NodeEdit_Type.Descriptor instead.
 func (NodeEdit_Type) EnumDescriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{5, 0}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{12, 0}
 }
 
 // Represents a single node within a workflow graph
@@ -172,7 +397,38 @@ type Node struct {
 	// Any changes to the nodes (ex. inserting nodes to fulfill tasks).
 	Edits []*NodeEdit `protobuf:"bytes,10,rep,name=edits,proto3" json:"edits,omitempty"`
 	// Whether the node is complete, and immutable.
-	IsFinal       bool `protobuf:"varint,11,opt,name=is_final,json=isFinal,proto3" json:"is_final,omitempty"`
+	IsFinal bool `protobuf:"varint,11,opt,name=is_final,json=isFinal,proto3" json:"is_final,omitempty"`
+	// Log of notable status transitions, such as recovery of an expired lease.
+	NodeStatus *NodeStatus `protobuf:"bytes,12,opt,name=node_status,json=nodeStatus,proto3" json:"node_status,omitempty"`
+	// Every attempt the scheduler has made to execute this node, oldest first.
+	Attempts []*Attempt `protobuf:"bytes,13,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	// Scheduling priority relative to the workflow's own priority; higher is
+	// dispatched first under the priority policy.
+	Priority int32 `protobuf:"varint,14,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Overrides the workflow's failure policy when this node fails.
+	FailurePolicy FailurePolicy `protobuf:"varint,15,opt,name=failure_policy,json=failurePolicy,proto3,enum=aisociety.workflow.FailurePolicy" json:"failure_policy,omitempty"`
+	// The node is not ready before this time, even once its parents are
+	// satisfied.
+	NotBefore *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	// How long the node waits once its parents are satisfied, or once it is
+	// created if it has none, before it is ready.
+	Delay *durationpb.Duration `protobuf:"bytes,17,opt,name=delay,proto3" json:"delay,omitempty"`
+	// What the node does; only AGENT nodes are sent to a NodeService.
+	Type NodeType `protobuf:"varint,18,opt,name=type,proto3,enum=aisociety.workflow.NodeType" json:"type,omitempty"`
+	// The decision on an APPROVAL node, once one is made.
+	Approval *Approval `protobuf:"bytes,19,opt,name=approval,proto3" json:"approval,omitempty"`
+	// How a MAP node expands.
+	MapOptions *MapOptions `protobuf:"bytes,20,opt,name=map_options,json=mapOptions,proto3" json:"map_options,omitempty"`
+	// Which children a BRANCH node takes.
+	BranchOptions *BranchOptions `protobuf:"bytes,21,opt,name=branch_options,json=branchOptions,proto3" json:"branch_options,omitempty"`
+	// The child workflow a SUBWORKFLOW node runs.
+	SubworkflowOptions *SubworkflowOptions `protobuf:"bytes,22,opt,name=subworkflow_options,json=subworkflowOptions,proto3" json:"subworkflow_options,omitempty"`
+	// Edits this node produced that the edit policy refused to apply.
+	RejectedEdits []*RejectedEdit `protobuf:"bytes,23,rep,name=rejected_edits,json=rejectedEdits,proto3" json:"rejected_edits,omitempty"`
+	// How many generations of agent edits led to this node: 0 for nodes the
+	// workflow was created with, one more than the inserting node's otherwise.
+	// Set by the state manager.
+	EditDepth     int32 `protobuf:"varint,24,opt,name=edit_depth,json=editDepth,proto3" json:"edit_depth,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -284,28 +540,126 @@ func (x *Node) GetIsFinal() bool {
 	return false
 }
 
-type ExecutionOptions struct {
-	state         protoimpl.MessageState         `protogen:"open.v1"`
-	Timeout       *durationpb.Duration           `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	RetryOptions  *ExecutionOptions_RetryOptions `protobuf:"bytes,2,opt,name=retry_options,json=retryOptions,proto3" json:"retry_options,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Node) GetNodeStatus() *NodeStatus {
+	if x != nil {
+		return x.NodeStatus
+	}
+	return nil
 }
 
-func (x *ExecutionOptions) Reset() {
-	*x = ExecutionOptions{}
+func (x *Node) GetAttempts() []*Attempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *Node) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Node) GetFailurePolicy() FailurePolicy {
+	if x != nil {
+		return x.FailurePolicy
+	}
+	return FailurePolicy_FAILURE_POLICY_UNSPECIFIED
+}
+
+func (x *Node) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *Node) GetDelay() *durationpb.Duration {
+	if x != nil {
+		return x.Delay
+	}
+	return nil
+}
+
+func (x *Node) GetType() NodeType {
+	if x != nil {
+		return x.Type
+	}
+	return NodeType_AGENT
+}
+
+func (x *Node) GetApproval() *Approval {
+	if x != nil {
+		return x.Approval
+	}
+	return nil
+}
+
+func (x *Node) GetMapOptions() *MapOptions {
+	if x != nil {
+		return x.MapOptions
+	}
+	return nil
+}
+
+func (x *Node) GetBranchOptions() *BranchOptions {
+	if x != nil {
+		return x.BranchOptions
+	}
+	return nil
+}
+
+func (x *Node) GetSubworkflowOptions() *SubworkflowOptions {
+	if x != nil {
+		return x.SubworkflowOptions
+	}
+	return nil
+}
+
+func (x *Node) GetRejectedEdits() []*RejectedEdit {
+	if x != nil {
+		return x.RejectedEdits
+	}
+	return nil
+}
+
+func (x *Node) GetEditDepth() int32 {
+	if x != nil {
+		return x.EditDepth
+	}
+	return 0
+}
+
+// The child workflow a SUBWORKFLOW node runs. Once the child completes, the
+// node takes on its aggregate status, and a result summarizing it is added to
+// the node's assigned task.
+type SubworkflowOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Node IDs are replaced with fresh UUIDs when the child is created.
+	Template *CreateWorkflowRequest `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	// Substituted for {{name}} placeholders in the descriptions and task goals
+	// of the template's nodes.
+	Inputs          map[string]string `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ChildWorkflowId string            `protobuf:"bytes,3,opt,name=child_workflow_id,json=childWorkflowId,proto3" json:"child_workflow_id,omitempty"` // Set once the child workflow is created
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SubworkflowOptions) Reset() {
+	*x = SubworkflowOptions{}
 	mi := &file_protos_workflow_node_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecutionOptions) String() string {
+func (x *SubworkflowOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecutionOptions) ProtoMessage() {}
+func (*SubworkflowOptions) ProtoMessage() {}
 
-func (x *ExecutionOptions) ProtoReflect() protoreflect.Message {
+func (x *SubworkflowOptions) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_workflow_node_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -317,49 +671,61 @@ func (x *ExecutionOptions) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecutionOptions.ProtoReflect.Descriptor instead.
-func (*ExecutionOptions) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubworkflowOptions.ProtoReflect.Descriptor instead.
+func (*SubworkflowOptions) Descriptor() ([]byte, []int) {
 	return file_protos_workflow_node_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *ExecutionOptions) GetTimeout() *durationpb.Duration {
+func (x *SubworkflowOptions) GetTemplate() *CreateWorkflowRequest {
 	if x != nil {
-		return x.Timeout
+		return x.Template
 	}
 	return nil
 }
 
-func (x *ExecutionOptions) GetRetryOptions() *ExecutionOptions_RetryOptions {
+func (x *SubworkflowOptions) GetInputs() map[string]string {
 	if x != nil {
-		return x.RetryOptions
+		return x.Inputs
 	}
 	return nil
 }
 
-// Placeholder for agent identity (to be defined in detail)
-type Agent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // A unique specification fro the model
-	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`                            // e.g., "Planner", "Worker", "Supervisor"
-	ModelType     string                 `protobuf:"bytes,3,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"` // e.g., "Claude-3", "GPT-4"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SubworkflowOptions) GetChildWorkflowId() string {
+	if x != nil {
+		return x.ChildWorkflowId
+	}
+	return ""
 }
 
-func (x *Agent) Reset() {
-	*x = Agent{}
+// How a MAP node expands into item nodes once it passes. Each item node is a
+// child of the MAP node and a parent of the MAP node's original children,
+// typically a REDUCE node.
+type MapOptions struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Source MapOptions_Source      `protobuf:"varint,1,opt,name=source,proto3,enum=aisociety.workflow.MapOptions_Source" json:"source,omitempty"`
+	// Copied into every item node, which gets a fresh ID and the item as its
+	// assigned task.
+	Template *Node `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	// At most this many item nodes are dispatched at once; 0 means no limit.
+	MaxConcurrency int32 `protobuf:"varint,3,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MapOptions) Reset() {
+	*x = MapOptions{}
 	mi := &file_protos_workflow_node_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Agent) String() string {
+func (x *MapOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Agent) ProtoMessage() {}
+func (*MapOptions) ProtoMessage() {}
 
-func (x *Agent) ProtoReflect() protoreflect.Message {
+func (x *MapOptions) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_workflow_node_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -371,58 +737,58 @@ func (x *Agent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
-func (*Agent) Descriptor() ([]byte, []int) {
+// Deprecated: Use MapOptions.ProtoReflect.Descriptor instead.
+func (*MapOptions) Descriptor() ([]byte, []int) {
 	return file_protos_workflow_node_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Agent) GetAgentId() string {
+func (x *MapOptions) GetSource() MapOptions_Source {
 	if x != nil {
-		return x.AgentId
+		return x.Source
 	}
-	return ""
+	return MapOptions_SUBTASKS
 }
 
-func (x *Agent) GetRole() string {
+func (x *MapOptions) GetTemplate() *Node {
 	if x != nil {
-		return x.Role
+		return x.Template
 	}
-	return ""
+	return nil
 }
 
-func (x *Agent) GetModelType() string {
+func (x *MapOptions) GetMaxConcurrency() int32 {
 	if x != nil {
-		return x.ModelType
+		return x.MaxConcurrency
 	}
-	return ""
+	return 0
 }
 
-// Task definition. A node fulfills a task, or updates tasks
-type Task struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                            // unique id for this task
-	Goal          string                 `protobuf:"bytes,2,opt,name=goal,proto3" json:"goal,omitempty"`                                        // name for the task
-	DependencyIds []string               `protobuf:"bytes,3,rep,name=dependency_ids,json=dependencyIds,proto3" json:"dependency_ids,omitempty"` // Upstream task neighbors
-	Results       []*Task_Result         `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`                                  // can be empty
-	Subtasks      []*Task                `protobuf:"bytes,6,rep,name=subtasks,proto3" json:"subtasks,omitempty"`                                // a tree of subtasks, with results
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Which children a BRANCH node takes once it passes. Children it does not
+// take are FILTERED, along with the pending nodes downstream of them.
+// Children named by no case, nor as a default, are always taken.
+type BranchOptions struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Cases           []*BranchCase          `protobuf:"bytes,1,rep,name=cases,proto3" json:"cases,omitempty"`                                              // The first case that matches is taken
+	DefaultChildIds []string               `protobuf:"bytes,2,rep,name=default_child_ids,json=defaultChildIds,proto3" json:"default_child_ids,omitempty"` // Taken if no case matches
+	TakenChildIds   []string               `protobuf:"bytes,3,rep,name=taken_child_ids,json=takenChildIds,proto3" json:"taken_child_ids,omitempty"`       // Set once the node passes
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *Task) Reset() {
-	*x = Task{}
+func (x *BranchOptions) Reset() {
+	*x = BranchOptions{}
 	mi := &file_protos_workflow_node_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Task) String() string {
+func (x *BranchOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Task) ProtoMessage() {}
+func (*BranchOptions) ProtoMessage() {}
 
-func (x *Task) ProtoReflect() protoreflect.Message {
+func (x *BranchOptions) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_workflow_node_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -434,70 +800,1823 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Task.ProtoReflect.Descriptor instead.
-func (*Task) Descriptor() ([]byte, []int) {
+// Deprecated: Use BranchOptions.ProtoReflect.Descriptor instead.
+func (*BranchOptions) Descriptor() ([]byte, []int) {
 	return file_protos_workflow_node_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Task) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *Task) GetGoal() string {
+func (x *BranchOptions) GetCases() []*BranchCase {
 	if x != nil {
-		return x.Goal
+		return x.Cases
 	}
-	return ""
+	return nil
 }
 
-func (x *Task) GetDependencyIds() []string {
+func (x *BranchOptions) GetDefaultChildIds() []string {
 	if x != nil {
-		return x.DependencyIds
+		return x.DefaultChildIds
 	}
 	return nil
 }
 
-func (x *Task) GetResults() []*Task_Result {
+func (x *BranchOptions) GetTakenChildIds() []string {
 	if x != nil {
-		return x.Results
+		return x.TakenChildIds
+	}
+	return nil
+}
+
+type BranchCase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	When          *ResultPredicate       `protobuf:"bytes,1,opt,name=when,proto3" json:"when,omitempty"`
+	ChildIds      []string               `protobuf:"bytes,2,rep,name=child_ids,json=childIds,proto3" json:"child_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BranchCase) Reset() {
+	*x = BranchCase{}
+	mi := &file_protos_workflow_node_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BranchCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BranchCase) ProtoMessage() {}
+
+func (x *BranchCase) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BranchCase.ProtoReflect.Descriptor instead.
+func (*BranchCase) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BranchCase) GetWhen() *ResultPredicate {
+	if x != nil {
+		return x.When
+	}
+	return nil
+}
+
+func (x *BranchCase) GetChildIds() []string {
+	if x != nil {
+		return x.ChildIds
+	}
+	return nil
+}
+
+// Matches the latest result of a node's assigned task. Every field that is
+// set must match.
+type ResultPredicate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	NodeId          string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`                                                                   // The node whose result is tested; defaults to the BRANCH node's first parent
+	Status          Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=aisociety.workflow.Status" json:"status,omitempty"`                                                 // The result's status, or the node's own status if it has no result
+	SummaryContains string                 `protobuf:"bytes,3,opt,name=summary_contains,json=summaryContains,proto3" json:"summary_contains,omitempty"`                                        // Case-insensitive substring of the result's summary
+	Artifacts       map[string]string      `protobuf:"bytes,4,rep,name=artifacts,proto3" json:"artifacts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Artifacts the result must have; an empty value matches any value
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResultPredicate) Reset() {
+	*x = ResultPredicate{}
+	mi := &file_protos_workflow_node_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultPredicate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultPredicate) ProtoMessage() {}
+
+func (x *ResultPredicate) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultPredicate.ProtoReflect.Descriptor instead.
+func (*ResultPredicate) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResultPredicate) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ResultPredicate) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNKNOWN
+}
+
+func (x *ResultPredicate) GetSummaryContains() string {
+	if x != nil {
+		return x.SummaryContains
+	}
+	return ""
+}
+
+func (x *ResultPredicate) GetArtifacts() map[string]string {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+// A human decision on an APPROVAL node
+type Approval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Approved      bool                   `protobuf:"varint,1,opt,name=approved,proto3" json:"approved,omitempty"`
+	Approver      *Caller                `protobuf:"bytes,2,opt,name=approver,proto3" json:"approver,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	DecidedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=decided_at,json=decidedAt,proto3" json:"decided_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Approval) Reset() {
+	*x = Approval{}
+	mi := &file_protos_workflow_node_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Approval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Approval) ProtoMessage() {}
+
+func (x *Approval) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Approval.ProtoReflect.Descriptor instead.
+func (*Approval) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Approval) GetApproved() bool {
+	if x != nil {
+		return x.Approved
+	}
+	return false
+}
+
+func (x *Approval) GetApprover() *Caller {
+	if x != nil {
+		return x.Approver
+	}
+	return nil
+}
+
+func (x *Approval) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Approval) GetDecidedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DecidedAt
+	}
+	return nil
+}
+
+type ExecutionOptions struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	Timeout       *durationpb.Duration           `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	RetryOptions  *ExecutionOptions_RetryOptions `protobuf:"bytes,2,opt,name=retry_options,json=retryOptions,proto3" json:"retry_options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecutionOptions) Reset() {
+	*x = ExecutionOptions{}
+	mi := &file_protos_workflow_node_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionOptions) ProtoMessage() {}
+
+func (x *ExecutionOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionOptions.ProtoReflect.Descriptor instead.
+func (*ExecutionOptions) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExecutionOptions) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *ExecutionOptions) GetRetryOptions() *ExecutionOptions_RetryOptions {
+	if x != nil {
+		return x.RetryOptions
+	}
+	return nil
+}
+
+// Outcome of a single attempt to execute a node.
+type Attempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`                                // 1-based attempt number
+	Status        Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=aisociety.workflow.Status" json:"status,omitempty"` // status the attempt ended with
+	Started       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started,proto3" json:"started,omitempty"`
+	Finished      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"` // error returned by the Node Service, if any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attempt) Reset() {
+	*x = Attempt{}
+	mi := &file_protos_workflow_node_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attempt) ProtoMessage() {}
+
+func (x *Attempt) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attempt.ProtoReflect.Descriptor instead.
+func (*Attempt) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Attempt) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Attempt) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNKNOWN
+}
+
+func (x *Attempt) GetStarted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Started
+	}
+	return nil
+}
+
+func (x *Attempt) GetFinished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Finished
+	}
+	return nil
+}
+
+func (x *Attempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Placeholder for agent identity (to be defined in detail)
+type Agent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // A unique specification fro the model
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`                            // e.g., "Planner", "Worker", "Supervisor"
+	ModelType     string                 `protobuf:"bytes,3,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"` // e.g., "Claude-3", "GPT-4"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Agent) Reset() {
+	*x = Agent{}
+	mi := &file_protos_workflow_node_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Agent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Agent) ProtoMessage() {}
+
+func (x *Agent) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Agent.ProtoReflect.Descriptor instead.
+func (*Agent) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Agent) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Agent) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Agent) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+// Task definition. A node fulfills a task, or updates tasks
+type Task struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                            // unique id for this task
+	Goal          string                 `protobuf:"bytes,2,opt,name=goal,proto3" json:"goal,omitempty"`                                        // name for the task
+	DependencyIds []string               `protobuf:"bytes,3,rep,name=dependency_ids,json=dependencyIds,proto3" json:"dependency_ids,omitempty"` // Upstream task neighbors
+	Results       []*Task_Result         `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`                                  // can be empty
+	Subtasks      []*Task                `protobuf:"bytes,6,rep,name=subtasks,proto3" json:"subtasks,omitempty"`                                // a tree of subtasks, with results
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_protos_workflow_node_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetGoal() string {
+	if x != nil {
+		return x.Goal
+	}
+	return ""
+}
+
+func (x *Task) GetDependencyIds() []string {
+	if x != nil {
+		return x.DependencyIds
+	}
+	return nil
+}
+
+func (x *Task) GetResults() []*Task_Result {
+	if x != nil {
+		return x.Results
 	}
 	return nil
 }
 
 func (x *Task) GetSubtasks() []*Task {
 	if x != nil {
-		return x.Subtasks
+		return x.Subtasks
+	}
+	return nil
+}
+
+// Placeholder for node status
+type NodeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastUpdated   int64                  `protobuf:"varint,1,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // timestamp (epoch millis)
+	Progress      []*NodeStatus_Update   `protobuf:"bytes,2,rep,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeStatus) Reset() {
+	*x = NodeStatus{}
+	mi := &file_protos_workflow_node_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStatus) ProtoMessage() {}
+
+func (x *NodeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStatus.ProtoReflect.Descriptor instead.
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *NodeStatus) GetLastUpdated() int64 {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetProgress() []*NodeStatus_Update {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type NodeEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          NodeEdit_Type          `protobuf:"varint,5,opt,name=type,proto3,enum=aisociety.workflow.NodeEdit_Type" json:"type,omitempty"` // Type of edit
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                              // When the edit was made
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`                          // Description of the edit
+	Node          *Node                  `protobuf:"bytes,6,opt,name=node,proto3" json:"node,omitempty"`                                        // New node data (for insert/update)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeEdit) Reset() {
+	*x = NodeEdit{}
+	mi := &file_protos_workflow_node_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeEdit) ProtoMessage() {}
+
+func (x *NodeEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeEdit.ProtoReflect.Descriptor instead.
+func (*NodeEdit) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NodeEdit) GetType() NodeEdit_Type {
+	if x != nil {
+		return x.Type
+	}
+	return NodeEdit_UNKNOWN
+}
+
+func (x *NodeEdit) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *NodeEdit) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *NodeEdit) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+// An edit produced by an agent that was not applied, and why.
+type RejectedEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edit          *NodeEdit              `protobuf:"bytes,1,opt,name=edit,proto3" json:"edit,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	RejectedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=rejected_at,json=rejectedAt,proto3" json:"rejected_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectedEdit) Reset() {
+	*x = RejectedEdit{}
+	mi := &file_protos_workflow_node_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectedEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedEdit) ProtoMessage() {}
+
+func (x *RejectedEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedEdit.ProtoReflect.Descriptor instead.
+func (*RejectedEdit) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RejectedEdit) GetEdit() *NodeEdit {
+	if x != nil {
+		return x.Edit
+	}
+	return nil
+}
+
+func (x *RejectedEdit) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RejectedEdit) GetRejectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RejectedAt
+	}
+	return nil
+}
+
+// WorkflowService messages
+type CreateWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	Priority      int32                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`                                                                      // Scheduling priority of every node in the workflow; higher is dispatched first
+	Owner         string                 `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`                                                                             // Who fair-share scheduling accounts the workflow to; defaults to caller.agent
+	FailurePolicy FailurePolicy          `protobuf:"varint,5,opt,name=failure_policy,json=failurePolicy,proto3,enum=aisociety.workflow.FailurePolicy" json:"failure_policy,omitempty"` // Applies to nodes that do not set their own; defaults to SKIP_DESCENDANTS
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkflowRequest) Reset() {
+	*x = CreateWorkflowRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkflowRequest) ProtoMessage() {}
+
+func (x *CreateWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateWorkflowRequest) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *CreateWorkflowRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+func (x *CreateWorkflowRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CreateWorkflowRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *CreateWorkflowRequest) GetFailurePolicy() FailurePolicy {
+	if x != nil {
+		return x.FailurePolicy
+	}
+	return FailurePolicy_FAILURE_POLICY_UNSPECIFIED
+}
+
+type CreateWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkflowResponse) Reset() {
+	*x = CreateWorkflowResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkflowResponse) ProtoMessage() {}
+
+func (x *CreateWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateWorkflowResponse) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+type GetWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkflowRequest) Reset() {
+	*x = GetWorkflowRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowRequest) ProtoMessage() {}
+
+func (x *GetWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+type GetWorkflowResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Nodes            []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Status           Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=aisociety.workflow.Status" json:"status,omitempty"` // Aggregate status, set once the workflow completes
+	CompletedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	FailurePolicy    FailurePolicy          `protobuf:"varint,4,opt,name=failure_policy,json=failurePolicy,proto3,enum=aisociety.workflow.FailurePolicy" json:"failure_policy,omitempty"`
+	Paused           bool                   `protobuf:"varint,5,opt,name=paused,proto3" json:"paused,omitempty"`                                              // No new nodes are dispatched until the workflow is resumed
+	ParentWorkflowId string                 `protobuf:"bytes,6,opt,name=parent_workflow_id,json=parentWorkflowId,proto3" json:"parent_workflow_id,omitempty"` // Set on workflows started by a SUBWORKFLOW node
+	ParentNodeId     string                 `protobuf:"bytes,7,opt,name=parent_node_id,json=parentNodeId,proto3" json:"parent_node_id,omitempty"`             // The SUBWORKFLOW node that started this workflow
+	ChildWorkflowIds []string               `protobuf:"bytes,8,rep,name=child_workflow_ids,json=childWorkflowIds,proto3" json:"child_workflow_ids,omitempty"` // Workflows started by this workflow's SUBWORKFLOW nodes
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetWorkflowResponse) Reset() {
+	*x = GetWorkflowResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowResponse) ProtoMessage() {}
+
+func (x *GetWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*GetWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetWorkflowResponse) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *GetWorkflowResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNKNOWN
+}
+
+func (x *GetWorkflowResponse) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *GetWorkflowResponse) GetFailurePolicy() FailurePolicy {
+	if x != nil {
+		return x.FailurePolicy
+	}
+	return FailurePolicy_FAILURE_POLICY_UNSPECIFIED
+}
+
+func (x *GetWorkflowResponse) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *GetWorkflowResponse) GetParentWorkflowId() string {
+	if x != nil {
+		return x.ParentWorkflowId
+	}
+	return ""
+}
+
+func (x *GetWorkflowResponse) GetParentNodeId() string {
+	if x != nil {
+		return x.ParentNodeId
+	}
+	return ""
+}
+
+func (x *GetWorkflowResponse) GetChildWorkflowIds() []string {
+	if x != nil {
+		return x.ChildWorkflowIds
+	}
+	return nil
+}
+
+// Payload of the WorkflowCompleted event.
+type WorkflowCompletedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Status        Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=aisociety.workflow.Status" json:"status,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowCompletedEvent) Reset() {
+	*x = WorkflowCompletedEvent{}
+	mi := &file_protos_workflow_node_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowCompletedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowCompletedEvent) ProtoMessage() {}
+
+func (x *WorkflowCompletedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowCompletedEvent.ProtoReflect.Descriptor instead.
+func (*WorkflowCompletedEvent) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WorkflowCompletedEvent) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *WorkflowCompletedEvent) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNKNOWN
+}
+
+func (x *WorkflowCompletedEvent) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type ListWorkflowsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkflowsRequest) Reset() {
+	*x = ListWorkflowsRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkflowsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkflowsRequest) ProtoMessage() {}
+
+func (x *ListWorkflowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkflowsRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkflowsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{19}
+}
+
+type ListWorkflowsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowIds   []string               `protobuf:"bytes,1,rep,name=workflow_ids,json=workflowIds,proto3" json:"workflow_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkflowsResponse) Reset() {
+	*x = ListWorkflowsResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkflowsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkflowsResponse) ProtoMessage() {}
+
+func (x *ListWorkflowsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkflowsResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkflowsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListWorkflowsResponse) GetWorkflowIds() []string {
+	if x != nil {
+		return x.WorkflowIds
+	}
+	return nil
+}
+
+type UpdateWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Nodes         []*Node                `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWorkflowRequest) Reset() {
+	*x = UpdateWorkflowRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWorkflowRequest) ProtoMessage() {}
+
+func (x *UpdateWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*UpdateWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *UpdateWorkflowRequest) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *UpdateWorkflowRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+type UpdateWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWorkflowResponse) Reset() {
+	*x = UpdateWorkflowResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWorkflowResponse) ProtoMessage() {}
+
+func (x *UpdateWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*UpdateWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateWorkflowResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// NodeService messages
+type GetNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeRequest) Reset() {
+	*x = GetNodeRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeRequest) ProtoMessage() {}
+
+func (x *GetNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetNodeRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *GetNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type GetNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Node          *Node                  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeResponse) Reset() {
+	*x = GetNodeResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeResponse) ProtoMessage() {}
+
+func (x *GetNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetNodeResponse) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+// *
+// Identifies the caller making a request, including agent identity and worknode context.
+type Caller struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	WorknodeId    string                 `protobuf:"bytes,2,opt,name=worknode_id,json=worknodeId,proto3" json:"worknode_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Caller) Reset() {
+	*x = Caller{}
+	mi := &file_protos_workflow_node_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Caller) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Caller) ProtoMessage() {}
+
+func (x *Caller) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Caller.ProtoReflect.Descriptor instead.
+func (*Caller) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Caller) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *Caller) GetWorknodeId() string {
+	if x != nil {
+		return x.WorknodeId
+	}
+	return ""
+}
+
+type UpdateNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Node          *Node                  `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNodeRequest) Reset() {
+	*x = UpdateNodeRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNodeRequest) ProtoMessage() {}
+
+func (x *UpdateNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNodeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNodeRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UpdateNodeRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *UpdateNodeRequest) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *UpdateNodeRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+type UpdateNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNodeResponse) Reset() {
+	*x = UpdateNodeResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNodeResponse) ProtoMessage() {}
+
+func (x *UpdateNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNodeResponse.ProtoReflect.Descriptor instead.
+func (*UpdateNodeResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UpdateNodeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type CancelWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Recorded in each canceled node's status log
+	Caller        *Caller                `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelWorkflowRequest) Reset() {
+	*x = CancelWorkflowRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelWorkflowRequest) ProtoMessage() {}
+
+func (x *CancelWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*CancelWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CancelWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *CancelWorkflowRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelWorkflowRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
 	}
 	return nil
 }
 
-// Placeholder for node status
-type NodeStatus struct {
+type CancelWorkflowResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CanceledNodeIds []string               `protobuf:"bytes,1,rep,name=canceled_node_ids,json=canceledNodeIds,proto3" json:"canceled_node_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CancelWorkflowResponse) Reset() {
+	*x = CancelWorkflowResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelWorkflowResponse) ProtoMessage() {}
+
+func (x *CancelWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*CancelWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CancelWorkflowResponse) GetCanceledNodeIds() []string {
+	if x != nil {
+		return x.CanceledNodeIds
+	}
+	return nil
+}
+
+type CancelNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LastUpdated   int64                  `protobuf:"varint,1,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // timestamp (epoch millis)
-	Progress      []*NodeStatus_Update   `protobuf:"bytes,2,rep,name=progress,proto3" json:"progress,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Recorded in each canceled node's status log
+	Caller        *Caller                `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NodeStatus) Reset() {
-	*x = NodeStatus{}
-	mi := &file_protos_workflow_node_proto_msgTypes[4]
+func (x *CancelNodeRequest) Reset() {
+	*x = CancelNodeRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelNodeRequest) ProtoMessage() {}
+
+func (x *CancelNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelNodeRequest.ProtoReflect.Descriptor instead.
+func (*CancelNodeRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CancelNodeRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *CancelNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *CancelNodeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelNodeRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+type CancelNodeResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CanceledNodeIds []string               `protobuf:"bytes,1,rep,name=canceled_node_ids,json=canceledNodeIds,proto3" json:"canceled_node_ids,omitempty"` // Empty if the node had already finished
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CancelNodeResponse) Reset() {
+	*x = CancelNodeResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelNodeResponse) ProtoMessage() {}
+
+func (x *CancelNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelNodeResponse.ProtoReflect.Descriptor instead.
+func (*CancelNodeResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CancelNodeResponse) GetCanceledNodeIds() []string {
+	if x != nil {
+		return x.CanceledNodeIds
+	}
+	return nil
+}
+
+type PauseWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseWorkflowRequest) Reset() {
+	*x = PauseWorkflowRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseWorkflowRequest) ProtoMessage() {}
+
+func (x *PauseWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*PauseWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PauseWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *PauseWorkflowRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+type PauseWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseWorkflowResponse) Reset() {
+	*x = PauseWorkflowResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseWorkflowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseWorkflowResponse) ProtoMessage() {}
+
+func (x *PauseWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*PauseWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *PauseWorkflowResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ResumeWorkflowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeWorkflowRequest) Reset() {
+	*x = ResumeWorkflowRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeWorkflowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeWorkflowRequest) ProtoMessage() {}
+
+func (x *ResumeWorkflowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeWorkflowRequest.ProtoReflect.Descriptor instead.
+func (*ResumeWorkflowRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ResumeWorkflowRequest) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ResumeWorkflowRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+type ResumeWorkflowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeWorkflowResponse) Reset() {
+	*x = ResumeWorkflowResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NodeStatus) String() string {
+func (x *ResumeWorkflowResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeStatus) ProtoMessage() {}
+func (*ResumeWorkflowResponse) ProtoMessage() {}
 
-func (x *NodeStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[4]
+func (x *ResumeWorkflowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,50 +2627,48 @@ func (x *NodeStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeStatus.ProtoReflect.Descriptor instead.
-func (*NodeStatus) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{4}
-}
-
-func (x *NodeStatus) GetLastUpdated() int64 {
-	if x != nil {
-		return x.LastUpdated
-	}
-	return 0
+// Deprecated: Use ResumeWorkflowResponse.ProtoReflect.Descriptor instead.
+func (*ResumeWorkflowResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *NodeStatus) GetProgress() []*NodeStatus_Update {
+func (x *ResumeWorkflowResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Progress
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type NodeEdit struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          NodeEdit_Type          `protobuf:"varint,5,opt,name=type,proto3,enum=aisociety.workflow.NodeEdit_Type" json:"type,omitempty"` // Type of edit
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                              // When the edit was made
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`                          // Description of the edit
-	Node          *Node                  `protobuf:"bytes,6,opt,name=node,proto3" json:"node,omitempty"`                                        // New node data (for insert/update)
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// A cron schedule that starts a new workflow from a template on every run
+type Trigger struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TriggerId       string                 `protobuf:"bytes,1,opt,name=trigger_id,json=triggerId,proto3" json:"trigger_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Schedule        string                 `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"` // Cron expression evaluated in UTC, e.g. "0 9 * * MON-FRI" or "@daily"
+	Template        *CreateWorkflowRequest `protobuf:"bytes,4,opt,name=template,proto3" json:"template,omitempty"` // Node IDs are replaced with fresh UUIDs on every run
+	MissedRunPolicy MissedRunPolicy        `protobuf:"varint,5,opt,name=missed_run_policy,json=missedRunPolicy,proto3,enum=aisociety.workflow.MissedRunPolicy" json:"missed_run_policy,omitempty"`
+	NextRunAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	LastRunAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	LastWorkflowId  string                 `protobuf:"bytes,8,opt,name=last_workflow_id,json=lastWorkflowId,proto3" json:"last_workflow_id,omitempty"` // The workflow started by the last run
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *NodeEdit) Reset() {
-	*x = NodeEdit{}
-	mi := &file_protos_workflow_node_proto_msgTypes[5]
+func (x *Trigger) Reset() {
+	*x = Trigger{}
+	mi := &file_protos_workflow_node_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NodeEdit) String() string {
+func (x *Trigger) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeEdit) ProtoMessage() {}
+func (*Trigger) ProtoMessage() {}
 
-func (x *NodeEdit) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[5]
+func (x *Trigger) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -562,63 +2679,90 @@ func (x *NodeEdit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeEdit.ProtoReflect.Descriptor instead.
-func (*NodeEdit) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Trigger.ProtoReflect.Descriptor instead.
+func (*Trigger) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *NodeEdit) GetType() NodeEdit_Type {
+func (x *Trigger) GetTriggerId() string {
 	if x != nil {
-		return x.Type
+		return x.TriggerId
 	}
-	return NodeEdit_UNKNOWN
+	return ""
 }
 
-func (x *NodeEdit) GetTimestamp() *timestamppb.Timestamp {
+func (x *Trigger) GetName() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *NodeEdit) GetDescription() string {
+func (x *Trigger) GetSchedule() string {
 	if x != nil {
-		return x.Description
+		return x.Schedule
 	}
 	return ""
 }
 
-func (x *NodeEdit) GetNode() *Node {
+func (x *Trigger) GetTemplate() *CreateWorkflowRequest {
 	if x != nil {
-		return x.Node
+		return x.Template
 	}
 	return nil
 }
 
-// WorkflowService messages
-type CreateWorkflowRequest struct {
+func (x *Trigger) GetMissedRunPolicy() MissedRunPolicy {
+	if x != nil {
+		return x.MissedRunPolicy
+	}
+	return MissedRunPolicy_MISSED_RUN_POLICY_UNSPECIFIED
+}
+
+func (x *Trigger) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+func (x *Trigger) GetLastRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return nil
+}
+
+func (x *Trigger) GetLastWorkflowId() string {
+	if x != nil {
+		return x.LastWorkflowId
+	}
+	return ""
+}
+
+type CreateTriggerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Nodes         []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Trigger       *Trigger               `protobuf:"bytes,1,opt,name=trigger,proto3" json:"trigger,omitempty"` // trigger_id, next_run_at and the last run are ignored
 	Caller        *Caller                `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateWorkflowRequest) Reset() {
-	*x = CreateWorkflowRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[6]
+func (x *CreateTriggerRequest) Reset() {
+	*x = CreateTriggerRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateWorkflowRequest) String() string {
+func (x *CreateTriggerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateWorkflowRequest) ProtoMessage() {}
+func (*CreateTriggerRequest) ProtoMessage() {}
 
-func (x *CreateWorkflowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[6]
+func (x *CreateTriggerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -629,47 +2773,48 @@ func (x *CreateWorkflowRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateWorkflowRequest.ProtoReflect.Descriptor instead.
-func (*CreateWorkflowRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use CreateTriggerRequest.ProtoReflect.Descriptor instead.
+func (*CreateTriggerRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *CreateWorkflowRequest) GetNodes() []*Node {
+func (x *CreateTriggerRequest) GetTrigger() *Trigger {
 	if x != nil {
-		return x.Nodes
+		return x.Trigger
 	}
 	return nil
 }
 
-func (x *CreateWorkflowRequest) GetCaller() *Caller {
+func (x *CreateTriggerRequest) GetCaller() *Caller {
 	if x != nil {
 		return x.Caller
 	}
 	return nil
 }
 
-type CreateWorkflowResponse struct {
+type CreateTriggerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	TriggerId     string                 `protobuf:"bytes,1,opt,name=trigger_id,json=triggerId,proto3" json:"trigger_id,omitempty"`
+	NextRunAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateWorkflowResponse) Reset() {
-	*x = CreateWorkflowResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[7]
+func (x *CreateTriggerResponse) Reset() {
+	*x = CreateTriggerResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateWorkflowResponse) String() string {
+func (x *CreateTriggerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateWorkflowResponse) ProtoMessage() {}
+func (*CreateTriggerResponse) ProtoMessage() {}
 
-func (x *CreateWorkflowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[7]
+func (x *CreateTriggerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -680,40 +2825,46 @@ func (x *CreateWorkflowResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateWorkflowResponse.ProtoReflect.Descriptor instead.
-func (*CreateWorkflowResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CreateTriggerResponse.ProtoReflect.Descriptor instead.
+func (*CreateTriggerResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *CreateWorkflowResponse) GetWorkflowId() string {
+func (x *CreateTriggerResponse) GetTriggerId() string {
 	if x != nil {
-		return x.WorkflowId
+		return x.TriggerId
 	}
 	return ""
 }
 
-type GetWorkflowRequest struct {
+func (x *CreateTriggerResponse) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+type ListTriggersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetWorkflowRequest) Reset() {
-	*x = GetWorkflowRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[8]
+func (x *ListTriggersRequest) Reset() {
+	*x = ListTriggersRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetWorkflowRequest) String() string {
+func (x *ListTriggersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetWorkflowRequest) ProtoMessage() {}
+func (*ListTriggersRequest) ProtoMessage() {}
 
-func (x *GetWorkflowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[8]
+func (x *ListTriggersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -724,40 +2875,33 @@ func (x *GetWorkflowRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetWorkflowRequest.ProtoReflect.Descriptor instead.
-func (*GetWorkflowRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *GetWorkflowRequest) GetWorkflowId() string {
-	if x != nil {
-		return x.WorkflowId
-	}
-	return ""
+// Deprecated: Use ListTriggersRequest.ProtoReflect.Descriptor instead.
+func (*ListTriggersRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{39}
 }
 
-type GetWorkflowResponse struct {
+type ListTriggersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Nodes         []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Triggers      []*Trigger             `protobuf:"bytes,1,rep,name=triggers,proto3" json:"triggers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetWorkflowResponse) Reset() {
-	*x = GetWorkflowResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[9]
+func (x *ListTriggersResponse) Reset() {
+	*x = ListTriggersResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetWorkflowResponse) String() string {
+func (x *ListTriggersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetWorkflowResponse) ProtoMessage() {}
+func (*ListTriggersResponse) ProtoMessage() {}
 
-func (x *GetWorkflowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[9]
+func (x *ListTriggersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -768,39 +2912,41 @@ func (x *GetWorkflowResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetWorkflowResponse.ProtoReflect.Descriptor instead.
-func (*GetWorkflowResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ListTriggersResponse.ProtoReflect.Descriptor instead.
+func (*ListTriggersResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *GetWorkflowResponse) GetNodes() []*Node {
+func (x *ListTriggersResponse) GetTriggers() []*Trigger {
 	if x != nil {
-		return x.Nodes
+		return x.Triggers
 	}
 	return nil
 }
 
-type ListWorkflowsRequest struct {
+type DeleteTriggerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	TriggerId     string                 `protobuf:"bytes,1,opt,name=trigger_id,json=triggerId,proto3" json:"trigger_id,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListWorkflowsRequest) Reset() {
-	*x = ListWorkflowsRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[10]
+func (x *DeleteTriggerRequest) Reset() {
+	*x = DeleteTriggerRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListWorkflowsRequest) String() string {
+func (x *DeleteTriggerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListWorkflowsRequest) ProtoMessage() {}
+func (*DeleteTriggerRequest) ProtoMessage() {}
 
-func (x *ListWorkflowsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[10]
+func (x *DeleteTriggerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -811,33 +2957,47 @@ func (x *ListWorkflowsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListWorkflowsRequest.ProtoReflect.Descriptor instead.
-func (*ListWorkflowsRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use DeleteTriggerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTriggerRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{41}
 }
 
-type ListWorkflowsResponse struct {
+func (x *DeleteTriggerRequest) GetTriggerId() string {
+	if x != nil {
+		return x.TriggerId
+	}
+	return ""
+}
+
+func (x *DeleteTriggerRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
+	}
+	return nil
+}
+
+type DeleteTriggerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowIds   []string               `protobuf:"bytes,1,rep,name=workflow_ids,json=workflowIds,proto3" json:"workflow_ids,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListWorkflowsResponse) Reset() {
-	*x = ListWorkflowsResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[11]
+func (x *DeleteTriggerResponse) Reset() {
+	*x = DeleteTriggerResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListWorkflowsResponse) String() string {
+func (x *DeleteTriggerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListWorkflowsResponse) ProtoMessage() {}
+func (*DeleteTriggerResponse) ProtoMessage() {}
 
-func (x *ListWorkflowsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[11]
+func (x *DeleteTriggerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -848,42 +3008,40 @@ func (x *ListWorkflowsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListWorkflowsResponse.ProtoReflect.Descriptor instead.
-func (*ListWorkflowsResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use DeleteTriggerResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTriggerResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *ListWorkflowsResponse) GetWorkflowIds() []string {
+func (x *DeleteTriggerResponse) GetSuccess() bool {
 	if x != nil {
-		return x.WorkflowIds
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type UpdateWorkflowRequest struct {
+type ListPendingApprovalsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
-	Nodes         []*Node                `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
-	Caller        *Caller                `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"` // Optional; lists every workflow's approvals if empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateWorkflowRequest) Reset() {
-	*x = UpdateWorkflowRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[12]
+func (x *ListPendingApprovalsRequest) Reset() {
+	*x = ListPendingApprovalsRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateWorkflowRequest) String() string {
+func (x *ListPendingApprovalsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateWorkflowRequest) ProtoMessage() {}
+func (*ListPendingApprovalsRequest) ProtoMessage() {}
 
-func (x *UpdateWorkflowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[12]
+func (x *ListPendingApprovalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -894,54 +3052,42 @@ func (x *UpdateWorkflowRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateWorkflowRequest.ProtoReflect.Descriptor instead.
-func (*UpdateWorkflowRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ListPendingApprovalsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingApprovalsRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *UpdateWorkflowRequest) GetWorkflowId() string {
+func (x *ListPendingApprovalsRequest) GetWorkflowId() string {
 	if x != nil {
 		return x.WorkflowId
 	}
 	return ""
 }
 
-func (x *UpdateWorkflowRequest) GetNodes() []*Node {
-	if x != nil {
-		return x.Nodes
-	}
-	return nil
-}
-
-func (x *UpdateWorkflowRequest) GetCaller() *Caller {
-	if x != nil {
-		return x.Caller
-	}
-	return nil
-}
-
-type UpdateWorkflowResponse struct {
+type PendingApproval struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Node          *Node                  `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	WaitingSince  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=waiting_since,json=waitingSince,proto3" json:"waiting_since,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateWorkflowResponse) Reset() {
-	*x = UpdateWorkflowResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[13]
+func (x *PendingApproval) Reset() {
+	*x = PendingApproval{}
+	mi := &file_protos_workflow_node_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateWorkflowResponse) String() string {
+func (x *PendingApproval) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateWorkflowResponse) ProtoMessage() {}
+func (*PendingApproval) ProtoMessage() {}
 
-func (x *UpdateWorkflowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[13]
+func (x *PendingApproval) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -952,42 +3098,54 @@ func (x *UpdateWorkflowResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateWorkflowResponse.ProtoReflect.Descriptor instead.
-func (*UpdateWorkflowResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use PendingApproval.ProtoReflect.Descriptor instead.
+func (*PendingApproval) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *UpdateWorkflowResponse) GetSuccess() bool {
+func (x *PendingApproval) GetWorkflowId() string {
 	if x != nil {
-		return x.Success
+		return x.WorkflowId
 	}
-	return false
+	return ""
 }
 
-// NodeService messages
-type GetNodeRequest struct {
+func (x *PendingApproval) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *PendingApproval) GetWaitingSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WaitingSince
+	}
+	return nil
+}
+
+type ListPendingApprovalsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
-	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Approvals     []*PendingApproval     `protobuf:"bytes,1,rep,name=approvals,proto3" json:"approvals,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNodeRequest) Reset() {
-	*x = GetNodeRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[14]
+func (x *ListPendingApprovalsResponse) Reset() {
+	*x = ListPendingApprovalsResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNodeRequest) String() string {
+func (x *ListPendingApprovalsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNodeRequest) ProtoMessage() {}
+func (*ListPendingApprovalsResponse) ProtoMessage() {}
 
-func (x *GetNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[14]
+func (x *ListPendingApprovalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -998,47 +3156,43 @@ func (x *GetNodeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNodeRequest.ProtoReflect.Descriptor instead.
-func (*GetNodeRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *GetNodeRequest) GetWorkflowId() string {
-	if x != nil {
-		return x.WorkflowId
-	}
-	return ""
+// Deprecated: Use ListPendingApprovalsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingApprovalsResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *GetNodeRequest) GetNodeId() string {
+func (x *ListPendingApprovalsResponse) GetApprovals() []*PendingApproval {
 	if x != nil {
-		return x.NodeId
+		return x.Approvals
 	}
-	return ""
+	return nil
 }
 
-type GetNodeResponse struct {
+type ApproveNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Node          *Node                  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"` // Recorded as the approver
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNodeResponse) Reset() {
-	*x = GetNodeResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[15]
+func (x *ApproveNodeRequest) Reset() {
+	*x = ApproveNodeRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNodeResponse) String() string {
+func (x *ApproveNodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNodeResponse) ProtoMessage() {}
+func (*ApproveNodeRequest) ProtoMessage() {}
 
-func (x *GetNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[15]
+func (x *ApproveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1049,43 +3203,61 @@ func (x *GetNodeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNodeResponse.ProtoReflect.Descriptor instead.
-func (*GetNodeResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ApproveNodeRequest.ProtoReflect.Descriptor instead.
+func (*ApproveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *GetNodeResponse) GetNode() *Node {
+func (x *ApproveNodeRequest) GetWorkflowId() string {
 	if x != nil {
-		return x.Node
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ApproveNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ApproveNodeRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *ApproveNodeRequest) GetCaller() *Caller {
+	if x != nil {
+		return x.Caller
 	}
 	return nil
 }
 
-// *
-// Identifies the caller making a request, including agent identity and worknode context.
-type Caller struct {
+type ApproveNodeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
-	WorknodeId    string                 `protobuf:"bytes,2,opt,name=worknode_id,json=worknodeId,proto3" json:"worknode_id,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Caller) Reset() {
-	*x = Caller{}
-	mi := &file_protos_workflow_node_proto_msgTypes[16]
+func (x *ApproveNodeResponse) Reset() {
+	*x = ApproveNodeResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Caller) String() string {
+func (x *ApproveNodeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Caller) ProtoMessage() {}
+func (*ApproveNodeResponse) ProtoMessage() {}
 
-func (x *Caller) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[16]
+func (x *ApproveNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1096,49 +3268,43 @@ func (x *Caller) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Caller.ProtoReflect.Descriptor instead.
-func (*Caller) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *Caller) GetAgent() string {
-	if x != nil {
-		return x.Agent
-	}
-	return ""
+// Deprecated: Use ApproveNodeResponse.ProtoReflect.Descriptor instead.
+func (*ApproveNodeResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *Caller) GetWorknodeId() string {
+func (x *ApproveNodeResponse) GetSuccess() bool {
 	if x != nil {
-		return x.WorknodeId
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-type UpdateNodeRequest struct {
+type RejectNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkflowId    string                 `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
-	Node          *Node                  `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
-	Caller        *Caller                `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Caller        *Caller                `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"` // Recorded as the approver
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateNodeRequest) Reset() {
-	*x = UpdateNodeRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[17]
+func (x *RejectNodeRequest) Reset() {
+	*x = RejectNodeRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateNodeRequest) String() string {
+func (x *RejectNodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateNodeRequest) ProtoMessage() {}
+func (*RejectNodeRequest) ProtoMessage() {}
 
-func (x *UpdateNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[17]
+func (x *RejectNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1149,54 +3315,61 @@ func (x *UpdateNodeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateNodeRequest.ProtoReflect.Descriptor instead.
-func (*UpdateNodeRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use RejectNodeRequest.ProtoReflect.Descriptor instead.
+func (*RejectNodeRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *UpdateNodeRequest) GetWorkflowId() string {
+func (x *RejectNodeRequest) GetWorkflowId() string {
 	if x != nil {
 		return x.WorkflowId
 	}
 	return ""
 }
 
-func (x *UpdateNodeRequest) GetNode() *Node {
+func (x *RejectNodeRequest) GetNodeId() string {
 	if x != nil {
-		return x.Node
+		return x.NodeId
 	}
-	return nil
+	return ""
 }
 
-func (x *UpdateNodeRequest) GetCaller() *Caller {
+func (x *RejectNodeRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *RejectNodeRequest) GetCaller() *Caller {
 	if x != nil {
 		return x.Caller
 	}
 	return nil
 }
 
-type UpdateNodeResponse struct {
+type RejectNodeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateNodeResponse) Reset() {
-	*x = UpdateNodeResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[18]
+func (x *RejectNodeResponse) Reset() {
+	*x = RejectNodeResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateNodeResponse) String() string {
+func (x *RejectNodeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateNodeResponse) ProtoMessage() {}
+func (*RejectNodeResponse) ProtoMessage() {}
 
-func (x *UpdateNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[18]
+func (x *RejectNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1207,12 +3380,12 @@ func (x *UpdateNodeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateNodeResponse.ProtoReflect.Descriptor instead.
-func (*UpdateNodeResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use RejectNodeResponse.ProtoReflect.Descriptor instead.
+func (*RejectNodeResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *UpdateNodeResponse) GetSuccess() bool {
+func (x *RejectNodeResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
@@ -1235,7 +3408,7 @@ type ExecuteNodeRequest struct {
 
 func (x *ExecuteNodeRequest) Reset() {
 	*x = ExecuteNodeRequest{}
-	mi := &file_protos_workflow_node_proto_msgTypes[19]
+	mi := &file_protos_workflow_node_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1247,7 +3420,7 @@ func (x *ExecuteNodeRequest) String() string {
 func (*ExecuteNodeRequest) ProtoMessage() {}
 
 func (x *ExecuteNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[19]
+	mi := &file_protos_workflow_node_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1260,7 +3433,7 @@ func (x *ExecuteNodeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteNodeRequest.ProtoReflect.Descriptor instead.
 func (*ExecuteNodeRequest) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{19}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *ExecuteNodeRequest) GetWorkflowId() string {
@@ -1307,7 +3480,7 @@ type ExecuteNodeResponse struct {
 
 func (x *ExecuteNodeResponse) Reset() {
 	*x = ExecuteNodeResponse{}
-	mi := &file_protos_workflow_node_proto_msgTypes[20]
+	mi := &file_protos_workflow_node_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1319,7 +3492,7 @@ func (x *ExecuteNodeResponse) String() string {
 func (*ExecuteNodeResponse) ProtoMessage() {}
 
 func (x *ExecuteNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[20]
+	mi := &file_protos_workflow_node_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1332,7 +3505,7 @@ func (x *ExecuteNodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteNodeResponse.ProtoReflect.Descriptor instead.
 func (*ExecuteNodeResponse) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{20}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *ExecuteNodeResponse) GetNode() *Node {
@@ -1342,6 +3515,156 @@ func (x *ExecuteNodeResponse) GetNode() *Node {
 	return nil
 }
 
+type GetCapabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_protos_workflow_node_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{52}
+}
+
+type GetCapabilitiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Capabilities  *NodeCapabilities      `protobuf:"bytes,1,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_protos_workflow_node_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetCapabilitiesResponse) GetCapabilities() *NodeCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// What a NodeService backend can execute. An empty agent_ids, roles or
+// model_types list means the backend accepts any value for that field.
+type NodeCapabilities struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentIds      []string               `protobuf:"bytes,1,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	ModelTypes    []string               `protobuf:"bytes,3,rep,name=model_types,json=modelTypes,proto3" json:"model_types,omitempty"`
+	Tools         []string               `protobuf:"bytes,4,rep,name=tools,proto3" json:"tools,omitempty"` // Names of the MCP tools the backend can invoke
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeCapabilities) Reset() {
+	*x = NodeCapabilities{}
+	mi := &file_protos_workflow_node_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeCapabilities) ProtoMessage() {}
+
+func (x *NodeCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_workflow_node_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeCapabilities.ProtoReflect.Descriptor instead.
+func (*NodeCapabilities) Descriptor() ([]byte, []int) {
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *NodeCapabilities) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *NodeCapabilities) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *NodeCapabilities) GetModelTypes() []string {
+	if x != nil {
+		return x.ModelTypes
+	}
+	return nil
+}
+
+func (x *NodeCapabilities) GetTools() []string {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
 type TaskList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
@@ -1351,7 +3674,7 @@ type TaskList struct {
 
 func (x *TaskList) Reset() {
 	*x = TaskList{}
-	mi := &file_protos_workflow_node_proto_msgTypes[21]
+	mi := &file_protos_workflow_node_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1363,7 +3686,7 @@ func (x *TaskList) String() string {
 func (*TaskList) ProtoMessage() {}
 
 func (x *TaskList) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[21]
+	mi := &file_protos_workflow_node_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1376,7 +3699,7 @@ func (x *TaskList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TaskList.ProtoReflect.Descriptor instead.
 func (*TaskList) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{21}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *TaskList) GetTasks() []*Task {
@@ -1395,7 +3718,7 @@ type NodeEditList struct {
 
 func (x *NodeEditList) Reset() {
 	*x = NodeEditList{}
-	mi := &file_protos_workflow_node_proto_msgTypes[22]
+	mi := &file_protos_workflow_node_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1407,7 +3730,7 @@ func (x *NodeEditList) String() string {
 func (*NodeEditList) ProtoMessage() {}
 
 func (x *NodeEditList) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[22]
+	mi := &file_protos_workflow_node_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1420,7 +3743,7 @@ func (x *NodeEditList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NodeEditList.ProtoReflect.Descriptor instead.
 func (*NodeEditList) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{22}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *NodeEditList) GetEdits() []*NodeEdit {
@@ -1440,7 +3763,7 @@ type ExecutionOptions_RetryOptions struct {
 
 func (x *ExecutionOptions_RetryOptions) Reset() {
 	*x = ExecutionOptions_RetryOptions{}
-	mi := &file_protos_workflow_node_proto_msgTypes[23]
+	mi := &file_protos_workflow_node_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1452,7 +3775,7 @@ func (x *ExecutionOptions_RetryOptions) String() string {
 func (*ExecutionOptions_RetryOptions) ProtoMessage() {}
 
 func (x *ExecutionOptions_RetryOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[23]
+	mi := &file_protos_workflow_node_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1465,7 +3788,7 @@ func (x *ExecutionOptions_RetryOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutionOptions_RetryOptions.ProtoReflect.Descriptor instead.
 func (*ExecutionOptions_RetryOptions) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{1, 0}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *ExecutionOptions_RetryOptions) GetMaxAttempts() int32 {
@@ -1494,7 +3817,7 @@ type Task_Result struct {
 
 func (x *Task_Result) Reset() {
 	*x = Task_Result{}
-	mi := &file_protos_workflow_node_proto_msgTypes[24]
+	mi := &file_protos_workflow_node_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1506,7 +3829,7 @@ func (x *Task_Result) String() string {
 func (*Task_Result) ProtoMessage() {}
 
 func (x *Task_Result) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[24]
+	mi := &file_protos_workflow_node_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1519,7 +3842,7 @@ func (x *Task_Result) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Task_Result.ProtoReflect.Descriptor instead.
 func (*Task_Result) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{3, 0}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{10, 0}
 }
 
 func (x *Task_Result) GetStatus() Status {
@@ -1561,7 +3884,7 @@ type NodeStatus_Update struct {
 
 func (x *NodeStatus_Update) Reset() {
 	*x = NodeStatus_Update{}
-	mi := &file_protos_workflow_node_proto_msgTypes[26]
+	mi := &file_protos_workflow_node_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1573,7 +3896,7 @@ func (x *NodeStatus_Update) String() string {
 func (*NodeStatus_Update) ProtoMessage() {}
 
 func (x *NodeStatus_Update) ProtoReflect() protoreflect.Message {
-	mi := &file_protos_workflow_node_proto_msgTypes[26]
+	mi := &file_protos_workflow_node_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1586,7 +3909,7 @@ func (x *NodeStatus_Update) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NodeStatus_Update.ProtoReflect.Descriptor instead.
 func (*NodeStatus_Update) Descriptor() ([]byte, []int) {
-	return file_protos_workflow_node_proto_rawDescGZIP(), []int{4, 0}
+	return file_protos_workflow_node_proto_rawDescGZIP(), []int{11, 0}
 }
 
 func (x *NodeStatus_Update) GetStatus() Status {
@@ -1614,7 +3937,7 @@ var File_protos_workflow_node_proto protoreflect.FileDescriptor
 
 const file_protos_workflow_node_proto_rawDesc = "" +
 	"\n" +
-	"\x1aprotos/workflow_node.proto\x12\x12aisociety.workflow\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfa\x03\n" +
+	"\x1aprotos/workflow_node.proto\x12\x12aisociety.workflow\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfe\t\n" +
 	"\x04Node\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1d\n" +
@@ -1628,14 +3951,74 @@ const file_protos_workflow_node_proto_rawDesc = "" +
 	"\x06status\x18\t \x01(\x0e2\x1a.aisociety.workflow.StatusR\x06status\x122\n" +
 	"\x05edits\x18\n" +
 	" \x03(\v2\x1c.aisociety.workflow.NodeEditR\x05edits\x12\x19\n" +
-	"\bis_final\x18\v \x01(\bR\aisFinal\"\x8e\x02\n" +
+	"\bis_final\x18\v \x01(\bR\aisFinal\x12?\n" +
+	"\vnode_status\x18\f \x01(\v2\x1e.aisociety.workflow.NodeStatusR\n" +
+	"nodeStatus\x127\n" +
+	"\battempts\x18\r \x03(\v2\x1b.aisociety.workflow.AttemptR\battempts\x12\x1a\n" +
+	"\bpriority\x18\x0e \x01(\x05R\bpriority\x12H\n" +
+	"\x0efailure_policy\x18\x0f \x01(\x0e2!.aisociety.workflow.FailurePolicyR\rfailurePolicy\x129\n" +
+	"\n" +
+	"not_before\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tnotBefore\x12/\n" +
+	"\x05delay\x18\x11 \x01(\v2\x19.google.protobuf.DurationR\x05delay\x120\n" +
+	"\x04type\x18\x12 \x01(\x0e2\x1c.aisociety.workflow.NodeTypeR\x04type\x128\n" +
+	"\bapproval\x18\x13 \x01(\v2\x1c.aisociety.workflow.ApprovalR\bapproval\x12?\n" +
+	"\vmap_options\x18\x14 \x01(\v2\x1e.aisociety.workflow.MapOptionsR\n" +
+	"mapOptions\x12H\n" +
+	"\x0ebranch_options\x18\x15 \x01(\v2!.aisociety.workflow.BranchOptionsR\rbranchOptions\x12W\n" +
+	"\x13subworkflow_options\x18\x16 \x01(\v2&.aisociety.workflow.SubworkflowOptionsR\x12subworkflowOptions\x12G\n" +
+	"\x0erejected_edits\x18\x17 \x03(\v2 .aisociety.workflow.RejectedEditR\rrejectedEdits\x12\x1d\n" +
+	"\n" +
+	"edit_depth\x18\x18 \x01(\x05R\teditDepth\"\x8e\x02\n" +
+	"\x12SubworkflowOptions\x12E\n" +
+	"\btemplate\x18\x01 \x01(\v2).aisociety.workflow.CreateWorkflowRequestR\btemplate\x12J\n" +
+	"\x06inputs\x18\x02 \x03(\v22.aisociety.workflow.SubworkflowOptions.InputsEntryR\x06inputs\x12*\n" +
+	"\x11child_workflow_id\x18\x03 \x01(\tR\x0fchildWorkflowId\x1a9\n" +
+	"\vInputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd4\x01\n" +
+	"\n" +
+	"MapOptions\x12=\n" +
+	"\x06source\x18\x01 \x01(\x0e2%.aisociety.workflow.MapOptions.SourceR\x06source\x124\n" +
+	"\btemplate\x18\x02 \x01(\v2\x18.aisociety.workflow.NodeR\btemplate\x12'\n" +
+	"\x0fmax_concurrency\x18\x03 \x01(\x05R\x0emaxConcurrency\"(\n" +
+	"\x06Source\x12\f\n" +
+	"\bSUBTASKS\x10\x00\x12\x10\n" +
+	"\fOUTPUT_ITEMS\x10\x01\"\x99\x01\n" +
+	"\rBranchOptions\x124\n" +
+	"\x05cases\x18\x01 \x03(\v2\x1e.aisociety.workflow.BranchCaseR\x05cases\x12*\n" +
+	"\x11default_child_ids\x18\x02 \x03(\tR\x0fdefaultChildIds\x12&\n" +
+	"\x0ftaken_child_ids\x18\x03 \x03(\tR\rtakenChildIds\"b\n" +
+	"\n" +
+	"BranchCase\x127\n" +
+	"\x04when\x18\x01 \x01(\v2#.aisociety.workflow.ResultPredicateR\x04when\x12\x1b\n" +
+	"\tchild_ids\x18\x02 \x03(\tR\bchildIds\"\x99\x02\n" +
+	"\x0fResultPredicate\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.aisociety.workflow.StatusR\x06status\x12)\n" +
+	"\x10summary_contains\x18\x03 \x01(\tR\x0fsummaryContains\x12P\n" +
+	"\tartifacts\x18\x04 \x03(\v22.aisociety.workflow.ResultPredicate.ArtifactsEntryR\tartifacts\x1a<\n" +
+	"\x0eArtifactsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb3\x01\n" +
+	"\bApproval\x12\x1a\n" +
+	"\bapproved\x18\x01 \x01(\bR\bapproved\x126\n" +
+	"\bapprover\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\bapprover\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x129\n" +
+	"\n" +
+	"decided_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tdecidedAt\"\x8e\x02\n" +
 	"\x10ExecutionOptions\x123\n" +
 	"\atimeout\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12V\n" +
 	"\rretry_options\x18\x02 \x01(\v21.aisociety.workflow.ExecutionOptions.RetryOptionsR\fretryOptions\x1am\n" +
 	"\fRetryOptions\x12!\n" +
 	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12:\n" +
 	"\vretry_delay\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\n" +
-	"retryDelay\"U\n" +
+	"retryDelay\"\xd9\x01\n" +
+	"\aAttempt\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.aisociety.workflow.StatusR\x06status\x124\n" +
+	"\astarted\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\astarted\x126\n" +
+	"\bfinished\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bfinished\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"U\n" +
 	"\x05Agent\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x12\n" +
 	"\x04role\x18\x02 \x01(\tR\x04role\x12\x1d\n" +
@@ -1677,18 +4060,38 @@ const file_protos_workflow_node_proto_rawDesc = "" +
 	"\n" +
 	"\x06DELETE\x10\x02\x12\n" +
 	"\n" +
-	"\x06UPDATE\x10\x03\"{\n" +
+	"\x06UPDATE\x10\x03\"\x95\x01\n" +
+	"\fRejectedEdit\x120\n" +
+	"\x04edit\x18\x01 \x01(\v2\x1c.aisociety.workflow.NodeEditR\x04edit\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12;\n" +
+	"\vrejected_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"rejectedAt\"\xf7\x01\n" +
 	"\x15CreateWorkflowRequest\x12.\n" +
 	"\x05nodes\x18\x01 \x03(\v2\x18.aisociety.workflow.NodeR\x05nodes\x122\n" +
-	"\x06caller\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"9\n" +
+	"\x06caller\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x05R\bpriority\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\x12H\n" +
+	"\x0efailure_policy\x18\x05 \x01(\x0e2!.aisociety.workflow.FailurePolicyR\rfailurePolicy\"9\n" +
 	"\x16CreateWorkflowResponse\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\"5\n" +
 	"\x12GetWorkflowRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
-	"workflowId\"E\n" +
+	"workflowId\"\x9c\x03\n" +
 	"\x13GetWorkflowResponse\x12.\n" +
-	"\x05nodes\x18\x01 \x03(\v2\x18.aisociety.workflow.NodeR\x05nodes\"\x16\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x18.aisociety.workflow.NodeR\x05nodes\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.aisociety.workflow.StatusR\x06status\x12=\n" +
+	"\fcompleted_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12H\n" +
+	"\x0efailure_policy\x18\x04 \x01(\x0e2!.aisociety.workflow.FailurePolicyR\rfailurePolicy\x12\x16\n" +
+	"\x06paused\x18\x05 \x01(\bR\x06paused\x12,\n" +
+	"\x12parent_workflow_id\x18\x06 \x01(\tR\x10parentWorkflowId\x12$\n" +
+	"\x0eparent_node_id\x18\a \x01(\tR\fparentNodeId\x12,\n" +
+	"\x12child_workflow_ids\x18\b \x03(\tR\x10childWorkflowIds\"\xac\x01\n" +
+	"\x16WorkflowCompletedEvent\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.aisociety.workflow.StatusR\x06status\x12=\n" +
+	"\fcompleted_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x16\n" +
 	"\x14ListWorkflowsRequest\":\n" +
 	"\x15ListWorkflowsResponse\x12!\n" +
 	"\fworkflow_ids\x18\x01 \x03(\tR\vworkflowIds\"\x9c\x01\n" +
@@ -1715,6 +4118,85 @@ const file_protos_workflow_node_proto_rawDesc = "" +
 	"\x04node\x18\x02 \x01(\v2\x18.aisociety.workflow.NodeR\x04node\x122\n" +
 	"\x06caller\x18\x03 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\".\n" +
 	"\x12UpdateNodeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x84\x01\n" +
+	"\x15CancelWorkflowRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x122\n" +
+	"\x06caller\x18\x03 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"D\n" +
+	"\x16CancelWorkflowResponse\x12*\n" +
+	"\x11canceled_node_ids\x18\x01 \x03(\tR\x0fcanceledNodeIds\"\x99\x01\n" +
+	"\x11CancelNodeRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x122\n" +
+	"\x06caller\x18\x04 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"@\n" +
+	"\x12CancelNodeResponse\x12*\n" +
+	"\x11canceled_node_ids\x18\x01 \x03(\tR\x0fcanceledNodeIds\"k\n" +
+	"\x14PauseWorkflowRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x122\n" +
+	"\x06caller\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"1\n" +
+	"\x15PauseWorkflowResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"l\n" +
+	"\x15ResumeWorkflowRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x122\n" +
+	"\x06caller\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"2\n" +
+	"\x16ResumeWorkflowResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x92\x03\n" +
+	"\aTrigger\x12\x1d\n" +
+	"\n" +
+	"trigger_id\x18\x01 \x01(\tR\ttriggerId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bschedule\x18\x03 \x01(\tR\bschedule\x12E\n" +
+	"\btemplate\x18\x04 \x01(\v2).aisociety.workflow.CreateWorkflowRequestR\btemplate\x12O\n" +
+	"\x11missed_run_policy\x18\x05 \x01(\x0e2#.aisociety.workflow.MissedRunPolicyR\x0fmissedRunPolicy\x12:\n" +
+	"\vnext_run_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\x12:\n" +
+	"\vlast_run_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x12(\n" +
+	"\x10last_workflow_id\x18\b \x01(\tR\x0elastWorkflowId\"\x81\x01\n" +
+	"\x14CreateTriggerRequest\x125\n" +
+	"\atrigger\x18\x01 \x01(\v2\x1b.aisociety.workflow.TriggerR\atrigger\x122\n" +
+	"\x06caller\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"r\n" +
+	"\x15CreateTriggerResponse\x12\x1d\n" +
+	"\n" +
+	"trigger_id\x18\x01 \x01(\tR\ttriggerId\x12:\n" +
+	"\vnext_run_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\"\x15\n" +
+	"\x13ListTriggersRequest\"O\n" +
+	"\x14ListTriggersResponse\x127\n" +
+	"\btriggers\x18\x01 \x03(\v2\x1b.aisociety.workflow.TriggerR\btriggers\"i\n" +
+	"\x14DeleteTriggerRequest\x12\x1d\n" +
+	"\n" +
+	"trigger_id\x18\x01 \x01(\tR\ttriggerId\x122\n" +
+	"\x06caller\x18\x02 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"1\n" +
+	"\x15DeleteTriggerResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\">\n" +
+	"\x1bListPendingApprovalsRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\"\xa1\x01\n" +
+	"\x0fPendingApproval\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12,\n" +
+	"\x04node\x18\x02 \x01(\v2\x18.aisociety.workflow.NodeR\x04node\x12?\n" +
+	"\rwaiting_since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fwaitingSince\"a\n" +
+	"\x1cListPendingApprovalsResponse\x12A\n" +
+	"\tapprovals\x18\x01 \x03(\v2#.aisociety.workflow.PendingApprovalR\tapprovals\"\x9c\x01\n" +
+	"\x12ApproveNodeRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x122\n" +
+	"\x06caller\x18\x04 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\"/\n" +
+	"\x13ApproveNodeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x9b\x01\n" +
+	"\x11RejectNodeRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x122\n" +
+	"\x06caller\x18\x04 \x01(\v2\x1a.aisociety.workflow.CallerR\x06caller\".\n" +
+	"\x12RejectNodeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x82\x02\n" +
 	"\x12ExecuteNodeRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
@@ -1724,11 +4206,20 @@ const file_protos_workflow_node_proto_rawDesc = "" +
 	"\x0eupstream_nodes\x18\x04 \x03(\v2\x18.aisociety.workflow.NodeR\rupstreamNodes\x12C\n" +
 	"\x10downstream_nodes\x18\x05 \x03(\v2\x18.aisociety.workflow.NodeR\x0fdownstreamNodes\"C\n" +
 	"\x13ExecuteNodeResponse\x12,\n" +
-	"\x04node\x18\x01 \x01(\v2\x18.aisociety.workflow.NodeR\x04node\":\n" +
+	"\x04node\x18\x01 \x01(\v2\x18.aisociety.workflow.NodeR\x04node\"\x18\n" +
+	"\x16GetCapabilitiesRequest\"c\n" +
+	"\x17GetCapabilitiesResponse\x12H\n" +
+	"\fcapabilities\x18\x01 \x01(\v2$.aisociety.workflow.NodeCapabilitiesR\fcapabilities\"|\n" +
+	"\x10NodeCapabilities\x12\x1b\n" +
+	"\tagent_ids\x18\x01 \x03(\tR\bagentIds\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x1f\n" +
+	"\vmodel_types\x18\x03 \x03(\tR\n" +
+	"modelTypes\x12\x14\n" +
+	"\x05tools\x18\x04 \x03(\tR\x05tools\":\n" +
 	"\bTaskList\x12.\n" +
 	"\x05tasks\x18\x01 \x03(\v2\x18.aisociety.workflow.TaskR\x05tasks\"B\n" +
 	"\fNodeEditList\x122\n" +
-	"\x05edits\x18\x01 \x03(\v2\x1c.aisociety.workflow.NodeEditR\x05edits*\x97\x01\n" +
+	"\x05edits\x18\x01 \x03(\v2\x1c.aisociety.workflow.NodeEditR\x05edits*\xd6\x01\n" +
 	"\x06Status\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\b\n" +
 	"\x04PASS\x10\x01\x12\b\n" +
@@ -1742,7 +4233,30 @@ const file_protos_workflow_node_proto_rawDesc = "" +
 	"\x05CRASH\x10\b\x12\v\n" +
 	"\aBLOCKED\x10\t\x12\v\n" +
 	"\aRUNNING\x10\n" +
-	"2\xda\x04\n" +
+	"\x12\t\n" +
+	"\x05READY\x10\v\x12\x18\n" +
+	"\x14WAITING_FOR_APPROVAL\x10\f\x12\x18\n" +
+	"\x14WAITING_FOR_WORKFLOW\x10\r*b\n" +
+	"\rFailurePolicy\x12\x1e\n" +
+	"\x1aFAILURE_POLICY_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10SKIP_DESCENDANTS\x10\x01\x12\r\n" +
+	"\tFAIL_FAST\x10\x02\x12\f\n" +
+	"\bCONTINUE\x10\x03*`\n" +
+	"\bNodeType\x12\t\n" +
+	"\x05AGENT\x10\x00\x12\t\n" +
+	"\x05TIMER\x10\x01\x12\f\n" +
+	"\bAPPROVAL\x10\x02\x12\a\n" +
+	"\x03MAP\x10\x03\x12\n" +
+	"\n" +
+	"\x06REDUCE\x10\x04\x12\n" +
+	"\n" +
+	"\x06BRANCH\x10\x05\x12\x0f\n" +
+	"\vSUBWORKFLOW\x10\x06*j\n" +
+	"\x0fMissedRunPolicy\x12!\n" +
+	"\x1dMISSED_RUN_POLICY_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rCATCH_UP_ONCE\x10\x01\x12\x0f\n" +
+	"\vSKIP_MISSED\x10\x02\x12\x10\n" +
+	"\fCATCH_UP_ALL\x10\x032\xd6\f\n" +
 	"\x0fWorkflowService\x12g\n" +
 	"\x0eCreateWorkflow\x12).aisociety.workflow.CreateWorkflowRequest\x1a*.aisociety.workflow.CreateWorkflowResponse\x12^\n" +
 	"\vGetWorkflow\x12&.aisociety.workflow.GetWorkflowRequest\x1a'.aisociety.workflow.GetWorkflowResponse\x12d\n" +
@@ -1750,9 +4264,22 @@ const file_protos_workflow_node_proto_rawDesc = "" +
 	"\x0eUpdateWorkflow\x12).aisociety.workflow.UpdateWorkflowRequest\x1a*.aisociety.workflow.UpdateWorkflowResponse\x12R\n" +
 	"\aGetNode\x12\".aisociety.workflow.GetNodeRequest\x1a#.aisociety.workflow.GetNodeResponse\x12[\n" +
 	"\n" +
-	"UpdateNode\x12%.aisociety.workflow.UpdateNodeRequest\x1a&.aisociety.workflow.UpdateNodeResponse2m\n" +
+	"UpdateNode\x12%.aisociety.workflow.UpdateNodeRequest\x1a&.aisociety.workflow.UpdateNodeResponse\x12g\n" +
+	"\x0eCancelWorkflow\x12).aisociety.workflow.CancelWorkflowRequest\x1a*.aisociety.workflow.CancelWorkflowResponse\x12[\n" +
+	"\n" +
+	"CancelNode\x12%.aisociety.workflow.CancelNodeRequest\x1a&.aisociety.workflow.CancelNodeResponse\x12d\n" +
+	"\rPauseWorkflow\x12(.aisociety.workflow.PauseWorkflowRequest\x1a).aisociety.workflow.PauseWorkflowResponse\x12g\n" +
+	"\x0eResumeWorkflow\x12).aisociety.workflow.ResumeWorkflowRequest\x1a*.aisociety.workflow.ResumeWorkflowResponse\x12d\n" +
+	"\rCreateTrigger\x12(.aisociety.workflow.CreateTriggerRequest\x1a).aisociety.workflow.CreateTriggerResponse\x12a\n" +
+	"\fListTriggers\x12'.aisociety.workflow.ListTriggersRequest\x1a(.aisociety.workflow.ListTriggersResponse\x12d\n" +
+	"\rDeleteTrigger\x12(.aisociety.workflow.DeleteTriggerRequest\x1a).aisociety.workflow.DeleteTriggerResponse\x12y\n" +
+	"\x14ListPendingApprovals\x12/.aisociety.workflow.ListPendingApprovalsRequest\x1a0.aisociety.workflow.ListPendingApprovalsResponse\x12^\n" +
+	"\vApproveNode\x12&.aisociety.workflow.ApproveNodeRequest\x1a'.aisociety.workflow.ApproveNodeResponse\x12[\n" +
+	"\n" +
+	"RejectNode\x12%.aisociety.workflow.RejectNodeRequest\x1a&.aisociety.workflow.RejectNodeResponse2\xd9\x01\n" +
 	"\vNodeService\x12^\n" +
-	"\vExecuteNode\x12&.aisociety.workflow.ExecuteNodeRequest\x1a'.aisociety.workflow.ExecuteNodeResponseB\"Z paul.hobbs.page/aisociety/protosb\x06proto3"
+	"\vExecuteNode\x12&.aisociety.workflow.ExecuteNodeRequest\x1a'.aisociety.workflow.ExecuteNodeResponse\x12j\n" +
+	"\x0fGetCapabilities\x12*.aisociety.workflow.GetCapabilitiesRequest\x1a+.aisociety.workflow.GetCapabilitiesResponseB\"Z paul.hobbs.page/aisociety/protosb\x06proto3"
 
 var (
 	file_protos_workflow_node_proto_rawDescOnce sync.Once
@@ -1766,94 +4293,207 @@ func file_protos_workflow_node_proto_rawDescGZIP() []byte {
 	return file_protos_workflow_node_proto_rawDescData
 }
 
-var file_protos_workflow_node_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_protos_workflow_node_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_protos_workflow_node_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_protos_workflow_node_proto_msgTypes = make([]protoimpl.MessageInfo, 63)
 var file_protos_workflow_node_proto_goTypes = []any{
 	(Status)(0),                           // 0: aisociety.workflow.Status
-	(NodeEdit_Type)(0),                    // 1: aisociety.workflow.NodeEdit.Type
-	(*Node)(nil),                          // 2: aisociety.workflow.Node
-	(*ExecutionOptions)(nil),              // 3: aisociety.workflow.ExecutionOptions
-	(*Agent)(nil),                         // 4: aisociety.workflow.Agent
-	(*Task)(nil),                          // 5: aisociety.workflow.Task
-	(*NodeStatus)(nil),                    // 6: aisociety.workflow.NodeStatus
-	(*NodeEdit)(nil),                      // 7: aisociety.workflow.NodeEdit
-	(*CreateWorkflowRequest)(nil),         // 8: aisociety.workflow.CreateWorkflowRequest
-	(*CreateWorkflowResponse)(nil),        // 9: aisociety.workflow.CreateWorkflowResponse
-	(*GetWorkflowRequest)(nil),            // 10: aisociety.workflow.GetWorkflowRequest
-	(*GetWorkflowResponse)(nil),           // 11: aisociety.workflow.GetWorkflowResponse
-	(*ListWorkflowsRequest)(nil),          // 12: aisociety.workflow.ListWorkflowsRequest
-	(*ListWorkflowsResponse)(nil),         // 13: aisociety.workflow.ListWorkflowsResponse
-	(*UpdateWorkflowRequest)(nil),         // 14: aisociety.workflow.UpdateWorkflowRequest
-	(*UpdateWorkflowResponse)(nil),        // 15: aisociety.workflow.UpdateWorkflowResponse
-	(*GetNodeRequest)(nil),                // 16: aisociety.workflow.GetNodeRequest
-	(*GetNodeResponse)(nil),               // 17: aisociety.workflow.GetNodeResponse
-	(*Caller)(nil),                        // 18: aisociety.workflow.Caller
-	(*UpdateNodeRequest)(nil),             // 19: aisociety.workflow.UpdateNodeRequest
-	(*UpdateNodeResponse)(nil),            // 20: aisociety.workflow.UpdateNodeResponse
-	(*ExecuteNodeRequest)(nil),            // 21: aisociety.workflow.ExecuteNodeRequest
-	(*ExecuteNodeResponse)(nil),           // 22: aisociety.workflow.ExecuteNodeResponse
-	(*TaskList)(nil),                      // 23: aisociety.workflow.TaskList
-	(*NodeEditList)(nil),                  // 24: aisociety.workflow.NodeEditList
-	(*ExecutionOptions_RetryOptions)(nil), // 25: aisociety.workflow.ExecutionOptions.RetryOptions
-	(*Task_Result)(nil),                   // 26: aisociety.workflow.Task.Result
-	nil,                                   // 27: aisociety.workflow.Task.Result.ArtifactsEntry
-	(*NodeStatus_Update)(nil),             // 28: aisociety.workflow.NodeStatus.Update
-	(*durationpb.Duration)(nil),           // 29: google.protobuf.Duration
-	(*timestamppb.Timestamp)(nil),         // 30: google.protobuf.Timestamp
+	(FailurePolicy)(0),                    // 1: aisociety.workflow.FailurePolicy
+	(NodeType)(0),                         // 2: aisociety.workflow.NodeType
+	(MissedRunPolicy)(0),                  // 3: aisociety.workflow.MissedRunPolicy
+	(MapOptions_Source)(0),                // 4: aisociety.workflow.MapOptions.Source
+	(NodeEdit_Type)(0),                    // 5: aisociety.workflow.NodeEdit.Type
+	(*Node)(nil),                          // 6: aisociety.workflow.Node
+	(*SubworkflowOptions)(nil),            // 7: aisociety.workflow.SubworkflowOptions
+	(*MapOptions)(nil),                    // 8: aisociety.workflow.MapOptions
+	(*BranchOptions)(nil),                 // 9: aisociety.workflow.BranchOptions
+	(*BranchCase)(nil),                    // 10: aisociety.workflow.BranchCase
+	(*ResultPredicate)(nil),               // 11: aisociety.workflow.ResultPredicate
+	(*Approval)(nil),                      // 12: aisociety.workflow.Approval
+	(*ExecutionOptions)(nil),              // 13: aisociety.workflow.ExecutionOptions
+	(*Attempt)(nil),                       // 14: aisociety.workflow.Attempt
+	(*Agent)(nil),                         // 15: aisociety.workflow.Agent
+	(*Task)(nil),                          // 16: aisociety.workflow.Task
+	(*NodeStatus)(nil),                    // 17: aisociety.workflow.NodeStatus
+	(*NodeEdit)(nil),                      // 18: aisociety.workflow.NodeEdit
+	(*RejectedEdit)(nil),                  // 19: aisociety.workflow.RejectedEdit
+	(*CreateWorkflowRequest)(nil),         // 20: aisociety.workflow.CreateWorkflowRequest
+	(*CreateWorkflowResponse)(nil),        // 21: aisociety.workflow.CreateWorkflowResponse
+	(*GetWorkflowRequest)(nil),            // 22: aisociety.workflow.GetWorkflowRequest
+	(*GetWorkflowResponse)(nil),           // 23: aisociety.workflow.GetWorkflowResponse
+	(*WorkflowCompletedEvent)(nil),        // 24: aisociety.workflow.WorkflowCompletedEvent
+	(*ListWorkflowsRequest)(nil),          // 25: aisociety.workflow.ListWorkflowsRequest
+	(*ListWorkflowsResponse)(nil),         // 26: aisociety.workflow.ListWorkflowsResponse
+	(*UpdateWorkflowRequest)(nil),         // 27: aisociety.workflow.UpdateWorkflowRequest
+	(*UpdateWorkflowResponse)(nil),        // 28: aisociety.workflow.UpdateWorkflowResponse
+	(*GetNodeRequest)(nil),                // 29: aisociety.workflow.GetNodeRequest
+	(*GetNodeResponse)(nil),               // 30: aisociety.workflow.GetNodeResponse
+	(*Caller)(nil),                        // 31: aisociety.workflow.Caller
+	(*UpdateNodeRequest)(nil),             // 32: aisociety.workflow.UpdateNodeRequest
+	(*UpdateNodeResponse)(nil),            // 33: aisociety.workflow.UpdateNodeResponse
+	(*CancelWorkflowRequest)(nil),         // 34: aisociety.workflow.CancelWorkflowRequest
+	(*CancelWorkflowResponse)(nil),        // 35: aisociety.workflow.CancelWorkflowResponse
+	(*CancelNodeRequest)(nil),             // 36: aisociety.workflow.CancelNodeRequest
+	(*CancelNodeResponse)(nil),            // 37: aisociety.workflow.CancelNodeResponse
+	(*PauseWorkflowRequest)(nil),          // 38: aisociety.workflow.PauseWorkflowRequest
+	(*PauseWorkflowResponse)(nil),         // 39: aisociety.workflow.PauseWorkflowResponse
+	(*ResumeWorkflowRequest)(nil),         // 40: aisociety.workflow.ResumeWorkflowRequest
+	(*ResumeWorkflowResponse)(nil),        // 41: aisociety.workflow.ResumeWorkflowResponse
+	(*Trigger)(nil),                       // 42: aisociety.workflow.Trigger
+	(*CreateTriggerRequest)(nil),          // 43: aisociety.workflow.CreateTriggerRequest
+	(*CreateTriggerResponse)(nil),         // 44: aisociety.workflow.CreateTriggerResponse
+	(*ListTriggersRequest)(nil),           // 45: aisociety.workflow.ListTriggersRequest
+	(*ListTriggersResponse)(nil),          // 46: aisociety.workflow.ListTriggersResponse
+	(*DeleteTriggerRequest)(nil),          // 47: aisociety.workflow.DeleteTriggerRequest
+	(*DeleteTriggerResponse)(nil),         // 48: aisociety.workflow.DeleteTriggerResponse
+	(*ListPendingApprovalsRequest)(nil),   // 49: aisociety.workflow.ListPendingApprovalsRequest
+	(*PendingApproval)(nil),               // 50: aisociety.workflow.PendingApproval
+	(*ListPendingApprovalsResponse)(nil),  // 51: aisociety.workflow.ListPendingApprovalsResponse
+	(*ApproveNodeRequest)(nil),            // 52: aisociety.workflow.ApproveNodeRequest
+	(*ApproveNodeResponse)(nil),           // 53: aisociety.workflow.ApproveNodeResponse
+	(*RejectNodeRequest)(nil),             // 54: aisociety.workflow.RejectNodeRequest
+	(*RejectNodeResponse)(nil),            // 55: aisociety.workflow.RejectNodeResponse
+	(*ExecuteNodeRequest)(nil),            // 56: aisociety.workflow.ExecuteNodeRequest
+	(*ExecuteNodeResponse)(nil),           // 57: aisociety.workflow.ExecuteNodeResponse
+	(*GetCapabilitiesRequest)(nil),        // 58: aisociety.workflow.GetCapabilitiesRequest
+	(*GetCapabilitiesResponse)(nil),       // 59: aisociety.workflow.GetCapabilitiesResponse
+	(*NodeCapabilities)(nil),              // 60: aisociety.workflow.NodeCapabilities
+	(*TaskList)(nil),                      // 61: aisociety.workflow.TaskList
+	(*NodeEditList)(nil),                  // 62: aisociety.workflow.NodeEditList
+	nil,                                   // 63: aisociety.workflow.SubworkflowOptions.InputsEntry
+	nil,                                   // 64: aisociety.workflow.ResultPredicate.ArtifactsEntry
+	(*ExecutionOptions_RetryOptions)(nil), // 65: aisociety.workflow.ExecutionOptions.RetryOptions
+	(*Task_Result)(nil),                   // 66: aisociety.workflow.Task.Result
+	nil,                                   // 67: aisociety.workflow.Task.Result.ArtifactsEntry
+	(*NodeStatus_Update)(nil),             // 68: aisociety.workflow.NodeStatus.Update
+	(*timestamppb.Timestamp)(nil),         // 69: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),           // 70: google.protobuf.Duration
 }
 var file_protos_workflow_node_proto_depIdxs = []int32{
-	4,  // 0: aisociety.workflow.Node.agent:type_name -> aisociety.workflow.Agent
-	3,  // 1: aisociety.workflow.Node.execution_options:type_name -> aisociety.workflow.ExecutionOptions
-	5,  // 2: aisociety.workflow.Node.all_tasks:type_name -> aisociety.workflow.Task
-	5,  // 3: aisociety.workflow.Node.assigned_task:type_name -> aisociety.workflow.Task
-	0,  // 4: aisociety.workflow.Node.status:type_name -> aisociety.workflow.Status
-	7,  // 5: aisociety.workflow.Node.edits:type_name -> aisociety.workflow.NodeEdit
-	29, // 6: aisociety.workflow.ExecutionOptions.timeout:type_name -> google.protobuf.Duration
-	25, // 7: aisociety.workflow.ExecutionOptions.retry_options:type_name -> aisociety.workflow.ExecutionOptions.RetryOptions
-	26, // 8: aisociety.workflow.Task.results:type_name -> aisociety.workflow.Task.Result
-	5,  // 9: aisociety.workflow.Task.subtasks:type_name -> aisociety.workflow.Task
-	28, // 10: aisociety.workflow.NodeStatus.progress:type_name -> aisociety.workflow.NodeStatus.Update
-	1,  // 11: aisociety.workflow.NodeEdit.type:type_name -> aisociety.workflow.NodeEdit.Type
-	30, // 12: aisociety.workflow.NodeEdit.timestamp:type_name -> google.protobuf.Timestamp
-	2,  // 13: aisociety.workflow.NodeEdit.node:type_name -> aisociety.workflow.Node
-	2,  // 14: aisociety.workflow.CreateWorkflowRequest.nodes:type_name -> aisociety.workflow.Node
-	18, // 15: aisociety.workflow.CreateWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
-	2,  // 16: aisociety.workflow.GetWorkflowResponse.nodes:type_name -> aisociety.workflow.Node
-	2,  // 17: aisociety.workflow.UpdateWorkflowRequest.nodes:type_name -> aisociety.workflow.Node
-	18, // 18: aisociety.workflow.UpdateWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
-	2,  // 19: aisociety.workflow.GetNodeResponse.node:type_name -> aisociety.workflow.Node
-	2,  // 20: aisociety.workflow.UpdateNodeRequest.node:type_name -> aisociety.workflow.Node
-	18, // 21: aisociety.workflow.UpdateNodeRequest.caller:type_name -> aisociety.workflow.Caller
-	2,  // 22: aisociety.workflow.ExecuteNodeRequest.node:type_name -> aisociety.workflow.Node
-	2,  // 23: aisociety.workflow.ExecuteNodeRequest.upstream_nodes:type_name -> aisociety.workflow.Node
-	2,  // 24: aisociety.workflow.ExecuteNodeRequest.downstream_nodes:type_name -> aisociety.workflow.Node
-	2,  // 25: aisociety.workflow.ExecuteNodeResponse.node:type_name -> aisociety.workflow.Node
-	5,  // 26: aisociety.workflow.TaskList.tasks:type_name -> aisociety.workflow.Task
-	7,  // 27: aisociety.workflow.NodeEditList.edits:type_name -> aisociety.workflow.NodeEdit
-	29, // 28: aisociety.workflow.ExecutionOptions.RetryOptions.retry_delay:type_name -> google.protobuf.Duration
-	0,  // 29: aisociety.workflow.Task.Result.status:type_name -> aisociety.workflow.Status
-	27, // 30: aisociety.workflow.Task.Result.artifacts:type_name -> aisociety.workflow.Task.Result.ArtifactsEntry
-	0,  // 31: aisociety.workflow.NodeStatus.Update.status:type_name -> aisociety.workflow.Status
-	30, // 32: aisociety.workflow.NodeStatus.Update.updated_millis:type_name -> google.protobuf.Timestamp
-	8,  // 33: aisociety.workflow.WorkflowService.CreateWorkflow:input_type -> aisociety.workflow.CreateWorkflowRequest
-	10, // 34: aisociety.workflow.WorkflowService.GetWorkflow:input_type -> aisociety.workflow.GetWorkflowRequest
-	12, // 35: aisociety.workflow.WorkflowService.ListWorkflows:input_type -> aisociety.workflow.ListWorkflowsRequest
-	14, // 36: aisociety.workflow.WorkflowService.UpdateWorkflow:input_type -> aisociety.workflow.UpdateWorkflowRequest
-	16, // 37: aisociety.workflow.WorkflowService.GetNode:input_type -> aisociety.workflow.GetNodeRequest
-	19, // 38: aisociety.workflow.WorkflowService.UpdateNode:input_type -> aisociety.workflow.UpdateNodeRequest
-	21, // 39: aisociety.workflow.NodeService.ExecuteNode:input_type -> aisociety.workflow.ExecuteNodeRequest
-	9,  // 40: aisociety.workflow.WorkflowService.CreateWorkflow:output_type -> aisociety.workflow.CreateWorkflowResponse
-	11, // 41: aisociety.workflow.WorkflowService.GetWorkflow:output_type -> aisociety.workflow.GetWorkflowResponse
-	13, // 42: aisociety.workflow.WorkflowService.ListWorkflows:output_type -> aisociety.workflow.ListWorkflowsResponse
-	15, // 43: aisociety.workflow.WorkflowService.UpdateWorkflow:output_type -> aisociety.workflow.UpdateWorkflowResponse
-	17, // 44: aisociety.workflow.WorkflowService.GetNode:output_type -> aisociety.workflow.GetNodeResponse
-	20, // 45: aisociety.workflow.WorkflowService.UpdateNode:output_type -> aisociety.workflow.UpdateNodeResponse
-	22, // 46: aisociety.workflow.NodeService.ExecuteNode:output_type -> aisociety.workflow.ExecuteNodeResponse
-	40, // [40:47] is the sub-list for method output_type
-	33, // [33:40] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	15,  // 0: aisociety.workflow.Node.agent:type_name -> aisociety.workflow.Agent
+	13,  // 1: aisociety.workflow.Node.execution_options:type_name -> aisociety.workflow.ExecutionOptions
+	16,  // 2: aisociety.workflow.Node.all_tasks:type_name -> aisociety.workflow.Task
+	16,  // 3: aisociety.workflow.Node.assigned_task:type_name -> aisociety.workflow.Task
+	0,   // 4: aisociety.workflow.Node.status:type_name -> aisociety.workflow.Status
+	18,  // 5: aisociety.workflow.Node.edits:type_name -> aisociety.workflow.NodeEdit
+	17,  // 6: aisociety.workflow.Node.node_status:type_name -> aisociety.workflow.NodeStatus
+	14,  // 7: aisociety.workflow.Node.attempts:type_name -> aisociety.workflow.Attempt
+	1,   // 8: aisociety.workflow.Node.failure_policy:type_name -> aisociety.workflow.FailurePolicy
+	69,  // 9: aisociety.workflow.Node.not_before:type_name -> google.protobuf.Timestamp
+	70,  // 10: aisociety.workflow.Node.delay:type_name -> google.protobuf.Duration
+	2,   // 11: aisociety.workflow.Node.type:type_name -> aisociety.workflow.NodeType
+	12,  // 12: aisociety.workflow.Node.approval:type_name -> aisociety.workflow.Approval
+	8,   // 13: aisociety.workflow.Node.map_options:type_name -> aisociety.workflow.MapOptions
+	9,   // 14: aisociety.workflow.Node.branch_options:type_name -> aisociety.workflow.BranchOptions
+	7,   // 15: aisociety.workflow.Node.subworkflow_options:type_name -> aisociety.workflow.SubworkflowOptions
+	19,  // 16: aisociety.workflow.Node.rejected_edits:type_name -> aisociety.workflow.RejectedEdit
+	20,  // 17: aisociety.workflow.SubworkflowOptions.template:type_name -> aisociety.workflow.CreateWorkflowRequest
+	63,  // 18: aisociety.workflow.SubworkflowOptions.inputs:type_name -> aisociety.workflow.SubworkflowOptions.InputsEntry
+	4,   // 19: aisociety.workflow.MapOptions.source:type_name -> aisociety.workflow.MapOptions.Source
+	6,   // 20: aisociety.workflow.MapOptions.template:type_name -> aisociety.workflow.Node
+	10,  // 21: aisociety.workflow.BranchOptions.cases:type_name -> aisociety.workflow.BranchCase
+	11,  // 22: aisociety.workflow.BranchCase.when:type_name -> aisociety.workflow.ResultPredicate
+	0,   // 23: aisociety.workflow.ResultPredicate.status:type_name -> aisociety.workflow.Status
+	64,  // 24: aisociety.workflow.ResultPredicate.artifacts:type_name -> aisociety.workflow.ResultPredicate.ArtifactsEntry
+	31,  // 25: aisociety.workflow.Approval.approver:type_name -> aisociety.workflow.Caller
+	69,  // 26: aisociety.workflow.Approval.decided_at:type_name -> google.protobuf.Timestamp
+	70,  // 27: aisociety.workflow.ExecutionOptions.timeout:type_name -> google.protobuf.Duration
+	65,  // 28: aisociety.workflow.ExecutionOptions.retry_options:type_name -> aisociety.workflow.ExecutionOptions.RetryOptions
+	0,   // 29: aisociety.workflow.Attempt.status:type_name -> aisociety.workflow.Status
+	69,  // 30: aisociety.workflow.Attempt.started:type_name -> google.protobuf.Timestamp
+	69,  // 31: aisociety.workflow.Attempt.finished:type_name -> google.protobuf.Timestamp
+	66,  // 32: aisociety.workflow.Task.results:type_name -> aisociety.workflow.Task.Result
+	16,  // 33: aisociety.workflow.Task.subtasks:type_name -> aisociety.workflow.Task
+	68,  // 34: aisociety.workflow.NodeStatus.progress:type_name -> aisociety.workflow.NodeStatus.Update
+	5,   // 35: aisociety.workflow.NodeEdit.type:type_name -> aisociety.workflow.NodeEdit.Type
+	69,  // 36: aisociety.workflow.NodeEdit.timestamp:type_name -> google.protobuf.Timestamp
+	6,   // 37: aisociety.workflow.NodeEdit.node:type_name -> aisociety.workflow.Node
+	18,  // 38: aisociety.workflow.RejectedEdit.edit:type_name -> aisociety.workflow.NodeEdit
+	69,  // 39: aisociety.workflow.RejectedEdit.rejected_at:type_name -> google.protobuf.Timestamp
+	6,   // 40: aisociety.workflow.CreateWorkflowRequest.nodes:type_name -> aisociety.workflow.Node
+	31,  // 41: aisociety.workflow.CreateWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
+	1,   // 42: aisociety.workflow.CreateWorkflowRequest.failure_policy:type_name -> aisociety.workflow.FailurePolicy
+	6,   // 43: aisociety.workflow.GetWorkflowResponse.nodes:type_name -> aisociety.workflow.Node
+	0,   // 44: aisociety.workflow.GetWorkflowResponse.status:type_name -> aisociety.workflow.Status
+	69,  // 45: aisociety.workflow.GetWorkflowResponse.completed_at:type_name -> google.protobuf.Timestamp
+	1,   // 46: aisociety.workflow.GetWorkflowResponse.failure_policy:type_name -> aisociety.workflow.FailurePolicy
+	0,   // 47: aisociety.workflow.WorkflowCompletedEvent.status:type_name -> aisociety.workflow.Status
+	69,  // 48: aisociety.workflow.WorkflowCompletedEvent.completed_at:type_name -> google.protobuf.Timestamp
+	6,   // 49: aisociety.workflow.UpdateWorkflowRequest.nodes:type_name -> aisociety.workflow.Node
+	31,  // 50: aisociety.workflow.UpdateWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
+	6,   // 51: aisociety.workflow.GetNodeResponse.node:type_name -> aisociety.workflow.Node
+	6,   // 52: aisociety.workflow.UpdateNodeRequest.node:type_name -> aisociety.workflow.Node
+	31,  // 53: aisociety.workflow.UpdateNodeRequest.caller:type_name -> aisociety.workflow.Caller
+	31,  // 54: aisociety.workflow.CancelWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
+	31,  // 55: aisociety.workflow.CancelNodeRequest.caller:type_name -> aisociety.workflow.Caller
+	31,  // 56: aisociety.workflow.PauseWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
+	31,  // 57: aisociety.workflow.ResumeWorkflowRequest.caller:type_name -> aisociety.workflow.Caller
+	20,  // 58: aisociety.workflow.Trigger.template:type_name -> aisociety.workflow.CreateWorkflowRequest
+	3,   // 59: aisociety.workflow.Trigger.missed_run_policy:type_name -> aisociety.workflow.MissedRunPolicy
+	69,  // 60: aisociety.workflow.Trigger.next_run_at:type_name -> google.protobuf.Timestamp
+	69,  // 61: aisociety.workflow.Trigger.last_run_at:type_name -> google.protobuf.Timestamp
+	42,  // 62: aisociety.workflow.CreateTriggerRequest.trigger:type_name -> aisociety.workflow.Trigger
+	31,  // 63: aisociety.workflow.CreateTriggerRequest.caller:type_name -> aisociety.workflow.Caller
+	69,  // 64: aisociety.workflow.CreateTriggerResponse.next_run_at:type_name -> google.protobuf.Timestamp
+	42,  // 65: aisociety.workflow.ListTriggersResponse.triggers:type_name -> aisociety.workflow.Trigger
+	31,  // 66: aisociety.workflow.DeleteTriggerRequest.caller:type_name -> aisociety.workflow.Caller
+	6,   // 67: aisociety.workflow.PendingApproval.node:type_name -> aisociety.workflow.Node
+	69,  // 68: aisociety.workflow.PendingApproval.waiting_since:type_name -> google.protobuf.Timestamp
+	50,  // 69: aisociety.workflow.ListPendingApprovalsResponse.approvals:type_name -> aisociety.workflow.PendingApproval
+	31,  // 70: aisociety.workflow.ApproveNodeRequest.caller:type_name -> aisociety.workflow.Caller
+	31,  // 71: aisociety.workflow.RejectNodeRequest.caller:type_name -> aisociety.workflow.Caller
+	6,   // 72: aisociety.workflow.ExecuteNodeRequest.node:type_name -> aisociety.workflow.Node
+	6,   // 73: aisociety.workflow.ExecuteNodeRequest.upstream_nodes:type_name -> aisociety.workflow.Node
+	6,   // 74: aisociety.workflow.ExecuteNodeRequest.downstream_nodes:type_name -> aisociety.workflow.Node
+	6,   // 75: aisociety.workflow.ExecuteNodeResponse.node:type_name -> aisociety.workflow.Node
+	60,  // 76: aisociety.workflow.GetCapabilitiesResponse.capabilities:type_name -> aisociety.workflow.NodeCapabilities
+	16,  // 77: aisociety.workflow.TaskList.tasks:type_name -> aisociety.workflow.Task
+	18,  // 78: aisociety.workflow.NodeEditList.edits:type_name -> aisociety.workflow.NodeEdit
+	70,  // 79: aisociety.workflow.ExecutionOptions.RetryOptions.retry_delay:type_name -> google.protobuf.Duration
+	0,   // 80: aisociety.workflow.Task.Result.status:type_name -> aisociety.workflow.Status
+	67,  // 81: aisociety.workflow.Task.Result.artifacts:type_name -> aisociety.workflow.Task.Result.ArtifactsEntry
+	0,   // 82: aisociety.workflow.NodeStatus.Update.status:type_name -> aisociety.workflow.Status
+	69,  // 83: aisociety.workflow.NodeStatus.Update.updated_millis:type_name -> google.protobuf.Timestamp
+	20,  // 84: aisociety.workflow.WorkflowService.CreateWorkflow:input_type -> aisociety.workflow.CreateWorkflowRequest
+	22,  // 85: aisociety.workflow.WorkflowService.GetWorkflow:input_type -> aisociety.workflow.GetWorkflowRequest
+	25,  // 86: aisociety.workflow.WorkflowService.ListWorkflows:input_type -> aisociety.workflow.ListWorkflowsRequest
+	27,  // 87: aisociety.workflow.WorkflowService.UpdateWorkflow:input_type -> aisociety.workflow.UpdateWorkflowRequest
+	29,  // 88: aisociety.workflow.WorkflowService.GetNode:input_type -> aisociety.workflow.GetNodeRequest
+	32,  // 89: aisociety.workflow.WorkflowService.UpdateNode:input_type -> aisociety.workflow.UpdateNodeRequest
+	34,  // 90: aisociety.workflow.WorkflowService.CancelWorkflow:input_type -> aisociety.workflow.CancelWorkflowRequest
+	36,  // 91: aisociety.workflow.WorkflowService.CancelNode:input_type -> aisociety.workflow.CancelNodeRequest
+	38,  // 92: aisociety.workflow.WorkflowService.PauseWorkflow:input_type -> aisociety.workflow.PauseWorkflowRequest
+	40,  // 93: aisociety.workflow.WorkflowService.ResumeWorkflow:input_type -> aisociety.workflow.ResumeWorkflowRequest
+	43,  // 94: aisociety.workflow.WorkflowService.CreateTrigger:input_type -> aisociety.workflow.CreateTriggerRequest
+	45,  // 95: aisociety.workflow.WorkflowService.ListTriggers:input_type -> aisociety.workflow.ListTriggersRequest
+	47,  // 96: aisociety.workflow.WorkflowService.DeleteTrigger:input_type -> aisociety.workflow.DeleteTriggerRequest
+	49,  // 97: aisociety.workflow.WorkflowService.ListPendingApprovals:input_type -> aisociety.workflow.ListPendingApprovalsRequest
+	52,  // 98: aisociety.workflow.WorkflowService.ApproveNode:input_type -> aisociety.workflow.ApproveNodeRequest
+	54,  // 99: aisociety.workflow.WorkflowService.RejectNode:input_type -> aisociety.workflow.RejectNodeRequest
+	56,  // 100: aisociety.workflow.NodeService.ExecuteNode:input_type -> aisociety.workflow.ExecuteNodeRequest
+	58,  // 101: aisociety.workflow.NodeService.GetCapabilities:input_type -> aisociety.workflow.GetCapabilitiesRequest
+	21,  // 102: aisociety.workflow.WorkflowService.CreateWorkflow:output_type -> aisociety.workflow.CreateWorkflowResponse
+	23,  // 103: aisociety.workflow.WorkflowService.GetWorkflow:output_type -> aisociety.workflow.GetWorkflowResponse
+	26,  // 104: aisociety.workflow.WorkflowService.ListWorkflows:output_type -> aisociety.workflow.ListWorkflowsResponse
+	28,  // 105: aisociety.workflow.WorkflowService.UpdateWorkflow:output_type -> aisociety.workflow.UpdateWorkflowResponse
+	30,  // 106: aisociety.workflow.WorkflowService.GetNode:output_type -> aisociety.workflow.GetNodeResponse
+	33,  // 107: aisociety.workflow.WorkflowService.UpdateNode:output_type -> aisociety.workflow.UpdateNodeResponse
+	35,  // 108: aisociety.workflow.WorkflowService.CancelWorkflow:output_type -> aisociety.workflow.CancelWorkflowResponse
+	37,  // 109: aisociety.workflow.WorkflowService.CancelNode:output_type -> aisociety.workflow.CancelNodeResponse
+	39,  // 110: aisociety.workflow.WorkflowService.PauseWorkflow:output_type -> aisociety.workflow.PauseWorkflowResponse
+	41,  // 111: aisociety.workflow.WorkflowService.ResumeWorkflow:output_type -> aisociety.workflow.ResumeWorkflowResponse
+	44,  // 112: aisociety.workflow.WorkflowService.CreateTrigger:output_type -> aisociety.workflow.CreateTriggerResponse
+	46,  // 113: aisociety.workflow.WorkflowService.ListTriggers:output_type -> aisociety.workflow.ListTriggersResponse
+	48,  // 114: aisociety.workflow.WorkflowService.DeleteTrigger:output_type -> aisociety.workflow.DeleteTriggerResponse
+	51,  // 115: aisociety.workflow.WorkflowService.ListPendingApprovals:output_type -> aisociety.workflow.ListPendingApprovalsResponse
+	53,  // 116: aisociety.workflow.WorkflowService.ApproveNode:output_type -> aisociety.workflow.ApproveNodeResponse
+	55,  // 117: aisociety.workflow.WorkflowService.RejectNode:output_type -> aisociety.workflow.RejectNodeResponse
+	57,  // 118: aisociety.workflow.NodeService.ExecuteNode:output_type -> aisociety.workflow.ExecuteNodeResponse
+	59,  // 119: aisociety.workflow.NodeService.GetCapabilities:output_type -> aisociety.workflow.GetCapabilitiesResponse
+	102, // [102:120] is the sub-list for method output_type
+	84,  // [84:102] is the sub-list for method input_type
+	84,  // [84:84] is the sub-list for extension type_name
+	84,  // [84:84] is the sub-list for extension extendee
+	0,   // [0:84] is the sub-list for field type_name
 }
 
 func init() { file_protos_workflow_node_proto_init() }
@@ -1861,14 +4501,14 @@ func file_protos_workflow_node_proto_init() {
 	if File_protos_workflow_node_proto != nil {
 		return
 	}
-	file_protos_workflow_node_proto_msgTypes[26].OneofWrappers = []any{}
+	file_protos_workflow_node_proto_msgTypes[62].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_workflow_node_proto_rawDesc), len(file_protos_workflow_node_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   27,
+			NumEnums:      6,
+			NumMessages:   63,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -164,6 +164,9 @@ func callRealAgent(ctx context.Context, agent *pb.Agent, prompt string, toolRegi
 
 // callFakeAgent simulates an agent response for testing
 func callFakeAgent(ctx context.Context, agent *pb.Agent, prompt string, taskGoal string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	// Simulate some processing time if needed
 	// time.Sleep(10 * time.Millisecond)
 	return fmt.Sprintf("Fake agent response for task: %s", taskGoal), nil
@@ -0,0 +1,26 @@
+package node
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// GetCapabilities advertises the agents this server accepts, as configured in
+// s.Capabilities, together with every tool currently in its registry.
+func (s *Server) GetCapabilities(ctx context.Context, req *pb.GetCapabilitiesRequest) (*pb.GetCapabilitiesResponse, error) {
+	caps := &pb.NodeCapabilities{}
+	if s.Capabilities != nil {
+		caps = proto.Clone(s.Capabilities).(*pb.NodeCapabilities)
+	}
+	caps.Tools = nil
+	if s.toolHandler != nil && s.toolHandler.Registry() != nil {
+		for _, tool := range s.toolHandler.Registry().ListTools() {
+			caps.Tools = append(caps.Tools, tool.Name)
+		}
+		sort.Strings(caps.Tools)
+	}
+	return &pb.GetCapabilitiesResponse{Capabilities: caps}, nil
+}
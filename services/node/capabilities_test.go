@@ -0,0 +1,33 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	registry := NewInMemoryToolRegistry([]MCPTool{{Name: "b.tool"}, {Name: "a.tool"}})
+	s := NewServerWithRegistry(registry)
+	s.Capabilities = &pb.NodeCapabilities{
+		ModelTypes: []string{"GPT-4"},
+		Tools:      []string{"ignored"},
+	}
+
+	resp, err := s.GetCapabilities(context.Background(), &pb.GetCapabilitiesRequest{})
+	if err != nil {
+		t.Fatalf("GetCapabilities failed: %v", err)
+	}
+	caps := resp.GetCapabilities()
+	if !reflect.DeepEqual(caps.GetModelTypes(), []string{"GPT-4"}) {
+		t.Errorf("ModelTypes = %v, want [GPT-4]", caps.GetModelTypes())
+	}
+	if !reflect.DeepEqual(caps.GetTools(), []string{"a.tool", "b.tool"}) {
+		t.Errorf("Tools = %v, want the registry's tools", caps.GetTools())
+	}
+	if len(s.Capabilities.Tools) != 1 {
+		t.Errorf("GetCapabilities modified the configured capabilities: %v", s.Capabilities)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
 
 	pb "paul.hobbs.page/aisociety/protos"
 	"paul.hobbs.page/aisociety/services/node"
@@ -47,8 +48,15 @@ func main() {
 	}
 
 	// Pass the registry to the NodeService
+	nodeServer := node.NewServerWithRegistry(toolRegistry)
+	nodeServer.Capabilities = &pb.NodeCapabilities{
+		AgentIds:   envList("NODE_AGENT_IDS"),
+		Roles:      envList("NODE_ROLES"),
+		ModelTypes: envList("NODE_MODEL_TYPES"),
+	}
+
 	s := grpc.NewServer()
-	pb.RegisterNodeServiceServer(s, node.NewServerWithRegistry(toolRegistry))
+	pb.RegisterNodeServiceServer(s, nodeServer)
 
 	// Enable reflection for debugging and testing (optional but recommended)
 	reflection.Register(s)
@@ -58,3 +66,14 @@ func main() {
 		panic(fmt.Sprintf("failed to serve: %v", err))
 	}
 }
+
+// envList reads a comma-separated list from the environment.
+func envList(name string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
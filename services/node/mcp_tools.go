@@ -161,6 +161,9 @@ type MCPToolAdapter interface {
 type MockToolAdapter struct{}
 
 func (a *MockToolAdapter) Invoke(ctx context.Context, tool MCPTool, input map[string]interface{}) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// For testing, just echo the input and tool name.
 	return map[string]interface{}{
 		"status":  "PASS",
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	pb "paul.hobbs.page/aisociety/protos"
 )
@@ -12,6 +14,10 @@ import (
 type Server struct {
 	pb.UnimplementedNodeServiceServer
 	toolHandler ToolHandler // Added ToolHandler field
+
+	// Capabilities restricts which agents the scheduler routes here. Its
+	// tools are ignored; GetCapabilities reports the registry's tools instead.
+	Capabilities *pb.NodeCapabilities
 }
 
 // NewServer creates a new Node service server.
@@ -32,6 +38,9 @@ func NewServerWithRegistry(registry MCPToolRegistry) *Server {
 
 func (s *Server) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
 	fmt.Printf("ExecuteNode request received for node: %s in workflow: %s\n", req.GetNodeId(), req.GetWorkflowId())
+	if err := interrupted(ctx); err != nil {
+		return nil, err
+	}
 
 	node := req.GetNode()
 	if node == nil {
@@ -82,6 +91,9 @@ func (s *Server) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*
 		}
 		if registry != nil {
 			resultMap, err := InvokeMCPTool(ctx, toolName, inputParams, registry, adapterSelector)
+			if err := interrupted(ctx); err != nil {
+				return nil, err
+			}
 			updatedNode = proto.Clone(node).(*pb.Node)
 			assignedTask := updatedNode.GetAssignedTask()
 			if assignedTask == nil {
@@ -140,6 +152,7 @@ func (s *Server) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*
 	for _, upstream := range req.GetUpstreamNodes() {
 		if upstream != nil && upstream.GetAssignedTask() != nil {
 			prompt += "- " + upstream.GetAssignedTask().GetGoal() + "\n"
+			prompt += describeResult(upstream.GetAssignedTask())
 		}
 	}
 
@@ -156,6 +169,9 @@ func (s *Server) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*
 	}
 	// --- End Agent Selection ---
 
+	if err := interrupted(ctx); err != nil {
+		return nil, err
+	}
 	if agentErr != nil {
 		// Handle agent error - maybe set node status to TASK_ERROR
 		updatedNode := proto.Clone(node).(*pb.Node)
@@ -194,6 +210,31 @@ func (s *Server) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*
 	}, nil
 }
 
+// describeResult renders the latest result of an upstream task for the prompt.
+func describeResult(task *pb.Task) string {
+	results := task.GetResults()
+	if len(results) == 0 {
+		return ""
+	}
+	result := results[len(results)-1]
+	out := ""
+	if result.GetSummary() != "" {
+		out += "  Summary: " + result.GetSummary() + "\n"
+	}
+	if result.GetOutput() != "" {
+		out += "  Output: " + result.GetOutput() + "\n"
+	}
+	names := make([]string, 0, len(result.GetArtifacts()))
+	for name := range result.GetArtifacts() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out += "  Artifact " + name + ": " + result.GetArtifacts()[name] + "\n"
+	}
+	return out
+}
+
 // truncate returns the first n characters of s, or s itself if shorter
 func truncate(s string, n int) string {
 	if len(s) <= n {
@@ -201,3 +242,13 @@ func truncate(s string, n int) string {
 	}
 	return s[:n]
 }
+
+// interrupted returns a gRPC error if the caller canceled the request or its
+// deadline passed, so that an aborted execution is not reported as a failure
+// of the task itself.
+func interrupted(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
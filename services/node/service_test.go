@@ -0,0 +1,55 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func TestDescribeResult(t *testing.T) {
+	task := &pb.Task{Results: []*pb.Task_Result{
+		{Summary: "stale"},
+		{
+			Summary:   "found 3 papers",
+			Output:    "a, b, c",
+			Artifacts: map[string]string{"z.csv": "s3://z", "a.json": "s3://a"},
+		},
+	}}
+	want := "  Summary: found 3 papers\n" +
+		"  Output: a, b, c\n" +
+		"  Artifact a.json: s3://a\n" +
+		"  Artifact z.csv: s3://z\n"
+	if got := describeResult(task); got != want {
+		t.Errorf("describeResult() = %q, want %q", got, want)
+	}
+	if got := describeResult(&pb.Task{}); got != "" {
+		t.Errorf("describeResult() of a task without results = %q, want empty", got)
+	}
+}
+
+func TestInterruptedExecution(t *testing.T) {
+	registry := NewInMemoryToolRegistry([]MCPTool{{
+		Name:         "knowledge-base-curator.summarize",
+		Version:      "v1.0.0",
+		InputSchema:  map[string]string{},
+		OutputSchema: map[string]string{"summary": "string"},
+	}})
+	server := NewServerWithRegistry(registry)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, goal := range []string{"Summarize the findings", "Call: knowledge-base-curator.summarize"} {
+		req := &pb.ExecuteNodeRequest{Node: &pb.Node{
+			NodeId:       "node-1",
+			Agent:        &pb.Agent{AgentId: FakeAgentID},
+			AssignedTask: &pb.Task{Goal: goal},
+		}}
+		resp, err := server.ExecuteNode(ctx, req)
+		if status.Code(err) != codes.Canceled {
+			t.Errorf("%q: expected a Canceled error instead of a result, got %v, %v", goal, resp, err)
+		}
+	}
+}
@@ -4,13 +4,19 @@ import (
 	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v4/pgxpool"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	pb "paul.hobbs.page/aisociety/protos"
 	scheduler "paul.hobbs.page/aisociety/services/scheduler/internal"
 	"paul.hobbs.page/aisociety/services/workflow/persistence"
+	"paul.hobbs.page/aisociety/services/workflow/schema"
 )
 
 type grpcNodeClientWrapper struct {
@@ -21,6 +27,10 @@ func (w *grpcNodeClientWrapper) ExecuteNode(ctx context.Context, req *pb.Execute
 	return w.client.ExecuteNode(ctx, req)
 }
 
+func (w *grpcNodeClientWrapper) GetCapabilities(ctx context.Context, req *pb.GetCapabilitiesRequest) (*pb.GetCapabilitiesResponse, error) {
+	return w.client.GetCapabilities(ctx, req)
+}
+
 func main() {
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -28,27 +38,100 @@ func main() {
 	}
 
 	ctx := context.Background()
-	sm, err := persistence.NewPostgresStateManagerFromConnStr(ctx, dbURL)
+	pool, err := pgxpool.Connect(ctx, dbURL)
 	if err != nil {
-		log.Fatalf("failed to create state manager: %v", err)
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	// Replicas of either service may apply pending migrations; none runs
+	// against a schema migrated by a newer release.
+	if err := schema.Up(ctx, pool); err != nil {
+		log.Fatalf("failed to migrate database: %v", err)
+	}
+	sm := persistence.NewPostgresStateManager(pool)
+	sm.EditPolicy = persistence.EditPolicy{
+		MaxNodes:     envInt("SCHEDULER_MAX_WORKFLOW_NODES"),
+		MaxEditDepth: int32(envInt("SCHEDULER_MAX_EDIT_DEPTH")),
 	}
 
-	nodeTarget := os.Getenv("NODE_TARGET")
-	if nodeTarget == "" {
-		nodeTarget = "localhost:50051"
+	// NODE_TARGETS lists every NodeService backend; NODE_TARGET is the
+	// single-backend form it replaces.
+	nodeTargets := os.Getenv("NODE_TARGETS")
+	if nodeTargets == "" {
+		nodeTargets = os.Getenv("NODE_TARGET")
 	}
+	if nodeTargets == "" {
+		nodeTargets = "localhost:50051"
+	}
+
+	nodePool := scheduler.NewNodeServicePool()
+	for _, target := range strings.Split(nodeTargets, ",") {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalf("failed to connect to node service %s: %v", target, err)
+		}
+		defer conn.Close()
+		nodePool.Add(target, &grpcNodeClientWrapper{client: pb.NewNodeServiceClient(conn)})
+	}
+	nodePool.Refresh(ctx)
+
+	// SIGTERM or SIGINT stops the scheduler from claiming nodes; Run then
+	// drains in-flight dispatches before returning.
+	runCtx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	go nodePool.Run(runCtx, scheduler.DefaultCapabilityRefreshInterval)
 
-	conn, err := grpc.NewClient(nodeTarget, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Node events from Postgres drive scheduling; polling only catches
+	// anything a dropped subscription missed.
+	sched := scheduler.NewSimpleScheduler(sm, nodePool, scheduler.DefaultFallbackPollInterval)
+	sched.Events = sm
+	if id := os.Getenv("SCHEDULER_ID"); id != "" {
+		sched.ID = id
+	}
+	policy, err := scheduler.ParsePolicy(os.Getenv("SCHEDULER_POLICY"))
 	if err != nil {
-		log.Fatalf("failed to connect to node service: %v", err)
+		log.Fatalf("invalid SCHEDULER_POLICY: %v", err)
+	}
+	sched.Policy = policy
+	sched.ContextBudget = envInt("SCHEDULER_CONTEXT_BUDGET_BYTES")
+	sched.Limits = scheduler.DispatchLimits{
+		Global:      envInt("SCHEDULER_MAX_CONCURRENCY"),
+		PerWorkflow: envInt("SCHEDULER_MAX_PER_WORKFLOW"),
+		PerModel:    envInt("SCHEDULER_MAX_PER_MODEL"),
 	}
-	defer conn.Close()
+	sched.DrainTimeout = envDuration("SCHEDULER_DRAIN_TIMEOUT")
 
-	nodeClient := pb.NewNodeServiceClient(conn)
-	wrappedClient := &grpcNodeClientWrapper{client: nodeClient}
+	log.Printf("Starting scheduler %s...", sched.ID)
+	sched.Run(runCtx)
+}
 
-	sched := scheduler.NewSimpleScheduler(sm, wrappedClient, 2*time.Second)
+// envInt reads a non-negative integer from the environment, treating an unset
+// variable as zero.
+func envInt(name string) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Fatalf("invalid %s %q: must be a non-negative integer", name, v)
+	}
+	return n
+}
 
-	log.Println("Starting scheduler...")
-	sched.Run(ctx)
+// envDuration reads a non-negative duration such as "45s" from the
+// environment, treating an unset variable as zero.
+func envDuration(name string) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Fatalf("invalid %s %q: must be a non-negative duration", name, v)
+	}
+	return d
 }
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// builtinStatus reports whether node is of a type the scheduler handles
+// itself, without the NodeService, and if so the status it records for the
+// claimed node.
+//
+// TIMER nodes only become ready once their not_before has passed, so claiming
+// one means it has fired; a timer therefore fires within PollInterval of
+// becoming due, or sooner if a node event triggers a scheduling pass first.
+// APPROVAL nodes wait for ApproveNode or RejectNode to decide their outcome.
+// MAP, REDUCE and BRANCH nodes only need an agent to produce their items,
+// summarize them or produce the result a branch tests; without one they pass
+// as soon as they are claimed. The state manager then expands a MAP node, or
+// takes a BRANCH node's branch.
+func builtinStatus(node *pb.Node) (pb.Status, bool) {
+	switch node.GetType() {
+	case pb.NodeType_TIMER:
+		return pb.Status_PASS, true
+	case pb.NodeType_APPROVAL:
+		return pb.Status_WAITING_FOR_APPROVAL, true
+	case pb.NodeType_MAP, pb.NodeType_REDUCE, pb.NodeType_BRANCH:
+		return pb.Status_PASS, node.GetAgent() == nil
+	}
+	return pb.Status_UNKNOWN, false
+}
+
+// completeBuiltin records a claimed node of a built-in type with status,
+// releasing its lease.
+func (s *SimpleScheduler) completeBuiltin(ctx context.Context, workflowID string, node *pb.Node, status pb.Status) *pb.Node {
+	node.Status = status
+	if err := s.StateManager.UpdateLeasedNode(ctx, workflowID, s.ID, node); errors.Is(err, persistence.ErrLeaseLost) {
+		log.Printf("Discarding %v node %s: lease lost before it was recorded", node.GetType(), node.NodeId)
+		return nil
+	} else if err != nil {
+		log.Printf("Failed to record %v node %s: %v", node.GetType(), node.NodeId, err)
+		return nil
+	}
+	return node
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func TestSchedulerRecordsBuiltinNodesWithoutDispatching(t *testing.T) {
+	tests := []struct {
+		nodeType pb.NodeType
+		want     pb.Status
+	}{
+		{pb.NodeType_TIMER, pb.Status_PASS},
+		{pb.NodeType_APPROVAL, pb.Status_WAITING_FOR_APPROVAL},
+		{pb.NodeType_MAP, pb.Status_PASS},
+		{pb.NodeType_BRANCH, pb.Status_PASS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nodeType.String(), func(t *testing.T) {
+			fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "gate", Type: tt.nodeType}}}
+			fakeClient := &FakeNodeServiceClient{}
+			sched := NewSimpleScheduler(fakeSM, fakeClient, 0)
+
+			sched.scheduleOnce(context.Background())
+			sched.Pool().Wait()
+
+			if fakeClient.Called {
+				t.Errorf("expected the node not to be sent to the NodeService")
+			}
+			fakeSM.mu.Lock()
+			defer fakeSM.mu.Unlock()
+			if len(fakeSM.updatedNodes) != 1 || fakeSM.updatedNodes[0].Status != tt.want {
+				t.Errorf("expected the node to be recorded as %v, got %v", tt.want, fakeSM.updatedNodes)
+			}
+		})
+	}
+}
+
+func TestSchedulerDiscardsBuiltinNodesWithLostLease(t *testing.T) {
+	fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "wait", Type: pb.NodeType_TIMER}}, leaseLost: true}
+	sched := NewSimpleScheduler(fakeSM, &FakeNodeServiceClient{}, 0)
+
+	sched.scheduleOnce(context.Background())
+	sched.Pool().Wait()
+
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if len(fakeSM.updatedNodes) != 0 {
+		t.Errorf("expected no update after the lease was lost, got %v", fakeSM.updatedNodes)
+	}
+}
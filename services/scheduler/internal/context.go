@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// DefaultContextBudget is the default cap, in serialized bytes, on the
+// upstream and downstream nodes attached to an ExecuteNodeRequest.
+const DefaultContextBudget = 256 << 10
+
+// attachContext loads the parents and children of req.Node and attaches them
+// as upstream and downstream context, within s.ContextBudget. Parents take
+// precedence over children. A relative that does not fit is first sent
+// without its result output, and dropped only if it still does not fit.
+func (s *SimpleScheduler) attachContext(ctx context.Context, req *pb.ExecuteNodeRequest) error {
+	node := req.Node
+	ids := append(append([]string(nil), node.ParentIds...), node.ChildIds...)
+	if len(ids) == 0 {
+		return nil
+	}
+	relatives, err := s.StateManager.GetNodes(ctx, req.WorkflowId, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load context of node %s: %w", node.NodeId, err)
+	}
+	byID := make(map[string]*pb.Node, len(relatives))
+	for _, n := range relatives {
+		byID[n.NodeId] = n
+	}
+
+	limit := s.ContextBudget
+	if limit <= 0 {
+		limit = DefaultContextBudget
+	}
+	budget := limit
+	fit := func(n *pb.Node) *pb.Node {
+		if size := proto.Size(n); size <= budget {
+			budget -= size
+			return n
+		}
+		if stripOutput(n) {
+			if size := proto.Size(n); size <= budget {
+				budget -= size
+				return n
+			}
+		}
+		log.Printf("Dropping context node %s from request for node %s: over the %d byte budget", n.NodeId, node.NodeId, limit)
+		return nil
+	}
+
+	req.UpstreamNodes, req.DownstreamNodes = nil, nil
+	for _, id := range node.ParentIds {
+		if n, ok := byID[id]; ok {
+			if c := fit(contextNode(n, true)); c != nil {
+				req.UpstreamNodes = append(req.UpstreamNodes, c)
+			}
+		}
+	}
+	for _, id := range node.ChildIds {
+		if n, ok := byID[id]; ok {
+			if c := fit(contextNode(n, false)); c != nil {
+				req.DownstreamNodes = append(req.DownstreamNodes, c)
+			}
+		}
+	}
+	return nil
+}
+
+// contextNode returns the parts of n an agent needs to see as a neighbour:
+// its identity, agent, status and task, plus the task's latest result if
+// withResult is set. Execution bookkeeping such as edits, attempts and the
+// full task tree is left out.
+func contextNode(n *pb.Node, withResult bool) *pb.Node {
+	c := &pb.Node{
+		NodeId:      n.NodeId,
+		Description: n.Description,
+		Agent:       n.Agent,
+		Status:      n.Status,
+	}
+	if task := n.GetAssignedTask(); task != nil {
+		c.AssignedTask = &pb.Task{Id: task.Id, Goal: task.Goal}
+		if results := task.GetResults(); withResult && len(results) > 0 {
+			c.AssignedTask.Results = []*pb.Task_Result{proto.Clone(results[len(results)-1]).(*pb.Task_Result)}
+		}
+	}
+	return c
+}
+
+// stripOutput removes the detailed result output from a context node, keeping
+// its summary and artifacts. It reports whether there was anything to remove.
+func stripOutput(n *pb.Node) bool {
+	stripped := false
+	for _, r := range n.GetAssignedTask().GetResults() {
+		if r.Output != "" {
+			r.Output = ""
+			stripped = true
+		}
+	}
+	return stripped
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func finishedNode(id, goal, output string) *pb.Node {
+	return &pb.Node{
+		NodeId: id,
+		Status: pb.Status_PASS,
+		AssignedTask: &pb.Task{Id: id + "-task", Goal: goal, Results: []*pb.Task_Result{
+			{Status: pb.Status_FAIL, Summary: "first try"},
+			{Status: pb.Status_PASS, Summary: goal + " done", Output: output, Artifacts: map[string]string{"log": "s3://" + id}},
+		}},
+		Attempts: []*pb.Attempt{{Number: 1}, {Number: 2}},
+		AllTasks: []*pb.Task{{Id: "noise"}},
+	}
+}
+
+func TestDispatchAttachesUpstreamAndDownstreamContext(t *testing.T) {
+	fakeSM := &FakeStateManager{nodes: map[string]*pb.Node{
+		"parent": finishedNode("parent", "research", "the findings"),
+		"child":  {NodeId: "child", Status: pb.Status_BLOCKED, AssignedTask: &pb.Task{Goal: "write"}},
+	}}
+	client := passingClient()
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+
+	node := &pb.Node{NodeId: "node1", ParentIds: []string{"parent", "missing"}, ChildIds: []string{"child"}, Status: pb.Status_RUNNING}
+	sched.dispatchNode(context.Background(), "wf-1", node)
+
+	if len(client.Requests) != 1 {
+		t.Fatalf("expected one ExecuteNode call, got %d", len(client.Requests))
+	}
+	req := client.Requests[0]
+	if len(req.UpstreamNodes) != 1 || len(req.DownstreamNodes) != 1 {
+		t.Fatalf("expected one upstream and one downstream node, got %d and %d", len(req.UpstreamNodes), len(req.DownstreamNodes))
+	}
+	up := req.UpstreamNodes[0]
+	results := up.GetAssignedTask().GetResults()
+	if len(results) != 1 || results[0].Summary != "research done" || results[0].Output != "the findings" || results[0].Artifacts["log"] != "s3://parent" {
+		t.Errorf("expected only the parent's latest result, got %v", results)
+	}
+	if len(up.Attempts) != 0 || len(up.AllTasks) != 0 {
+		t.Errorf("expected execution bookkeeping to be stripped from context, got %v", up)
+	}
+	if down := req.DownstreamNodes[0]; down.NodeId != "child" || down.GetAssignedTask().GetGoal() != "write" {
+		t.Errorf("unexpected downstream node %v", down)
+	}
+}
+
+func TestContextBudget(t *testing.T) {
+	big := strings.Repeat("x", 4000)
+	fakeSM := &FakeStateManager{nodes: map[string]*pb.Node{
+		"p1": finishedNode("p1", "first", big),
+		"p2": finishedNode("p2", "second", big),
+		"c1": finishedNode("c1", "child", big),
+	}}
+	sched := NewSimpleScheduler(fakeSM, passingClient(), time.Hour)
+	sched.ContextBudget = 4500
+
+	req := &pb.ExecuteNodeRequest{WorkflowId: "wf-1", Node: &pb.Node{
+		NodeId: "n", ParentIds: []string{"p1", "p2"}, ChildIds: []string{"c1"},
+	}}
+	if err := sched.attachContext(context.Background(), req); err != nil {
+		t.Fatalf("attachContext failed: %v", err)
+	}
+
+	// p1 fits whole; p2 only fits without its output; c1 is sent without results.
+	if len(req.UpstreamNodes) != 2 {
+		t.Fatalf("expected both parents, got %d", len(req.UpstreamNodes))
+	}
+	if out := req.UpstreamNodes[0].AssignedTask.Results[0].Output; out != big {
+		t.Errorf("expected the first parent's output to be kept")
+	}
+	second := req.UpstreamNodes[1].AssignedTask.Results[0]
+	if second.Output != "" || second.Summary != "second done" {
+		t.Errorf("expected the second parent to keep only its summary, got %v", second)
+	}
+	if len(req.DownstreamNodes) != 1 || len(req.DownstreamNodes[0].AssignedTask.Results) != 0 {
+		t.Errorf("expected the child without results, got %v", req.DownstreamNodes)
+	}
+
+	sched.ContextBudget = 10
+	if err := sched.attachContext(context.Background(), req); err != nil {
+		t.Fatalf("attachContext failed: %v", err)
+	}
+	if len(req.UpstreamNodes) != 0 || len(req.DownstreamNodes) != 0 {
+		t.Errorf("expected nodes over budget to be dropped, got %v / %v", req.UpstreamNodes, req.DownstreamNodes)
+	}
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// DefaultDrainTimeout is how long a stopping scheduler waits for in-flight
+// dispatches when SimpleScheduler.DrainTimeout is zero.
+const DefaultDrainTimeout = 30 * time.Second
+
+// drain shuts dispatching down gracefully. Queued nodes have not started, so
+// their claims are released straight away; running dispatches get up to
+// DrainTimeout to record their results. Nodes still running after that are
+// released and their dispatches canceled. ctx must outlive the scheduling
+// loop's context, and cancelDispatches must cancel the dispatches' contexts.
+func (s *SimpleScheduler) drain(ctx context.Context, cancelDispatches context.CancelFunc) {
+	pool := s.Pool()
+	s.releaseClaims(ctx, pool.DrainQueue())
+
+	done := make(chan struct{})
+	go func() {
+		pool.Wait()
+		close(done)
+	}()
+	timeout := s.DrainTimeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	if running := pool.Metrics().Running; running > 0 {
+		log.Printf("Waiting up to %s for %d in-flight dispatches to finish", timeout, running)
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return
+	case <-timer.C:
+	}
+
+	// Release before canceling, so that the interrupted dispatches find their
+	// leases gone and discard their results instead of recording failures.
+	unfinished := pool.InFlight()
+	log.Printf("Drain timed out; releasing %d unfinished nodes", len(unfinished))
+	s.releaseClaims(ctx, unfinished)
+	cancelDispatches()
+	<-done
+}
+
+// releaseClaims hands nodes this scheduler claimed but will not finish back
+// to the ready pool.
+func (s *SimpleScheduler) releaseClaims(ctx context.Context, nodes []*persistence.ReadyNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	released, err := s.StateManager.ReleaseClaims(ctx, s.ID, nodes)
+	if err != nil {
+		log.Printf("Failed to release claims on %d nodes: %v", len(nodes), err)
+		return
+	}
+	log.Printf("Released claims on %d of %d nodes", released, len(nodes))
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// gatedClient passes each node once release is closed.
+type gatedClient struct {
+	started chan string
+	release chan struct{}
+}
+
+func (c *gatedClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	c.started <- req.NodeId
+	select {
+	case <-c.release:
+		return &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: req.NodeId, Status: pb.Status_PASS}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runUntilStopped runs sched in the background, returning a channel closed
+// when Run returns.
+func runUntilStopped(ctx context.Context, sched *SimpleScheduler) <-chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		sched.Run(ctx)
+		close(stopped)
+	}()
+	return stopped
+}
+
+func TestSchedulerDrainsInFlightDispatches(t *testing.T) {
+	fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "running"}, {NodeId: "queued"}}}
+	client := &gatedClient{started: make(chan string, 2), release: make(chan struct{})}
+	sched := NewSimpleScheduler(fakeSM, client, 5*time.Millisecond)
+	sched.Limits = DispatchLimits{PerWorkflow: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := runUntilStopped(ctx, sched)
+	if id := <-client.started; id != "running" {
+		t.Fatalf("started %s, want running", id)
+	}
+	// The scheduler leaves the second node alone while its workflow is at
+	// its limit; claim and submit it by hand so that it waits in the queue.
+	claimed, _ := fakeSM.ClaimNodes(ctx, sched.ID, []*persistence.ReadyNode{{WorkflowID: "wf-test", Node: fakeSM.readyNodes[1]}})
+	sched.Pool().Submit(ctx, claimed[0], func(context.Context, *persistence.ReadyNode) {})
+	if queued := sched.Pool().Queued(); len(queued) != 1 {
+		t.Fatalf("expected the second node to be queued, got %d queued", len(queued))
+	}
+	cancel()
+
+	waitFor(t, "the queued node to be released", func() bool {
+		fakeSM.mu.Lock()
+		defer fakeSM.mu.Unlock()
+		return len(fakeSM.released) == 1
+	})
+	select {
+	case <-stopped:
+		t.Fatal("Run returned before the in-flight dispatch finished")
+	default:
+	}
+	close(client.release)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after draining")
+	}
+
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if got := fmt.Sprint(fakeSM.released); got != "[queued]" {
+		t.Errorf("released %s, want [queued]", got)
+	}
+	if len(fakeSM.updatedNodes) != 1 || fakeSM.updatedNodes[0].Status != pb.Status_PASS {
+		t.Errorf("expected the in-flight node's result to be recorded, got %v", fakeSM.updatedNodes)
+	}
+}
+
+func TestSchedulerDrainTimeoutReleasesRunningNodes(t *testing.T) {
+	fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "node1"}}}
+	client := &recordingBlockingClient{started: make(chan struct{}), done: make(chan struct{})}
+	sched := NewSimpleScheduler(fakeSM, client, 5*time.Millisecond)
+	sched.DrainTimeout = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := runUntilStopped(ctx, sched)
+	<-client.started
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the drain timeout")
+	}
+
+	select {
+	case <-client.done:
+	default:
+		t.Error("expected the running dispatch to be canceled")
+	}
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if got := fmt.Sprint(fakeSM.released); got != "[node1]" {
+		t.Errorf("released %s, want [node1]", got)
+	}
+	if len(fakeSM.updatedNodes) != 0 {
+		t.Errorf("expected the interrupted node's result to be discarded, got %v", fakeSM.updatedNodes)
+	}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultFallbackPollInterval is the poll interval to use alongside a
+// NodeEventSource. Polling then only covers missed notifications, so it can
+// be slow.
+const DefaultFallbackPollInterval = 30 * time.Second
+
+// NodeEventSource delivers the ID of a workflow whenever some of its nodes may
+// have become ready. The channel is closed when the subscription ends.
+type NodeEventSource interface {
+	ListenNodeEvents(ctx context.Context) (<-chan string, error)
+}
+
+// subscribeNodeEvents opens a subscription on s.Events, returning nil if there
+// is no event source or subscribing failed.
+func (s *SimpleScheduler) subscribeNodeEvents(ctx context.Context) <-chan string {
+	if s.Events == nil {
+		return nil
+	}
+	events, err := s.Events.ListenNodeEvents(ctx)
+	if err != nil {
+		log.Printf("Failed to subscribe to node events, polling every %s: %v", s.PollInterval, err)
+		return nil
+	}
+	return events
+}
+
+// drainNodeEvents adds the workflow IDs of events already buffered on events
+// to workflowIDs, so that a burst of notifications triggers a single
+// scheduling pass. It reports whether the subscription is still open.
+func drainNodeEvents(events <-chan string, workflowIDs map[string]bool) bool {
+	for {
+		select {
+		case workflowID, ok := <-events:
+			if !ok {
+				return false
+			}
+			workflowIDs[workflowID] = true
+		default:
+			return true
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// fakeEventSource hands out subscriptions whose channels the test controls.
+type fakeEventSource struct {
+	mu            sync.Mutex
+	err           error
+	subscriptions []chan string
+}
+
+func (f *fakeEventSource) ListenNodeEvents(ctx context.Context) (<-chan string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	ch := make(chan string, 8)
+	f.subscriptions = append(f.subscriptions, ch)
+	return ch, nil
+}
+
+func (f *fakeEventSource) subscription(i int) chan string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i >= len(f.subscriptions) {
+		return nil
+	}
+	return f.subscriptions[i]
+}
+
+func (f *fakeEventSource) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscriptions)
+}
+
+func (m *FakeStateManager) addReadyNode(node *pb.Node) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.readyNodes = append(m.readyNodes, node)
+}
+
+func (m *FakeStateManager) updateCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.updatedNodes)
+}
+
+func passingClient() *FakeNodeServiceClient {
+	return &FakeNodeServiceClient{
+		Response: &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: "node1", Status: pb.Status_PASS}},
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerDispatchesOnNodeEvent(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	events := &fakeEventSource{}
+	sched := NewSimpleScheduler(fakeSM, passingClient(), time.Hour)
+	sched.Events = events
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sched.Run(ctx)
+	waitFor(t, "subscription", func() bool { return events.count() == 1 })
+
+	// Without an event the hour-long poll would never pick this node up.
+	fakeSM.addReadyNode(&pb.Node{NodeId: "node1", Status: pb.Status_READY})
+	events.subscription(0) <- "wf-test"
+	waitFor(t, "node1 to be dispatched", func() bool { return fakeSM.updateCount() == 1 })
+}
+
+func TestSchedulerResubscribesAfterEventStreamCloses(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	events := &fakeEventSource{}
+	sched := NewSimpleScheduler(fakeSM, passingClient(), 10*time.Millisecond)
+	sched.Events = events
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sched.Run(ctx)
+	waitFor(t, "subscription", func() bool { return events.count() == 1 })
+
+	close(events.subscription(0))
+	waitFor(t, "resubscription", func() bool { return events.count() == 2 })
+}
+
+func TestSchedulerPollsWhenSubscribeFails(t *testing.T) {
+	fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "node1", Status: pb.Status_READY}}}
+	sched := NewSimpleScheduler(fakeSM, passingClient(), 10*time.Millisecond)
+	sched.Events = &fakeEventSource{err: errors.New("listen failed")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sched.Run(ctx)
+	waitFor(t, "node1 to be dispatched by polling", func() bool { return fakeSM.updateCount() == 1 })
+}
+
+func TestSchedulerCancelsRevokedNodeOnEvent(t *testing.T) {
+	fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "node1", Status: pb.Status_READY}}}
+	events := &fakeEventSource{}
+	client := &recordingBlockingClient{started: make(chan struct{}), done: make(chan struct{})}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+	sched.Events = events
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sched.Run(ctx)
+	waitFor(t, "subscription", func() bool { return events.count() == 1 })
+	events.subscription(0) <- "wf-test"
+	<-client.started
+
+	// Cancel the node as CancelNode would, then notify; the hour-long
+	// heartbeat must not be needed to stop it.
+	fakeSM.mu.Lock()
+	fakeSM.revoked = map[string]bool{"node1": true}
+	fakeSM.mu.Unlock()
+	events.subscription(0) <- "wf-test"
+	select {
+	case <-client.done:
+	case <-time.After(time.Second):
+		t.Fatal("canceled node was not interrupted")
+	}
+	sched.Pool().Wait()
+	if n := fakeSM.updateCount(); n != 0 {
+		t.Errorf("expected the canceled node's result to be discarded, got %d updates", n)
+	}
+}
+
+// recordingBlockingClient runs a single node until its context is done.
+type recordingBlockingClient struct {
+	started chan struct{}
+	done    chan struct{}
+}
+
+func (c *recordingBlockingClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	close(c.started)
+	<-ctx.Done()
+	close(c.done)
+	return nil, ctx.Err()
+}
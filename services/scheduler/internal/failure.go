@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"log"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// applyFailurePolicy stops this scheduler's other work on a workflow after
+// one of its nodes failed under FAIL_FAST. Recording the failure has already
+// skipped the workflow's unfinished nodes and revoked their leases, so
+// canceled dispatches discard their results; replicas running nodes of the
+// same workflow notice on their next heartbeat. Other policies are applied
+// entirely by the state manager.
+func (s *SimpleScheduler) applyFailurePolicy(ready *persistence.ReadyNode, final *pb.Node) {
+	policy := persistence.EffectiveFailurePolicy(ready.Node, ready.WorkflowFailurePolicy)
+	if !persistence.IsFailureStatus(final.Status) || policy != pb.FailurePolicy_FAIL_FAST {
+		return
+	}
+	log.Printf("Node %s failed with %v; canceling the rest of workflow %s", final.NodeId, final.Status, ready.WorkflowID)
+	s.Pool().CancelWorkflow(ready.WorkflowID)
+}
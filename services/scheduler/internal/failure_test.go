@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// siblingClient fails node "a" once node "b" is running. Node "b" passes when
+// release is closed, or gives up when its context is canceled.
+type siblingClient struct {
+	bStarted chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func (c *siblingClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	if req.NodeId == "a" {
+		<-c.bStarted
+		return &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: "a", Status: pb.Status_FAIL}}, nil
+	}
+	close(c.bStarted)
+	select {
+	case <-c.release:
+		return &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: "b", Status: pb.Status_PASS}}, nil
+	case <-ctx.Done():
+		close(c.canceled)
+		return nil, ctx.Err()
+	}
+}
+
+func TestFailurePolicyCancelsSiblings(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       pb.FailurePolicy
+		nodePolicy   pb.FailurePolicy
+		wantCanceled bool
+	}{
+		{name: "fail fast", policy: pb.FailurePolicy_FAIL_FAST, wantCanceled: true},
+		{name: "node overrides workflow", policy: pb.FailurePolicy_CONTINUE, nodePolicy: pb.FailurePolicy_FAIL_FAST, wantCanceled: true},
+		{name: "skip descendants", policy: pb.FailurePolicy_SKIP_DESCENDANTS},
+		{name: "default", policy: pb.FailurePolicy_FAILURE_POLICY_UNSPECIFIED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ready []*persistence.ReadyNode
+			for _, id := range []string{"a", "b"} {
+				rn := readyNode("wf-1", id, "")
+				rn.Node.FailurePolicy = tt.nodePolicy
+				rn.WorkflowFailurePolicy = tt.policy
+				ready = append(ready, rn)
+			}
+			fakeSM := &FakeStateManager{ready: ready, failFast: tt.wantCanceled}
+			client := &siblingClient{
+				bStarted: make(chan struct{}),
+				release:  make(chan struct{}),
+				canceled: make(chan struct{}),
+			}
+			sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+			sched.scheduleOnce(context.Background())
+
+			waitFor(t, "the failure of node a to be recorded", func() bool { return fakeSM.updateCount() > 0 })
+			if tt.wantCanceled {
+				select {
+				case <-client.canceled:
+				case <-time.After(time.Second):
+					t.Fatal("expected node b to be canceled")
+				}
+			}
+			close(client.release)
+			sched.Pool().Wait()
+
+			fakeSM.mu.Lock()
+			defer fakeSM.mu.Unlock()
+			var recorded []string
+			for _, n := range fakeSM.updatedNodes {
+				recorded = append(recorded, n.NodeId+"="+n.Status.String())
+			}
+			want := "[a=FAIL b=PASS]"
+			if tt.wantCanceled {
+				want = "[a=FAIL]"
+			}
+			if got := fmt.Sprint(recorded); got != want {
+				t.Errorf("recorded %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDispatchPoolCancelWorkflow(t *testing.T) {
+	pool := NewDispatchPool(DispatchLimits{PerWorkflow: 1})
+	canceled := make(chan string, 2)
+	run := func(ctx context.Context, ready *persistence.ReadyNode) {
+		<-ctx.Done()
+		canceled <- ready.Node.NodeId
+	}
+	ctx := context.Background()
+	pool.Submit(ctx, readyNode("wf-1", "running", ""), run)
+	pool.Submit(ctx, readyNode("wf-1", "queued", ""), run)
+	pool.Submit(ctx, readyNode("wf-2", "other", ""), run)
+
+	if n := pool.CancelWorkflow("wf-1"); n != 2 {
+		t.Errorf("CancelWorkflow affected %d nodes, want 2", n)
+	}
+	select {
+	case id := <-canceled:
+		if id != "running" {
+			t.Errorf("canceled %s, want running", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("running node was not canceled")
+	}
+	if q := pool.Queued(); len(q) != 0 {
+		t.Errorf("expected the queued node to be dropped, still queued: %d", len(q))
+	}
+
+	pool.CancelWorkflow("wf-2")
+	pool.Wait()
+	if id := <-canceled; id != "other" {
+		t.Errorf("canceled %s, want other", id)
+	}
+}
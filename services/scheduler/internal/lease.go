@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often a scheduler renews the leases of
+	// the nodes it is dispatching. It must be comfortably shorter than the
+	// state manager's lease duration.
+	DefaultHeartbeatInterval = 1 * time.Minute
+
+	// DefaultReapInterval is how often a scheduler looks for expired leases.
+	DefaultReapInterval = 30 * time.Second
+
+	// reapBatchSize caps how many orphaned nodes are recovered per sweep.
+	reapBatchSize = 100
+)
+
+// LeaseRecoveryPolicy decides what happens to a RUNNING node whose lease
+// expired because the scheduler that claimed it stopped heartbeating.
+type LeaseRecoveryPolicy int
+
+const (
+	// RequeueOnExpiry returns orphaned nodes to the ready pool.
+	RequeueOnExpiry LeaseRecoveryPolicy = iota
+	// CrashOnExpiry marks orphaned nodes CRASH.
+	CrashOnExpiry
+	// TimeoutOnExpiry marks orphaned nodes TIMEOUT.
+	TimeoutOnExpiry
+)
+
+// status returns the node status the policy recovers orphaned nodes to.
+func (p LeaseRecoveryPolicy) status() pb.Status {
+	switch p {
+	case CrashOnExpiry:
+		return pb.Status_CRASH
+	case TimeoutOnExpiry:
+		return pb.Status_TIMEOUT
+	default:
+		return pb.Status_READY
+	}
+}
+
+// heartbeat renews the lease on a dispatched node every HeartbeatInterval
+// until ctx is done. If the lease is lost, onLost is called and heartbeating
+// stops; transient errors are logged and retried on the next beat.
+func (s *SimpleScheduler) heartbeat(ctx context.Context, workflowID, nodeID string, onLost func()) {
+	interval := s.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := s.StateManager.RenewLease(ctx, workflowID, nodeID, s.ID)
+			if errors.Is(err, persistence.ErrLeaseLost) {
+				log.Printf("Lease on node %s in workflow %s was lost", nodeID, workflowID)
+				onLost()
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to renew lease on node %s: %v", nodeID, err)
+			}
+		}
+	}
+}
+
+// reapExpiredLeases recovers RUNNING nodes whose lease has expired according
+// to the scheduler's LeaseRecovery policy.
+func (s *SimpleScheduler) reapExpiredLeases(ctx context.Context) {
+	status := s.LeaseRecovery.status()
+	recovered, err := s.StateManager.RecoverExpiredLeases(ctx, status,
+		"recovered by scheduler "+s.ID, reapBatchSize)
+	if err != nil {
+		log.Printf("Error recovering expired leases: %v", err)
+		return
+	}
+	for _, rn := range recovered {
+		log.Printf("Recovered orphaned node %s in workflow %s as %v", rn.Node.NodeId, rn.WorkflowID, status)
+	}
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// slowNodeClient takes delay to execute a node, or until its context is done.
+type slowNodeClient struct {
+	delay    time.Duration
+	canceled chan struct{}
+}
+
+func (c *slowNodeClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	select {
+	case <-time.After(c.delay):
+		return &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: req.NodeId, Status: pb.Status_PASS}}, nil
+	case <-ctx.Done():
+		close(c.canceled)
+		return nil, ctx.Err()
+	}
+}
+
+func TestDispatchRenewsLeaseWhileExecuting(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	client := &slowNodeClient{delay: 30 * time.Millisecond, canceled: make(chan struct{})}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+	sched.HeartbeatInterval = 5 * time.Millisecond
+
+	sched.dispatchNode(context.Background(), "wf-1", &pb.Node{NodeId: "slow", Status: pb.Status_RUNNING})
+
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if fakeSM.renewals < 2 {
+		t.Errorf("expected the lease to be renewed several times, got %d renewals", fakeSM.renewals)
+	}
+	if len(fakeSM.updatedNodes) != 1 || fakeSM.updatedNodes[0].Status != pb.Status_PASS {
+		t.Errorf("expected the PASS result to be persisted, got %v", fakeSM.updatedNodes)
+	}
+}
+
+func TestDispatchAbandonsNodeWhenLeaseLost(t *testing.T) {
+	fakeSM := &FakeStateManager{leaseLost: true}
+	client := &slowNodeClient{delay: time.Second, canceled: make(chan struct{})}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+	sched.HeartbeatInterval = 5 * time.Millisecond
+
+	done := make(chan struct{})
+	go func() {
+		sched.dispatchNode(context.Background(), "wf-1", &pb.Node{NodeId: "orphan", Status: pb.Status_RUNNING})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("dispatch did not stop after losing its lease")
+	}
+	select {
+	case <-client.canceled:
+	default:
+		t.Error("expected ExecuteNode to be canceled when the lease was lost")
+	}
+
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if len(fakeSM.updatedNodes) != 0 {
+		t.Errorf("expected no result to be persisted without a lease, got %v", fakeSM.updatedNodes)
+	}
+}
+
+func TestReaperRecoversExpiredLeasesPerPolicy(t *testing.T) {
+	tests := []struct {
+		policy LeaseRecoveryPolicy
+		want   pb.Status
+	}{
+		{RequeueOnExpiry, pb.Status_READY},
+		{CrashOnExpiry, pb.Status_CRASH},
+		{TimeoutOnExpiry, pb.Status_TIMEOUT},
+	}
+	for _, tc := range tests {
+		fakeSM := &FakeStateManager{
+			expired: []*persistence.ReadyNode{{WorkflowID: "wf-1", Node: &pb.Node{NodeId: "orphan"}}},
+		}
+		sched := NewSimpleScheduler(fakeSM, &FakeNodeServiceClient{}, time.Hour)
+		sched.ReapInterval = 5 * time.Millisecond
+		sched.LeaseRecovery = tc.policy
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		sched.Run(ctx)
+		cancel()
+
+		fakeSM.mu.Lock()
+		if len(fakeSM.recoveredStatuses) == 0 {
+			t.Errorf("policy %v: expected the reaper to run", tc.policy)
+		}
+		for _, got := range fakeSM.recoveredStatuses {
+			if got != tc.want {
+				t.Errorf("policy %v: recovered to %v, want %v", tc.policy, got, tc.want)
+			}
+		}
+		fakeSM.mu.Unlock()
+	}
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sort"
+)
+
+// releasePaused hands back the queued nodes of paused workflows, so that
+// pausing a workflow also holds back nodes claimed before the pause that have
+// not started yet. Only the given workflows are checked, or the workflows of
+// every queued node if workflowIDs is nil.
+func (s *SimpleScheduler) releasePaused(ctx context.Context, workflowIDs map[string]bool) {
+	pool := s.Pool()
+	queued := map[string]bool{}
+	for _, ready := range pool.Queued() {
+		if workflowIDs == nil || workflowIDs[ready.WorkflowID] {
+			queued[ready.WorkflowID] = true
+		}
+	}
+	if len(queued) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(queued))
+	for id := range queued {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	paused, err := s.StateManager.PausedWorkflows(ctx, ids)
+	if err != nil {
+		log.Printf("Failed to look for paused workflows: %v", err)
+		return
+	}
+	for _, workflowID := range paused {
+		if nodes := pool.DequeueWorkflow(workflowID); len(nodes) > 0 {
+			log.Printf("Workflow %s is paused; releasing %d queued nodes", workflowID, len(nodes))
+			s.releaseClaims(ctx, nodes)
+		}
+	}
+}
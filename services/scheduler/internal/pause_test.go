@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+func TestSchedulerReleasesQueuedNodesOfPausedWorkflows(t *testing.T) {
+	fakeSM := &FakeStateManager{readyNodes: []*pb.Node{{NodeId: "running"}}}
+	client := &gatedClient{started: make(chan string, 1), release: make(chan struct{})}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+	sched.Limits = DispatchLimits{PerWorkflow: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sched.scheduleOnce(ctx)
+	if id := <-client.started; id != "running" {
+		t.Fatalf("started %s, want running", id)
+	}
+	// Claimed before the pause, but waiting for its workflow's slot.
+	fakeSM.ready = []*persistence.ReadyNode{
+		{WorkflowID: "wf-test", Node: &pb.Node{NodeId: "queued"}},
+		{WorkflowID: "wf-other", Node: &pb.Node{NodeId: "other"}},
+	}
+	claimed, _ := fakeSM.ClaimNodes(ctx, sched.ID, fakeSM.ready)
+	sched.Pool().Submit(ctx, claimed[0], func(context.Context, *persistence.ReadyNode) {
+		t.Error("the queued node started after its workflow was paused")
+	})
+
+	fakeSM.mu.Lock()
+	fakeSM.paused = map[string]bool{"wf-test": true}
+	fakeSM.mu.Unlock()
+	sched.releasePaused(ctx, map[string]bool{"wf-test": true})
+
+	if queued := sched.Pool().Queued(); len(queued) != 0 {
+		t.Errorf("expected the paused workflow's queued node to be dropped, got %d queued", len(queued))
+	}
+	close(client.release)
+	sched.Pool().Wait()
+
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if got := fmt.Sprint(fakeSM.released); got != "[queued]" {
+		t.Errorf("released %s, want [queued]", got)
+	}
+	if len(fakeSM.updatedNodes) != 1 || fakeSM.updatedNodes[0].NodeId != "running" {
+		t.Errorf("expected the running node to finish despite the pause, got %v", fakeSM.updatedNodes)
+	}
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// Policy decides the order in which ready nodes are claimed and dispatched.
+type Policy interface {
+	// Order returns ready sorted into dispatch order. inFlight lists the nodes
+	// this scheduler is already running or has queued.
+	Order(ready, inFlight []*persistence.ReadyNode) []*persistence.ReadyNode
+}
+
+// FIFOPolicy dispatches nodes in the order they became ready.
+type FIFOPolicy struct{}
+
+func (FIFOPolicy) Order(ready, inFlight []*persistence.ReadyNode) []*persistence.ReadyNode {
+	ordered := append([]*persistence.ReadyNode(nil), ready...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ReadySince.Before(ordered[j].ReadySince)
+	})
+	return ordered
+}
+
+// PriorityPolicy dispatches nodes with the highest effective priority first,
+// oldest first among equals. Low-priority work waits for as long as
+// higher-priority work is ready.
+type PriorityPolicy struct{}
+
+func (PriorityPolicy) Order(ready, inFlight []*persistence.ReadyNode) []*persistence.ReadyNode {
+	ordered := append([]*persistence.ReadyNode(nil), ready...)
+	sortByPriority(ordered)
+	return ordered
+}
+
+// ShareKey selects what FairSharePolicy divides capacity between.
+type ShareKey int
+
+const (
+	// ShareByWorkflow gives every workflow an equal share.
+	ShareByWorkflow ShareKey = iota
+	// ShareByOwner gives every workflow owner an equal share; workflows
+	// without an owner are each their own share.
+	ShareByOwner
+)
+
+// FairSharePolicy interleaves nodes so that each workflow or owner gets
+// capacity in proportion to its weight, however many nodes it has ready.
+// Within a share, nodes are ordered as by PriorityPolicy.
+type FairSharePolicy struct {
+	By ShareKey
+	// Weights maps a workflow ID or owner to its relative share. Missing or
+	// non-positive entries count as 1.
+	Weights map[string]float64
+}
+
+func (p FairSharePolicy) Order(ready, inFlight []*persistence.ReadyNode) []*persistence.ReadyNode {
+	// Start each share from what it already has in flight, so a share that is
+	// busy yields to idle ones.
+	used := make(map[string]float64)
+	for _, rn := range inFlight {
+		used[p.key(rn)]++
+	}
+
+	queues := make(map[string][]*persistence.ReadyNode)
+	var keys []string
+	for _, rn := range ready {
+		k := p.key(rn)
+		if _, ok := queues[k]; !ok {
+			keys = append(keys, k)
+		}
+		queues[k] = append(queues[k], rn)
+	}
+	for _, k := range keys {
+		sortByPriority(queues[k])
+	}
+
+	ordered := make([]*persistence.ReadyNode, 0, len(ready))
+	for len(ordered) < len(ready) {
+		// Pick the share furthest below its entitlement, breaking ties in
+		// favour of whichever has been waiting longest.
+		next, found := "", false
+		for _, k := range keys {
+			if len(queues[k]) == 0 {
+				continue
+			}
+			if !found {
+				next, found = k, true
+				continue
+			}
+			a, b := used[k]/p.weight(k), used[next]/p.weight(next)
+			if a < b || (a == b && queues[k][0].ReadySince.Before(queues[next][0].ReadySince)) {
+				next = k
+			}
+		}
+		ordered = append(ordered, queues[next][0])
+		queues[next] = queues[next][1:]
+		used[next]++
+	}
+	return ordered
+}
+
+func (p FairSharePolicy) key(rn *persistence.ReadyNode) string {
+	if p.By == ShareByOwner && rn.Owner != "" {
+		return rn.Owner
+	}
+	return rn.WorkflowID
+}
+
+func (p FairSharePolicy) weight(key string) float64 {
+	if w := p.Weights[key]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+func sortByPriority(nodes []*persistence.ReadyNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if pi, pj := nodes[i].Priority(), nodes[j].Priority(); pi != pj {
+			return pi > pj
+		}
+		return nodes[i].ReadySince.Before(nodes[j].ReadySince)
+	})
+}
+
+// ParsePolicy returns the policy with the given name: "fifo", "priority",
+// "fair-share" (per workflow) or "fair-share-owner".
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case "", "fifo":
+		return FIFOPolicy{}, nil
+	case "priority":
+		return PriorityPolicy{}, nil
+	case "fair-share":
+		return FairSharePolicy{By: ShareByWorkflow}, nil
+	case "fair-share-owner":
+		return FairSharePolicy{By: ShareByOwner}, nil
+	default:
+		return nil, fmt.Errorf("unknown scheduling policy %q", name)
+	}
+}
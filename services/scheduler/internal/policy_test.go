@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+var policyEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// backlog returns n ready nodes of workflowID, named workflowID-0 onwards,
+// that became ready one second apart starting at offset seconds past the epoch.
+func backlog(workflowID, owner string, n, offset int) []*persistence.ReadyNode {
+	var nodes []*persistence.ReadyNode
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, &persistence.ReadyNode{
+			WorkflowID: workflowID,
+			Owner:      owner,
+			Node:       &pb.Node{NodeId: fmt.Sprintf("%s-%d", workflowID, i), Status: pb.Status_READY},
+			ReadySince: policyEpoch.Add(time.Duration(offset+i) * time.Second),
+		})
+	}
+	return nodes
+}
+
+func nodeIDs(nodes []*persistence.ReadyNode) []string {
+	var ids []string
+	for _, rn := range nodes {
+		ids = append(ids, rn.Node.NodeId)
+	}
+	return ids
+}
+
+func TestFIFOPolicyOrdersByReadySince(t *testing.T) {
+	ready := append(backlog("late", "", 1, 10), backlog("early", "", 2, 0)...)
+	got := nodeIDs(FIFOPolicy{}.Order(ready, nil))
+	want := []string{"early-0", "early-1", "late-0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Order() = %v, want %v", got, want)
+	}
+}
+
+func TestPriorityPolicy(t *testing.T) {
+	ready := backlog("low", "", 2, 0)
+	urgent := backlog("urgent", "", 1, 10)
+	urgent[0].WorkflowPriority = 10
+	bumped := backlog("bumped", "", 1, 20)
+	bumped[0].Node.Priority = 5
+	ready = append(ready, append(urgent, bumped...)...)
+
+	got := nodeIDs(PriorityPolicy{}.Order(ready, nil))
+	want := []string{"urgent-0", "bumped-0", "low-0", "low-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Order() = %v, want %v", got, want)
+	}
+}
+
+func TestFairSharePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   FairSharePolicy
+		ready    []*persistence.ReadyNode
+		inFlight []*persistence.ReadyNode
+		want     []string
+	}{
+		{
+			name:   "interleaves workflows",
+			policy: FairSharePolicy{},
+			ready:  append(backlog("big", "", 4, 0), backlog("small", "", 2, 100)...),
+			want:   []string{"big-0", "small-0", "big-1", "small-1", "big-2", "big-3"},
+		},
+		{
+			name:     "busy workflow yields",
+			policy:   FairSharePolicy{},
+			ready:    append(backlog("big", "", 2, 0), backlog("small", "", 2, 100)...),
+			inFlight: backlog("big", "", 2, 0),
+			want:     []string{"small-0", "small-1", "big-0", "big-1"},
+		},
+		{
+			name:   "weights",
+			policy: FairSharePolicy{Weights: map[string]float64{"heavy": 2}},
+			ready:  append(backlog("heavy", "", 6, 0), backlog("light", "", 6, 0)...),
+			want: []string{"heavy-0", "light-0", "heavy-1", "light-1", "heavy-2", "heavy-3",
+				"light-2", "heavy-4", "heavy-5", "light-3", "light-4", "light-5"},
+		},
+		{
+			name:   "shares by owner",
+			policy: FairSharePolicy{By: ShareByOwner},
+			ready: append(append(backlog("alice-1", "alice", 2, 0), backlog("alice-2", "alice", 2, 10)...),
+				backlog("bob-1", "bob", 2, 20)...),
+			want: []string{"alice-1-0", "bob-1-0", "alice-1-1", "bob-1-1", "alice-2-0", "alice-2-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nodeIDs(tt.policy.Order(tt.ready, tt.inFlight))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A large workflow that became ready first must not monopolize the scheduler.
+func TestFairShareSchedulingPreventsStarvation(t *testing.T) {
+	tests := []struct {
+		policy      Policy
+		wantStarved bool
+	}{
+		{policy: FIFOPolicy{}, wantStarved: true},
+		{policy: FairSharePolicy{}, wantStarved: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T", tt.policy), func(t *testing.T) {
+			fakeSM := &FakeStateManager{ready: append(backlog("big", "", 50, 0), backlog("small", "", 2, 100)...)}
+			sched := NewSimpleScheduler(fakeSM, blockingNodeClient{}, time.Hour)
+			sched.Limits = DispatchLimits{Global: 4}
+			sched.Policy = tt.policy
+
+			ctx, cancel := context.WithCancel(context.Background())
+			sched.scheduleOnce(ctx)
+
+			fakeSM.mu.Lock()
+			claimed := append([]string(nil), fakeSM.claimOrder...)
+			fakeSM.mu.Unlock()
+			smallClaimed := 0
+			for _, id := range claimed {
+				if id == "small-0" || id == "small-1" {
+					smallClaimed++
+				}
+			}
+			if starved := smallClaimed == 0; starved != tt.wantStarved {
+				t.Errorf("claimed %v: small workflow starved = %v, want %v", claimed, starved, tt.wantStarved)
+			}
+			if !tt.wantStarved && smallClaimed != 2 {
+				t.Errorf("expected both small nodes in the first batch, claimed %v", claimed)
+			}
+			cancel()
+			sched.Pool().Wait()
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for name, want := range map[string]Policy{
+		"":                 FIFOPolicy{},
+		"fifo":             FIFOPolicy{},
+		"priority":         PriorityPolicy{},
+		"fair-share":       FairSharePolicy{By: ShareByWorkflow},
+		"fair-share-owner": FairSharePolicy{By: ShareByOwner},
+	} {
+		got, err := ParsePolicy(name)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("ParsePolicy(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParsePolicy("random"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
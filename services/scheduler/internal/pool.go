@@ -0,0 +1,346 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// DispatchLimits caps how many nodes may execute concurrently. A zero value
+// for any field means that dimension is unlimited.
+type DispatchLimits struct {
+	// Global caps in-flight dispatches across all workflows.
+	Global int
+	// PerWorkflow caps in-flight dispatches of any single workflow.
+	PerWorkflow int
+	// PerModel caps in-flight dispatches per Agent.model_type, so that a wide
+	// fan-out cannot exhaust one provider's rate limit.
+	PerModel int
+}
+
+// PoolMetrics is a point-in-time snapshot of DispatchPool activity.
+type PoolMetrics struct {
+	Running            int
+	QueueDepth         int
+	MaxQueueDepth      int
+	Admitted           int64
+	TotalAdmissionWait time.Duration
+	MaxAdmissionWait   time.Duration
+}
+
+// AverageAdmissionWait returns the mean time admitted nodes spent queued.
+func (m PoolMetrics) AverageAdmissionWait() time.Duration {
+	if m.Admitted == 0 {
+		return 0
+	}
+	return m.TotalAdmissionWait / time.Duration(m.Admitted)
+}
+
+// DispatchFunc executes a claimed node.
+type DispatchFunc func(ctx context.Context, ready *persistence.ReadyNode)
+
+type queuedNode struct {
+	ctx      context.Context
+	ready    *persistence.ReadyNode
+	run      DispatchFunc
+	enqueued time.Time
+}
+
+// DispatchPool runs claimed nodes within DispatchLimits. Nodes whose workflow
+// or model is at capacity wait in a queue; queued nodes are admitted in FIFO
+// order, but a blocked node never holds up nodes behind it that fit.
+type DispatchPool struct {
+	limits DispatchLimits
+
+	mu         sync.Mutex
+	running    int
+	active     map[*persistence.ReadyNode]context.CancelFunc
+	byWorkflow map[string]int
+	byModel    map[string]int
+	queue      []*queuedNode
+	metrics    PoolMetrics
+	wg         sync.WaitGroup
+}
+
+// NewDispatchPool creates a DispatchPool enforcing limits.
+func NewDispatchPool(limits DispatchLimits) *DispatchPool {
+	return &DispatchPool{
+		limits:     limits,
+		active:     make(map[*persistence.ReadyNode]context.CancelFunc),
+		byWorkflow: make(map[string]int),
+		byModel:    make(map[string]int),
+	}
+}
+
+// FreeSlots returns how many more nodes the pool can accept before reaching
+// its global limit, counting queued nodes, or -1 if there is no global limit.
+func (p *DispatchPool) FreeSlots() int {
+	if p.limits.Global <= 0 {
+		return -1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	free := p.limits.Global - p.running - len(p.queue)
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+// Fit returns, in order, the candidates the pool can accept without any of
+// them waiting on a full workflow or model, at most max of them unless max is
+// negative. Running and queued nodes both count against the limits. Skipped
+// candidates are left for other replicas, or for a later pass once this
+// pool's dispatches finish.
+func (p *DispatchPool) Fit(candidates []*persistence.ReadyNode, max int) []*persistence.ReadyNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	byWorkflow := make(map[string]int, len(p.byWorkflow))
+	for id, n := range p.byWorkflow {
+		byWorkflow[id] = n
+	}
+	byModel := make(map[string]int, len(p.byModel))
+	for model, n := range p.byModel {
+		byModel[model] = n
+	}
+	for _, q := range p.queue {
+		byWorkflow[q.ready.WorkflowID]++
+		if model := modelOf(q.ready); model != "" {
+			byModel[model]++
+		}
+	}
+
+	var fit []*persistence.ReadyNode
+	for _, c := range candidates {
+		if max >= 0 && len(fit) >= max {
+			break
+		}
+		if p.limits.PerWorkflow > 0 && byWorkflow[c.WorkflowID] >= p.limits.PerWorkflow {
+			continue
+		}
+		model := modelOf(c)
+		if p.limits.PerModel > 0 && model != "" && byModel[model] >= p.limits.PerModel {
+			continue
+		}
+		fit = append(fit, c)
+		byWorkflow[c.WorkflowID]++
+		if model != "" {
+			byModel[model]++
+		}
+	}
+	return fit
+}
+
+// Submit runs ready with run as soon as the limits allow.
+func (p *DispatchPool) Submit(ctx context.Context, ready *persistence.ReadyNode, run DispatchFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, &queuedNode{ctx: ctx, ready: ready, run: run, enqueued: time.Now()})
+	p.admitLocked()
+}
+
+// Queued returns the nodes currently waiting for admission.
+func (p *DispatchPool) Queued() []*persistence.ReadyNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	queued := make([]*persistence.ReadyNode, len(p.queue))
+	for i, q := range p.queue {
+		queued[i] = q.ready
+	}
+	return queued
+}
+
+// InFlight returns the nodes that are running or waiting for admission.
+func (p *DispatchPool) InFlight() []*persistence.ReadyNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	inFlight := make([]*persistence.ReadyNode, 0, len(p.active)+len(p.queue))
+	for rn := range p.active {
+		inFlight = append(inFlight, rn)
+	}
+	for _, q := range p.queue {
+		inFlight = append(inFlight, q.ready)
+	}
+	return inFlight
+}
+
+// DrainQueue drops every node waiting for admission and returns them.
+func (p *DispatchPool) DrainQueue() []*persistence.ReadyNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	drained := make([]*persistence.ReadyNode, len(p.queue))
+	for i, q := range p.queue {
+		drained[i] = q.ready
+	}
+	p.queue = nil
+	p.metrics.QueueDepth = 0
+	return drained
+}
+
+// Remove drops a queued node, e.g. after its lease was lost. It reports
+// whether the node was queued.
+func (p *DispatchPool) Remove(workflowID, nodeID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.removeLocked(workflowID, nodeID)
+}
+
+func (p *DispatchPool) removeLocked(workflowID, nodeID string) bool {
+	for i, q := range p.queue {
+		if q.ready.WorkflowID == workflowID && q.ready.Node.GetNodeId() == nodeID {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			p.metrics.QueueDepth = len(p.queue)
+			return true
+		}
+	}
+	return false
+}
+
+// Cancel drops a queued node or cancels the context of a running one. It
+// reports whether the node was in the pool.
+func (p *DispatchPool) Cancel(workflowID, nodeID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for rn, cancel := range p.active {
+		if rn.WorkflowID == workflowID && rn.Node.GetNodeId() == nodeID {
+			cancel()
+			return true
+		}
+	}
+	return p.removeLocked(workflowID, nodeID)
+}
+
+// CancelWorkflow drops the queued nodes of a workflow and cancels the context
+// of its running ones. It returns how many nodes were affected.
+func (p *DispatchPool) CancelWorkflow(workflowID string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for rn, cancel := range p.active {
+		if rn.WorkflowID == workflowID {
+			cancel()
+			n++
+		}
+	}
+	return n + len(p.dequeueWorkflowLocked(workflowID))
+}
+
+// DequeueWorkflow drops the queued nodes of a workflow, leaving its running
+// ones alone, and returns them.
+func (p *DispatchPool) DequeueWorkflow(workflowID string) []*persistence.ReadyNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dequeueWorkflowLocked(workflowID)
+}
+
+func (p *DispatchPool) dequeueWorkflowLocked(workflowID string) []*persistence.ReadyNode {
+	var dequeued []*persistence.ReadyNode
+	remaining := p.queue[:0]
+	for _, q := range p.queue {
+		if q.ready.WorkflowID == workflowID {
+			dequeued = append(dequeued, q.ready)
+		} else {
+			remaining = append(remaining, q)
+		}
+	}
+	for i := len(remaining); i < len(p.queue); i++ {
+		p.queue[i] = nil
+	}
+	p.queue = remaining
+	p.metrics.QueueDepth = len(p.queue)
+	return dequeued
+}
+
+// Metrics returns a snapshot of the pool's activity.
+func (p *DispatchPool) Metrics() PoolMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := p.metrics
+	m.Running = p.running
+	m.QueueDepth = len(p.queue)
+	return m
+}
+
+// Wait blocks until every admitted node has finished running.
+func (p *DispatchPool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *DispatchPool) admitLocked() {
+	remaining := p.queue[:0]
+	for _, q := range p.queue {
+		if p.canAdmitLocked(q.ready) {
+			p.startLocked(q)
+		} else {
+			remaining = append(remaining, q)
+		}
+	}
+	for i := len(remaining); i < len(p.queue); i++ {
+		p.queue[i] = nil
+	}
+	p.queue = remaining
+	p.metrics.QueueDepth = len(p.queue)
+	if len(p.queue) > p.metrics.MaxQueueDepth {
+		p.metrics.MaxQueueDepth = len(p.queue)
+	}
+}
+
+func (p *DispatchPool) canAdmitLocked(ready *persistence.ReadyNode) bool {
+	if p.limits.Global > 0 && p.running >= p.limits.Global {
+		return false
+	}
+	if p.limits.PerWorkflow > 0 && p.byWorkflow[ready.WorkflowID] >= p.limits.PerWorkflow {
+		return false
+	}
+	if model := modelOf(ready); p.limits.PerModel > 0 && model != "" && p.byModel[model] >= p.limits.PerModel {
+		return false
+	}
+	return true
+}
+
+func (p *DispatchPool) startLocked(q *queuedNode) {
+	ctx, cancel := context.WithCancel(q.ctx)
+	p.running++
+	p.active[q.ready] = cancel
+	p.byWorkflow[q.ready.WorkflowID]++
+	if model := modelOf(q.ready); model != "" {
+		p.byModel[model]++
+	}
+
+	wait := time.Since(q.enqueued)
+	p.metrics.Admitted++
+	p.metrics.TotalAdmissionWait += wait
+	if wait > p.metrics.MaxAdmissionWait {
+		p.metrics.MaxAdmissionWait = wait
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.finish(q.ready)
+		defer cancel()
+		q.run(ctx, q.ready)
+	}()
+}
+
+func (p *DispatchPool) finish(ready *persistence.ReadyNode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running--
+	delete(p.active, ready)
+	if p.byWorkflow[ready.WorkflowID]--; p.byWorkflow[ready.WorkflowID] <= 0 {
+		delete(p.byWorkflow, ready.WorkflowID)
+	}
+	if model := modelOf(ready); model != "" {
+		if p.byModel[model]--; p.byModel[model] <= 0 {
+			delete(p.byModel, model)
+		}
+	}
+	p.admitLocked()
+}
+
+func modelOf(ready *persistence.ReadyNode) string {
+	return ready.Node.GetAgent().GetModelType()
+}
@@ -0,0 +1,207 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// gatedRun is a DispatchFunc that records which nodes started and blocks each
+// one until release is closed.
+type gatedRun struct {
+	mu      sync.Mutex
+	started []string
+	release chan struct{}
+}
+
+func (g *gatedRun) run(ctx context.Context, ready *persistence.ReadyNode) {
+	g.mu.Lock()
+	g.started = append(g.started, ready.Node.NodeId)
+	g.mu.Unlock()
+	<-g.release
+}
+
+func (g *gatedRun) startedIDs() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.started...)
+}
+
+// blockingNodeClient executes nodes until their context is done.
+type blockingNodeClient struct{}
+
+func (blockingNodeClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func readyNode(workflowID, nodeID, model string) *persistence.ReadyNode {
+	node := &pb.Node{NodeId: nodeID, Status: pb.Status_RUNNING}
+	if model != "" {
+		node.Agent = &pb.Agent{ModelType: model}
+	}
+	return &persistence.ReadyNode{WorkflowID: workflowID, Node: node}
+}
+
+func waitForStarted(t *testing.T, g *gatedRun, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(g.startedIDs()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d nodes to start, got %v", n, g.startedIDs())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDispatchPoolEnforcesLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    DispatchLimits
+		nodes     []*persistence.ReadyNode
+		wantFirst []string
+	}{
+		{
+			name:      "global",
+			limits:    DispatchLimits{Global: 2},
+			nodes:     []*persistence.ReadyNode{readyNode("wf-1", "a", ""), readyNode("wf-2", "b", ""), readyNode("wf-3", "c", "")},
+			wantFirst: []string{"a", "b"},
+		},
+		{
+			name:      "per workflow",
+			limits:    DispatchLimits{PerWorkflow: 1},
+			nodes:     []*persistence.ReadyNode{readyNode("wf-1", "a", ""), readyNode("wf-1", "b", ""), readyNode("wf-2", "c", "")},
+			wantFirst: []string{"a", "c"},
+		},
+		{
+			name:      "per model",
+			limits:    DispatchLimits{PerModel: 1},
+			nodes:     []*persistence.ReadyNode{readyNode("wf-1", "a", "gpt"), readyNode("wf-2", "b", "gpt"), readyNode("wf-3", "c", "claude")},
+			wantFirst: []string{"a", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewDispatchPool(tt.limits)
+			g := &gatedRun{release: make(chan struct{})}
+			for _, n := range tt.nodes {
+				pool.Submit(context.Background(), n, g.run)
+			}
+
+			waitForStarted(t, g, len(tt.wantFirst))
+			time.Sleep(10 * time.Millisecond)
+			started := g.startedIDs()
+			if len(started) != len(tt.wantFirst) {
+				t.Fatalf("expected only %v to start, got %v", tt.wantFirst, started)
+			}
+			sort.Strings(started)
+			for i, id := range tt.wantFirst {
+				if started[i] != id {
+					t.Errorf("expected %v to start first, got %v", tt.wantFirst, started)
+					break
+				}
+			}
+			if m := pool.Metrics(); m.QueueDepth != 1 || m.Running != 2 {
+				t.Errorf("expected 2 running and 1 queued, got %+v", m)
+			}
+
+			// Finishing the admitted nodes admits the queued one.
+			close(g.release)
+			pool.Wait()
+			m := pool.Metrics()
+			if len(g.startedIDs()) != 3 || m.Admitted != 3 || m.QueueDepth != 0 || m.Running != 0 {
+				t.Errorf("expected all 3 nodes to run, got started=%v metrics=%+v", g.startedIDs(), m)
+			}
+			if m.MaxQueueDepth != 1 || m.MaxAdmissionWait < 10*time.Millisecond {
+				t.Errorf("expected the queued node's wait to be recorded, got %+v", m)
+			}
+		})
+	}
+}
+
+func TestDispatchPoolRemove(t *testing.T) {
+	pool := NewDispatchPool(DispatchLimits{Global: 1})
+	g := &gatedRun{release: make(chan struct{})}
+	pool.Submit(context.Background(), readyNode("wf-1", "a", ""), g.run)
+	pool.Submit(context.Background(), readyNode("wf-1", "b", ""), g.run)
+
+	if free := pool.FreeSlots(); free != 0 {
+		t.Errorf("FreeSlots() = %d, want 0", free)
+	}
+	if !pool.Remove("wf-1", "b") {
+		t.Fatal("expected queued node b to be removed")
+	}
+	if pool.Remove("wf-1", "a") {
+		t.Error("expected running node a not to be removable")
+	}
+	close(g.release)
+	pool.Wait()
+	if started := g.startedIDs(); len(started) != 1 || started[0] != "a" {
+		t.Errorf("expected only a to run, got %v", started)
+	}
+	if free := pool.FreeSlots(); free != 1 {
+		t.Errorf("FreeSlots() = %d, want 1", free)
+	}
+}
+
+func TestSchedulerClaimsOnlyFreeSlots(t *testing.T) {
+	var nodes []*pb.Node
+	for _, id := range []string{"n1", "n2", "n3", "n4", "n5"} {
+		nodes = append(nodes, &pb.Node{NodeId: id, Status: pb.Status_READY})
+	}
+	fakeSM := &FakeStateManager{readyNodes: nodes}
+	sched := NewSimpleScheduler(fakeSM, blockingNodeClient{}, time.Hour)
+	sched.Limits = DispatchLimits{Global: 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sched.scheduleOnce(ctx)
+	sched.scheduleOnce(ctx)
+
+	fakeSM.mu.Lock()
+	claimed := len(fakeSM.claimedBy)
+	limits := fmt.Sprint(fakeSM.findLimits)
+	fakeSM.mu.Unlock()
+	if claimed != 2 {
+		t.Errorf("expected the scheduler to claim only its 2 free slots, claimed %d", claimed)
+	}
+	// The full pool skips looking for ready nodes altogether.
+	if want := fmt.Sprint([]int{candidateFactor * 2}); limits != want {
+		t.Errorf("FindReadyNodes called with limits %s, want %s", limits, want)
+	}
+	cancel()
+	sched.Pool().Wait()
+}
+
+func TestSchedulerSkipsCandidatesAtTheirLimits(t *testing.T) {
+	fakeSM := &FakeStateManager{ready: []*persistence.ReadyNode{
+		readyNode("wf-1", "a1", "gpt"),
+		readyNode("wf-1", "a2", "claude"),
+		readyNode("wf-2", "b1", "gpt"),
+		readyNode("wf-2", "b2", "claude"),
+		readyNode("wf-3", "c1", "claude"),
+	}}
+	sched := NewSimpleScheduler(fakeSM, blockingNodeClient{}, time.Hour)
+	sched.Limits = DispatchLimits{PerWorkflow: 1, PerModel: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sched.scheduleOnce(ctx)
+	sched.scheduleOnce(ctx)
+
+	fakeSM.mu.Lock()
+	claimed := append([]string(nil), fakeSM.claimOrder...)
+	fakeSM.mu.Unlock()
+	if len(claimed) != 2 || claimed[0] != "a1" || claimed[1] != "b2" {
+		t.Errorf("expected only a1 and b2 to fit the per-workflow and per-model limits, claimed %v", claimed)
+	}
+	if queued := sched.Pool().Queued(); len(queued) != 0 {
+		t.Errorf("expected no claimed node to wait for admission, got %d queued", len(queued))
+	}
+	cancel()
+	sched.Pool().Wait()
+}
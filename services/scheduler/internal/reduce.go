@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// collectResults sets the subtasks of a REDUCE node's assigned task to the
+// assigned tasks, with their results, of its parents other than MAP nodes,
+// in parent order. For a REDUCE node downstream of a MAP node these are the
+// MAP node's items. If the parents cannot be loaded, the claim on node is
+// released so that it is retried, and collectResults reports false.
+func (s *SimpleScheduler) collectResults(ctx context.Context, workflowID string, node *pb.Node) bool {
+	parents, err := s.StateManager.GetNodes(ctx, workflowID, node.ParentIds)
+	if err != nil {
+		log.Printf("Failed to load parents of reduce node %s: %v", node.NodeId, err)
+		s.releaseClaims(ctx, []*persistence.ReadyNode{{WorkflowID: workflowID, Node: node}})
+		return false
+	}
+	byID := make(map[string]*pb.Node, len(parents))
+	for _, p := range parents {
+		byID[p.NodeId] = p
+	}
+
+	if node.AssignedTask == nil {
+		node.AssignedTask = &pb.Task{}
+	}
+	node.AssignedTask.Subtasks = nil
+	for _, id := range node.ParentIds {
+		p, ok := byID[id]
+		if !ok || p.GetType() == pb.NodeType_MAP || p.AssignedTask == nil {
+			continue
+		}
+		node.AssignedTask.Subtasks = append(node.AssignedTask.Subtasks, proto.Clone(p.AssignedTask).(*pb.Task))
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func TestSchedulerCollectsResultsIntoReduceNodes(t *testing.T) {
+	result := func(summary string) *pb.Task {
+		return &pb.Task{Id: summary, Results: []*pb.Task_Result{{Status: pb.Status_PASS, Summary: summary}}}
+	}
+	parents := map[string]*pb.Node{
+		"map":    {NodeId: "map", Type: pb.NodeType_MAP, AssignedTask: result("plan")},
+		"item-1": {NodeId: "item-1", Status: pb.Status_PASS, AssignedTask: result("first")},
+		"item-2": {NodeId: "item-2", Status: pb.Status_PASS, AssignedTask: result("second")},
+	}
+	parentIDs := []string{"map", "item-2", "item-1"}
+
+	t.Run("without an agent", func(t *testing.T) {
+		fakeSM := &FakeStateManager{
+			nodes:      parents,
+			readyNodes: []*pb.Node{{NodeId: "join", Type: pb.NodeType_REDUCE, ParentIds: parentIDs}},
+		}
+		fakeClient := &FakeNodeServiceClient{}
+		sched := NewSimpleScheduler(fakeSM, fakeClient, 0)
+		sched.scheduleOnce(context.Background())
+		sched.Pool().Wait()
+
+		if fakeClient.Called {
+			t.Errorf("expected a reduce node without an agent not to be sent to the NodeService")
+		}
+		fakeSM.mu.Lock()
+		defer fakeSM.mu.Unlock()
+		if len(fakeSM.updatedNodes) != 1 || fakeSM.updatedNodes[0].Status != pb.Status_PASS {
+			t.Fatalf("expected the reduce node to pass, got %v", fakeSM.updatedNodes)
+		}
+		subtasks := fakeSM.updatedNodes[0].AssignedTask.GetSubtasks()
+		if len(subtasks) != 2 || subtasks[0].Id != "second" || subtasks[1].Id != "first" {
+			t.Errorf("expected the items' tasks in parent order, got %v", subtasks)
+		}
+	})
+
+	t.Run("with an agent", func(t *testing.T) {
+		fakeSM := &FakeStateManager{
+			nodes: parents,
+			readyNodes: []*pb.Node{{
+				NodeId: "join", Type: pb.NodeType_REDUCE, ParentIds: parentIDs,
+				Agent: &pb.Agent{Role: "Summarizer"}, AssignedTask: &pb.Task{Goal: "summarize"},
+			}},
+		}
+		fakeClient := &FakeNodeServiceClient{Response: &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: "join", Status: pb.Status_PASS}}}
+		sched := NewSimpleScheduler(fakeSM, fakeClient, 0)
+		sched.scheduleOnce(context.Background())
+		sched.Pool().Wait()
+
+		fakeClient.mu.Lock()
+		defer fakeClient.mu.Unlock()
+		if len(fakeClient.Requests) != 1 {
+			t.Fatalf("expected the reduce node to be sent to the NodeService, got %d requests", len(fakeClient.Requests))
+		}
+		task := fakeClient.Requests[0].Node.AssignedTask
+		if task.Goal != "summarize" || len(task.Subtasks) != 2 {
+			t.Errorf("expected the agent to receive the collected results, got %v", task)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+const (
+	// DefaultRetryDelay is the base backoff used when RetryOptions.retry_delay
+	// is unset.
+	DefaultRetryDelay = 1 * time.Second
+
+	// MaxRetryBackoff caps the exponential backoff between attempts.
+	MaxRetryBackoff = 5 * time.Minute
+)
+
+// isRetryable reports whether an attempt that ended with status may be retried.
+// Only infrastructure failures are retried; task failures are final.
+func isRetryable(s pb.Status) bool {
+	switch s {
+	case pb.Status_INFRA_ERROR, pb.Status_CRASH, pb.Status_TIMEOUT:
+		return true
+	}
+	return false
+}
+
+// attemptTimeout returns the per-attempt deadline from the node's
+// ExecutionOptions, or zero if it has none.
+func attemptTimeout(node *pb.Node) time.Duration {
+	return node.GetExecutionOptions().GetTimeout().AsDuration()
+}
+
+// maxAttempts returns how many attempts the node allows in total. Nodes
+// without RetryOptions get a single attempt.
+func maxAttempts(node *pb.Node) int {
+	if n := int(node.GetExecutionOptions().GetRetryOptions().GetMaxAttempts()); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// retryBackoff returns how long to wait before the attempt following attempt
+// number n: retry_delay doubled for every previous retry, capped at
+// MaxRetryBackoff, with "equal jitter" so that replicas retrying the same
+// backend spread out.
+func retryBackoff(node *pb.Node, n int) time.Duration {
+	base := DefaultRetryDelay
+	if opts := node.GetExecutionOptions().GetRetryOptions(); opts.GetRetryDelay() != nil {
+		base = opts.GetRetryDelay().AsDuration()
+	}
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < n && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryBackoff {
+		delay = MaxRetryBackoff
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// executeAttempt makes one ExecuteNode call for req, bounded by the node's
+// timeout, and returns the resulting node together with a record of the
+// attempt. Failures are folded into the returned node's status: TIMEOUT when
+// the deadline passed, INFRA_ERROR for any other transport error or failure
+// to load the node's context.
+func (s *SimpleScheduler) executeAttempt(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.Node, *pb.Attempt) {
+	node := req.Node
+	attempt := &pb.Attempt{
+		Number:  int32(len(node.Attempts) + 1),
+		Started: timestamppb.Now(),
+	}
+
+	attemptCtx := ctx
+	if timeout := attemptTimeout(node); timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Reload context on every attempt so that a retry sees its relatives'
+	// latest results.
+	var resp *pb.ExecuteNodeResponse
+	err := s.attachContext(attemptCtx, req)
+	if err == nil {
+		resp, err = s.NodeServiceClient.ExecuteNode(attemptCtx, req)
+	}
+	attempt.Finished = timestamppb.Now()
+
+	var result *pb.Node
+	switch {
+	case err == nil && resp.GetNode() != nil:
+		result = resp.Node
+		attempt.Status = result.Status
+	case err == nil:
+		attempt.Status = pb.Status_INFRA_ERROR
+		attempt.Error = "node service returned no node"
+	case ctx.Err() == nil && (errors.Is(attemptCtx.Err(), context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded):
+		attempt.Status = pb.Status_TIMEOUT
+		attempt.Error = fmt.Sprintf("attempt exceeded timeout of %s: %v", attemptTimeout(node), err)
+	default:
+		attempt.Status = pb.Status_INFRA_ERROR
+		attempt.Error = err.Error()
+	}
+
+	if result == nil {
+		result = proto.Clone(node).(*pb.Node)
+		result.Status = attempt.Status
+	}
+	return result, attempt
+}
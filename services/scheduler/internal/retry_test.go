@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// scriptedNodeClient returns the scripted outcomes in order, repeating the
+// last one once the script runs out. A nil response with a nil error blocks
+// until the request context is done.
+type scriptedNodeClient struct {
+	mu       sync.Mutex
+	statuses []pb.Status
+	errs     []error
+	calls    int
+}
+
+func (c *scriptedNodeClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	c.mu.Lock()
+	i := c.calls
+	if i >= len(c.statuses) {
+		i = len(c.statuses) - 1
+	}
+	c.calls++
+	st, err := c.statuses[i], c.errs[i]
+	c.mu.Unlock()
+
+	if st == pb.Status_UNKNOWN && err == nil {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: req.NodeId, Status: st}}, nil
+}
+
+func retryingNode(maxAttempts int32) *pb.Node {
+	return &pb.Node{
+		NodeId: "retry-node",
+		Status: pb.Status_RUNNING,
+		ExecutionOptions: &pb.ExecutionOptions{
+			RetryOptions: &pb.ExecutionOptions_RetryOptions{
+				MaxAttempts: maxAttempts,
+				RetryDelay:  durationpb.New(time.Millisecond),
+			},
+		},
+	}
+}
+
+func lastUpdate(t *testing.T, sm *FakeStateManager) *pb.Node {
+	t.Helper()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if len(sm.updatedNodes) == 0 {
+		t.Fatal("expected the node to be persisted")
+	}
+	return sm.updatedNodes[len(sm.updatedNodes)-1]
+}
+
+func TestDispatchTimesOutPerExecutionOptions(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	client := &scriptedNodeClient{statuses: []pb.Status{pb.Status_UNKNOWN}, errs: []error{nil}}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+
+	node := &pb.Node{
+		NodeId:           "slow-node",
+		Status:           pb.Status_RUNNING,
+		ExecutionOptions: &pb.ExecutionOptions{Timeout: durationpb.New(10 * time.Millisecond)},
+	}
+	start := time.Now()
+	sched.dispatchNode(context.Background(), "wf-1", node)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("dispatch took %s, expected the 10ms timeout to cut it short", elapsed)
+	}
+
+	got := lastUpdate(t, fakeSM)
+	if got.Status != pb.Status_TIMEOUT {
+		t.Errorf("expected TIMEOUT, got %v", got.Status)
+	}
+	if len(got.Attempts) != 1 || got.Attempts[0].Status != pb.Status_TIMEOUT {
+		t.Errorf("expected one TIMEOUT attempt, got %v", got.Attempts)
+	}
+}
+
+func TestDispatchRetriesInfraErrorsUntilSuccess(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	client := &scriptedNodeClient{
+		statuses: []pb.Status{pb.Status_INFRA_ERROR, pb.Status_CRASH, pb.Status_PASS},
+		errs:     []error{errors.New("unavailable"), nil, nil},
+	}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+
+	sched.dispatchNode(context.Background(), "wf-1", retryingNode(5))
+
+	got := lastUpdate(t, fakeSM)
+	if got.Status != pb.Status_PASS {
+		t.Errorf("expected PASS after retries, got %v", got.Status)
+	}
+	want := []pb.Status{pb.Status_INFRA_ERROR, pb.Status_CRASH, pb.Status_PASS}
+	if len(got.Attempts) != len(want) {
+		t.Fatalf("expected %d attempts, got %v", len(want), got.Attempts)
+	}
+	for i, a := range got.Attempts {
+		if a.Number != int32(i+1) || a.Status != want[i] {
+			t.Errorf("attempt %d = #%d %v, want #%d %v", i, a.Number, a.Status, i+1, want[i])
+		}
+	}
+	if got.Attempts[0].Error == "" {
+		t.Error("expected the transport error to be recorded on the first attempt")
+	}
+
+	// Each failed attempt is persisted before retrying.
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if len(fakeSM.updatedNodes) != 3 {
+		t.Errorf("expected 2 progress updates and 1 final update, got %d", len(fakeSM.updatedNodes))
+	}
+}
+
+func TestDispatchStopsAtMaxAttempts(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	client := &scriptedNodeClient{statuses: []pb.Status{pb.Status_INFRA_ERROR}, errs: []error{errors.New("down")}}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+
+	sched.dispatchNode(context.Background(), "wf-1", retryingNode(2))
+
+	got := lastUpdate(t, fakeSM)
+	if got.Status != pb.Status_INFRA_ERROR || len(got.Attempts) != 2 {
+		t.Errorf("expected INFRA_ERROR after 2 attempts, got %v after %d", got.Status, len(got.Attempts))
+	}
+	if client.calls != 2 {
+		t.Errorf("expected 2 ExecuteNode calls, got %d", client.calls)
+	}
+}
+
+func TestDispatchDoesNotRetryTaskFailures(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	client := &scriptedNodeClient{statuses: []pb.Status{pb.Status_TASK_ERROR}, errs: []error{nil}}
+	sched := NewSimpleScheduler(fakeSM, client, time.Hour)
+
+	sched.dispatchNode(context.Background(), "wf-1", retryingNode(5))
+
+	if got := lastUpdate(t, fakeSM); got.Status != pb.Status_TASK_ERROR || len(got.Attempts) != 1 {
+		t.Errorf("expected a single TASK_ERROR attempt, got %v after %d", got.Status, len(got.Attempts))
+	}
+}
+
+func TestRetryBackoffIsExponentialWithJitter(t *testing.T) {
+	node := retryingNode(10)
+	node.ExecutionOptions.RetryOptions.RetryDelay = durationpb.New(100 * time.Millisecond)
+
+	for n, full := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		30: MaxRetryBackoff,
+	} {
+		for i := 0; i < 20; i++ {
+			if got := retryBackoff(node, n); got < full/2 || got > full {
+				t.Errorf("retryBackoff(attempt %d) = %s, want within [%s, %s]", n, got, full/2, full)
+			}
+		}
+	}
+}
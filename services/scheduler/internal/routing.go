@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// DefaultCapabilityRefreshInterval is how often NodeServicePool.Run polls
+// backends for their capabilities and health.
+const DefaultCapabilityRefreshInterval = 30 * time.Second
+
+// ErrNoCapableBackend is returned when no healthy backend can execute a node.
+var ErrNoCapableBackend = errors.New("no healthy NodeService backend can execute node")
+
+// NodeServiceBackend is a NodeService that can describe what it executes.
+type NodeServiceBackend interface {
+	NodeServiceClient
+	GetCapabilities(ctx context.Context, req *pb.GetCapabilitiesRequest) (*pb.GetCapabilitiesResponse, error)
+}
+
+type backend struct {
+	target       string
+	client       NodeServiceBackend
+	capabilities *pb.NodeCapabilities
+	healthy      bool
+	inFlight     int
+}
+
+// NodeServicePool routes each node to a healthy backend whose capabilities
+// match the node's agent and tool. Among capable backends it picks the one
+// with the fewest in-flight requests, and fails over to the next when a
+// backend is unreachable. It implements NodeServiceClient.
+type NodeServicePool struct {
+	mu       sync.Mutex
+	backends []*backend
+	next     int
+}
+
+// NewNodeServicePool creates an empty pool.
+func NewNodeServicePool() *NodeServicePool {
+	return &NodeServicePool{}
+}
+
+// Add registers a backend. It receives no traffic until a Refresh has
+// fetched its capabilities.
+func (p *NodeServicePool) Add(target string, client NodeServiceBackend) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.backends = append(p.backends, &backend{target: target, client: client})
+}
+
+// Refresh fetches the capabilities of every backend, marking those that fail
+// to answer unhealthy until the next successful refresh.
+func (p *NodeServicePool) Refresh(ctx context.Context) {
+	p.mu.Lock()
+	backends := append([]*backend(nil), p.backends...)
+	p.mu.Unlock()
+
+	for _, b := range backends {
+		resp, err := b.client.GetCapabilities(ctx, &pb.GetCapabilitiesRequest{})
+		p.mu.Lock()
+		if err != nil {
+			if b.healthy {
+				log.Printf("NodeService backend %s is unhealthy: %v", b.target, err)
+			}
+			b.healthy = false
+		} else {
+			if !b.healthy {
+				log.Printf("NodeService backend %s is healthy", b.target)
+			}
+			b.healthy = true
+			b.capabilities = resp.GetCapabilities()
+		}
+		p.mu.Unlock()
+	}
+}
+
+// Run refreshes the pool every interval until ctx is done.
+func (p *NodeServicePool) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCapabilityRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Refresh(ctx)
+		}
+	}
+}
+
+// ExecuteNode sends req to a capable backend, failing over to the others if
+// it is unavailable. If no backend qualifies, the pool is refreshed once
+// before giving up with ErrNoCapableBackend.
+func (p *NodeServicePool) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	tried := make(map[*backend]bool)
+	refreshed := false
+	var lastErr error
+	for {
+		b := p.acquire(req.GetNode(), tried)
+		if b == nil && !refreshed {
+			// Backends may have come up or changed since the last refresh.
+			refreshed = true
+			p.Refresh(ctx)
+			b = p.acquire(req.GetNode(), tried)
+		}
+		if b == nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, fmt.Errorf("%w %s", ErrNoCapableBackend, req.GetNodeId())
+		}
+		tried[b] = true
+
+		resp, err := b.client.ExecuteNode(ctx, req)
+		p.release(b, err)
+		if err == nil || status.Code(err) != codes.Unavailable || ctx.Err() != nil {
+			return resp, err
+		}
+		log.Printf("NodeService backend %s unavailable for node %s, failing over: %v", b.target, req.GetNodeId(), err)
+		lastErr = err
+	}
+}
+
+// acquire picks the least-loaded healthy backend capable of running node,
+// rotating the starting point so that ties are spread evenly.
+func (p *NodeServicePool) acquire(node *pb.Node, exclude map[*backend]bool) *backend {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var best *backend
+	for i := range p.backends {
+		b := p.backends[(p.next+i)%len(p.backends)]
+		if !b.healthy || exclude[b] || !canExecute(b.capabilities, node) {
+			continue
+		}
+		if best == nil || b.inFlight < best.inFlight {
+			best = b
+		}
+	}
+	if best != nil {
+		best.inFlight++
+		p.next = (p.next + 1) % len(p.backends)
+	}
+	return best
+}
+
+func (p *NodeServicePool) release(b *backend, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b.inFlight--
+	if status.Code(err) == codes.Unavailable {
+		// Stop routing here until a refresh shows the backend is back.
+		b.healthy = false
+	}
+}
+
+// canExecute reports whether a backend with caps can run node. Fields the
+// node leaves empty match any backend.
+func canExecute(caps *pb.NodeCapabilities, node *pb.Node) bool {
+	agent := node.GetAgent()
+	if !accepts(caps.GetAgentIds(), agent.GetAgentId()) ||
+		!accepts(caps.GetRoles(), agent.GetRole()) ||
+		!accepts(caps.GetModelTypes(), agent.GetModelType()) {
+		return false
+	}
+	if tool := requiredTool(node); tool != "" {
+		return contains(caps.GetTools(), tool)
+	}
+	return true
+}
+
+func accepts(allowed []string, value string) bool {
+	return len(allowed) == 0 || value == "" || contains(allowed, value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredTool returns the MCP tool named by a "Call: tool" task goal, the
+// convention the NodeService uses to invoke tools directly.
+func requiredTool(node *pb.Node) string {
+	goal, ok := strings.CutPrefix(node.GetAssignedTask().GetGoal(), "Call:")
+	if !ok {
+		return ""
+	}
+	goal = strings.TrimSpace(goal)
+	if i := strings.IndexAny(goal, " {"); i >= 0 {
+		goal = goal[:i]
+	}
+	return goal
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// fakeBackend is a NodeServiceBackend with fixed capabilities that counts the
+// nodes it executes.
+type fakeBackend struct {
+	mu       sync.Mutex
+	caps     *pb.NodeCapabilities
+	capsErr  error
+	execErr  error
+	executed int
+}
+
+func (b *fakeBackend) GetCapabilities(ctx context.Context, req *pb.GetCapabilitiesRequest) (*pb.GetCapabilitiesResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.capsErr != nil {
+		return nil, b.capsErr
+	}
+	return &pb.GetCapabilitiesResponse{Capabilities: b.caps}, nil
+}
+
+func (b *fakeBackend) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.execErr != nil {
+		return nil, b.execErr
+	}
+	b.executed++
+	return &pb.ExecuteNodeResponse{Node: &pb.Node{NodeId: req.NodeId, Status: pb.Status_PASS}}, nil
+}
+
+func (b *fakeBackend) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.executed
+}
+
+func agentNode(id string, agent *pb.Agent, goal string) *pb.ExecuteNodeRequest {
+	return &pb.ExecuteNodeRequest{NodeId: id, Node: &pb.Node{
+		NodeId:       id,
+		Agent:        agent,
+		AssignedTask: &pb.Task{Goal: goal},
+	}}
+}
+
+func TestNodeServicePoolRoutesByCapability(t *testing.T) {
+	gpt := &fakeBackend{caps: &pb.NodeCapabilities{ModelTypes: []string{"GPT-4"}}}
+	claude := &fakeBackend{caps: &pb.NodeCapabilities{ModelTypes: []string{"Claude-3"}, Roles: []string{"Worker"}}}
+	tools := &fakeBackend{caps: &pb.NodeCapabilities{Roles: []string{"Worker"}, Tools: []string{"kb.summarize"}}}
+	pool := NewNodeServicePool()
+	pool.Add("gpt", gpt)
+	pool.Add("claude", claude)
+	pool.Add("tools", tools)
+	pool.Refresh(context.Background())
+
+	tests := []struct {
+		req  *pb.ExecuteNodeRequest
+		want *fakeBackend
+	}{
+		{agentNode("n1", &pb.Agent{ModelType: "GPT-4"}, "write"), gpt},
+		{agentNode("n2", &pb.Agent{ModelType: "Claude-3", Role: "Worker"}, "write"), claude},
+		{agentNode("n3", &pb.Agent{ModelType: "Claude-3"}, "Call: kb.summarize {}"), tools},
+	}
+	for _, tt := range tests {
+		before := tt.want.count()
+		if _, err := pool.ExecuteNode(context.Background(), tt.req); err != nil {
+			t.Fatalf("ExecuteNode(%s) failed: %v", tt.req.NodeId, err)
+		}
+		if tt.want.count() != before+1 {
+			t.Errorf("node %s was not routed to the capable backend", tt.req.NodeId)
+		}
+	}
+
+	_, err := pool.ExecuteNode(context.Background(), agentNode("n4", &pb.Agent{ModelType: "Claude-3", Role: "Planner"}, "plan"))
+	if !errors.Is(err, ErrNoCapableBackend) {
+		t.Errorf("expected ErrNoCapableBackend for an unsupported role, got %v", err)
+	}
+}
+
+func TestNodeServicePoolBalancesLoad(t *testing.T) {
+	a, b := &fakeBackend{caps: &pb.NodeCapabilities{}}, &fakeBackend{caps: &pb.NodeCapabilities{}}
+	pool := NewNodeServicePool()
+	pool.Add("a", a)
+	pool.Add("b", b)
+	pool.Refresh(context.Background())
+
+	for i := 0; i < 10; i++ {
+		if _, err := pool.ExecuteNode(context.Background(), agentNode("n", nil, "work")); err != nil {
+			t.Fatalf("ExecuteNode failed: %v", err)
+		}
+	}
+	if a.count() != 5 || b.count() != 5 {
+		t.Errorf("expected requests to be spread evenly, got a=%d b=%d", a.count(), b.count())
+	}
+}
+
+func TestNodeServicePoolFailsOver(t *testing.T) {
+	down := &fakeBackend{caps: &pb.NodeCapabilities{}, execErr: status.Error(codes.Unavailable, "connection refused")}
+	up := &fakeBackend{caps: &pb.NodeCapabilities{}}
+	pool := NewNodeServicePool()
+	pool.Add("down", down)
+	pool.Add("up", up)
+	pool.Refresh(context.Background())
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.ExecuteNode(context.Background(), agentNode("n", nil, "work")); err != nil {
+			t.Fatalf("ExecuteNode failed: %v", err)
+		}
+	}
+	if up.count() != 3 {
+		t.Errorf("expected every request to fail over to the healthy backend, got %d", up.count())
+	}
+
+	// Task errors are not the backend's fault and are not retried elsewhere.
+	up.execErr = status.Error(codes.Internal, "agent exploded")
+	down.execErr = nil
+	if _, err := pool.ExecuteNode(context.Background(), agentNode("n", nil, "work")); status.Code(err) != codes.Internal {
+		t.Errorf("expected the Internal error to be returned as-is, got %v", err)
+	}
+}
+
+func TestNodeServicePoolSkipsUnhealthyBackends(t *testing.T) {
+	sick := &fakeBackend{caps: &pb.NodeCapabilities{}, capsErr: errors.New("unreachable")}
+	pool := NewNodeServicePool()
+	pool.Add("sick", sick)
+	pool.Refresh(context.Background())
+
+	if _, err := pool.ExecuteNode(context.Background(), agentNode("n", nil, "work")); !errors.Is(err, ErrNoCapableBackend) {
+		t.Fatalf("expected ErrNoCapableBackend while the only backend is unhealthy, got %v", err)
+	}
+
+	// Recovery is picked up by the refresh ExecuteNode does before giving up.
+	sick.mu.Lock()
+	sick.capsErr = nil
+	sick.mu.Unlock()
+	if _, err := pool.ExecuteNode(context.Background(), agentNode("n", nil, "work")); err != nil {
+		t.Fatalf("expected the recovered backend to be used, got %v", err)
+	}
+}
@@ -2,17 +2,41 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
 )
 
+// DefaultClaimBatchSize is the maximum number of nodes claimed per scheduling
+// iteration when SimpleScheduler.ClaimBatchSize is zero.
+const DefaultClaimBatchSize = 100
+
+// candidateFactor is how many ready nodes are considered per node claimed, so
+// that the policy and the dispatch limits have some choice without every pass
+// loading every ready node.
+const candidateFactor = 4
+
 // StateManager abstracts persistence operations needed by the scheduler.
 type StateManager interface {
-	FindReadyNodes(ctx context.Context) ([]*pb.Node, error)
-	UpdateNode(ctx context.Context, workflowID string, node *pb.Node) error
-	ApplyNodeEdits(ctx context.Context, workflowID string, edits []*pb.NodeEdit) error
+	FindReadyNodes(ctx context.Context, limit int) ([]*persistence.ReadyNode, error)
+	ClaimNodes(ctx context.Context, schedulerID string, nodes []*persistence.ReadyNode) ([]*persistence.ReadyNode, error)
+	UpdateLeasedNode(ctx context.Context, workflowID, schedulerID string, node *pb.Node) error
+	GetNodes(ctx context.Context, workflowID string, nodeIDs []string) ([]*pb.Node, error)
+	ApplyAgentEdits(ctx context.Context, workflowID, nodeID string, edits []*pb.NodeEdit) ([]*pb.RejectedEdit, error)
+	RenewLease(ctx context.Context, workflowID, nodeID, schedulerID string) (time.Time, error)
+	RecoverExpiredLeases(ctx context.Context, status pb.Status, reason string, limit int) ([]*persistence.ReadyNode, error)
+	ReleaseClaims(ctx context.Context, schedulerID string, nodes []*persistence.ReadyNode) (int, error)
+	StartSubworkflow(ctx context.Context, workflowID, schedulerID string, node *pb.Node) (string, error)
+	PausedWorkflows(ctx context.Context, workflowIDs []string) ([]string, error)
 }
 
 // NodeServiceClient abstracts the NodeService gRPC client.
@@ -30,87 +54,311 @@ type SimpleScheduler struct {
 	StateManager      StateManager
 	NodeServiceClient NodeServiceClient
 	PollInterval      time.Duration
+
+	// ID identifies this scheduler as the lease owner of the nodes it claims.
+	ID string
+	// ClaimBatchSize caps how many nodes are claimed per iteration.
+	ClaimBatchSize int
+
+	// HeartbeatInterval is how often leases on in-flight nodes are renewed.
+	HeartbeatInterval time.Duration
+	// ReapInterval is how often expired leases are looked for.
+	ReapInterval time.Duration
+	// LeaseRecovery decides what happens to nodes whose lease expired.
+	LeaseRecovery LeaseRecoveryPolicy
+
+	// Limits caps concurrent dispatches; nodes over a cap wait in a queue.
+	Limits DispatchLimits
+
+	// DrainTimeout is how long Run waits, once its context is canceled, for
+	// in-flight dispatches to record their results. Defaults to
+	// DefaultDrainTimeout.
+	DrainTimeout time.Duration
+
+	// ContextBudget caps the serialized size, in bytes, of the upstream and
+	// downstream nodes sent with each node. Defaults to DefaultContextBudget.
+	ContextBudget int
+
+	// Policy orders ready nodes for claiming. Defaults to FIFOPolicy.
+	Policy Policy
+
+	// Events, if set, triggers a scheduling pass as soon as nodes may have
+	// become ready; PollInterval then only acts as a safety net.
+	Events NodeEventSource
+
+	poolOnce sync.Once
+	pool     *DispatchPool
 }
 
-// NewSimpleScheduler creates a new SimpleScheduler.
+// NewSimpleScheduler creates a new SimpleScheduler with a unique ID.
 func NewSimpleScheduler(sm StateManager, nc NodeServiceClient, pollInterval time.Duration) *SimpleScheduler {
 	return &SimpleScheduler{
 		StateManager:      sm,
 		NodeServiceClient: nc,
 		PollInterval:      pollInterval,
+		ID:                NewSchedulerID(),
+		ClaimBatchSize:    DefaultClaimBatchSize,
+		HeartbeatInterval: DefaultHeartbeatInterval,
+		ReapInterval:      DefaultReapInterval,
+		LeaseRecovery:     RequeueOnExpiry,
+	}
+}
+
+// NewSchedulerID returns an identifier that is unique across scheduler
+// replicas and restarts.
+func NewSchedulerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "scheduler"
 	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.New().String()[:8])
+}
+
+// Pool returns the pool that bounds this scheduler's dispatches.
+func (s *SimpleScheduler) Pool() *DispatchPool {
+	s.poolOnce.Do(func() {
+		s.pool = NewDispatchPool(s.Limits)
+	})
+	return s.pool
 }
 
-// Run starts the scheduling loop.
+// Run starts the scheduling loop, along with a reaper that recovers nodes
+// orphaned by schedulers that stopped heartbeating. The loop runs on every
+// node event and every PollInterval. Once ctx is canceled, Run stops claiming
+// nodes and drains the dispatch pool before returning.
 func (s *SimpleScheduler) Run(ctx context.Context) {
+	// Dispatches outlive ctx so that a shutdown can let them finish; drain
+	// cancels them once DrainTimeout passes.
+	dispatchCtx, cancelDispatches := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelDispatches()
+
 	ticker := time.NewTicker(s.PollInterval)
 	defer ticker.Stop()
 
+	reapInterval := s.ReapInterval
+	if reapInterval <= 0 {
+		reapInterval = DefaultReapInterval
+	}
+	reaper := time.NewTicker(reapInterval)
+	defer reaper.Stop()
+
+	// Nodes waiting in the dispatch queue are claimed but not yet running, so
+	// their leases are renewed here rather than by a per-dispatch heartbeat,
+	// and they are released if their workflow was paused.
+	heartbeatInterval := s.HeartbeatInterval
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = DefaultHeartbeatInterval
+	}
+	queueHeartbeat := time.NewTicker(heartbeatInterval)
+	defer queueHeartbeat.Stop()
+
+	// A nil channel never fires, so without a subscription only polling runs.
+	events := s.subscribeNodeEvents(ctx)
+
 	for {
 		select {
 		case <-ctx.Done():
+			s.drain(dispatchCtx, cancelDispatches)
 			log.Println("Scheduler stopped")
 			return
+		case workflowID, ok := <-events:
+			workflowIDs := map[string]bool{workflowID: ok}
+			if !ok || !drainNodeEvents(events, workflowIDs) {
+				events = nil
+				if ctx.Err() != nil {
+					continue
+				}
+				log.Printf("Node event subscription closed; polling every %s until resubscribed", s.PollInterval)
+			}
+			s.checkLeases(ctx, workflowIDs)
+			s.releasePaused(ctx, workflowIDs)
+			s.scheduleOnce(dispatchCtx)
 		case <-ticker.C:
-			s.scheduleOnce(ctx)
+			if events == nil {
+				events = s.subscribeNodeEvents(ctx)
+			}
+			s.scheduleOnce(dispatchCtx)
+		case <-reaper.C:
+			s.reapExpiredLeases(ctx)
+			s.logPoolMetrics()
+		case <-queueHeartbeat.C:
+			s.releasePaused(ctx, nil)
+			s.renewLeases(ctx, s.Pool().Queued())
 		}
 	}
 }
 
 // scheduleOnce performs one scheduling iteration.
 func (s *SimpleScheduler) scheduleOnce(ctx context.Context) {
-	readyNodes, err := s.StateManager.FindReadyNodes(ctx)
+	batchSize := s.ClaimBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultClaimBatchSize
+	}
+	// Never claim more than the pool can hold, nor nodes whose workflow or
+	// model is at its limit, so that nodes this replica cannot run stay
+	// available to other replicas.
+	pool := s.Pool()
+	if free := pool.FreeSlots(); free >= 0 && free < batchSize {
+		batchSize = free
+	}
+	if batchSize == 0 {
+		return
+	}
+	candidates, err := s.StateManager.FindReadyNodes(ctx, candidateFactor*batchSize)
 	if err != nil {
 		log.Printf("Error finding ready nodes: %v", err)
 		return
 	}
+	if len(candidates) == 0 {
+		return
+	}
+	policy := s.Policy
+	if policy == nil {
+		policy = FIFOPolicy{}
+	}
+	candidates = pool.Fit(policy.Order(candidates, pool.InFlight()), batchSize)
+	if len(candidates) == 0 {
+		return
+	}
+	readyNodes, err := s.StateManager.ClaimNodes(ctx, s.ID, candidates)
+	if err != nil {
+		log.Printf("Error claiming ready nodes: %v", err)
+		return
+	}
 
-	for _, node := range readyNodes {
-		go s.dispatchNode(ctx, node)
+	for _, ready := range readyNodes {
+		pool.Submit(ctx, ready, func(ctx context.Context, ready *persistence.ReadyNode) {
+			if final := s.dispatchNode(ctx, ready.WorkflowID, ready.Node); final != nil {
+				s.applyFailurePolicy(ready, final)
+			}
+		})
 	}
 }
 
-// dispatchNode dispatches a single node to the NodeService.
-func (s *SimpleScheduler) dispatchNode(ctx context.Context, node *pb.Node) {
-	// TODO: Determine how to properly obtain the workflowID for a given node.
-	// Using a placeholder for now as tests don't depend on a specific ID.
-	workflowID := "unknown_workflow"
-	nodeID := node.NodeId
+// checkLeases renews the leases of this scheduler's in-flight nodes of the
+// given workflows, so that nodes canceled or recovered elsewhere stop without
+// waiting for their next heartbeat.
+func (s *SimpleScheduler) checkLeases(ctx context.Context, workflowIDs map[string]bool) {
+	var nodes []*persistence.ReadyNode
+	for _, ready := range s.Pool().InFlight() {
+		if workflowIDs[ready.WorkflowID] {
+			nodes = append(nodes, ready)
+		}
+	}
+	s.renewLeases(ctx, nodes)
+}
+
+// renewLeases renews the leases of claimed nodes that are queued or running,
+// canceling any whose lease was lost, e.g. because the node was canceled or
+// recovered by another scheduler.
+func (s *SimpleScheduler) renewLeases(ctx context.Context, nodes []*persistence.ReadyNode) {
+	pool := s.Pool()
+	for _, ready := range nodes {
+		_, err := s.StateManager.RenewLease(ctx, ready.WorkflowID, ready.Node.NodeId, s.ID)
+		if errors.Is(err, persistence.ErrLeaseLost) {
+			log.Printf("Lease on node %s lost; canceling its dispatch", ready.Node.NodeId)
+			pool.Cancel(ready.WorkflowID, ready.Node.NodeId)
+		} else if err != nil {
+			log.Printf("Failed to renew lease on node %s: %v", ready.Node.NodeId, err)
+		}
+	}
+}
 
-	// Update node status to RUNNING
-	node.Status = pb.Status_RUNNING
-	if err := s.StateManager.UpdateNode(ctx, workflowID, node); err != nil {
-		log.Printf("Failed to update node %s status to RUNNING: %v", nodeID, err)
+func (s *SimpleScheduler) logPoolMetrics() {
+	m := s.Pool().Metrics()
+	if m.Admitted == 0 && m.QueueDepth == 0 {
 		return
 	}
+	log.Printf("Dispatch pool: running=%d queued=%d max_queued=%d admitted=%d avg_wait=%s max_wait=%s",
+		m.Running, m.QueueDepth, m.MaxQueueDepth, m.Admitted, m.AverageAdmissionWait(), m.MaxAdmissionWait)
+}
 
-	// Build ExecuteNodeRequest
+// dispatchNode dispatches a single claimed node of the given workflow to the
+// NodeService, or records it directly if it is of a built-in type. The claim
+// has already moved the node to RUNNING. It returns the node as recorded with
+// its final outcome, or nil if none was recorded.
+func (s *SimpleScheduler) dispatchNode(ctx context.Context, workflowID string, node *pb.Node) *pb.Node {
+	if node.GetType() == pb.NodeType_REDUCE && !s.collectResults(ctx, workflowID, node) {
+		return nil
+	}
+	if node.GetType() == pb.NodeType_SUBWORKFLOW {
+		return s.startSubworkflow(ctx, workflowID, node)
+	}
+	if status, ok := builtinStatus(node); ok {
+		return s.completeBuiltin(ctx, workflowID, node, status)
+	}
+	nodeID := node.NodeId
+
+	// Keep the lease alive while the node executes. If it is lost, another
+	// scheduler may already own the node, so abandon the dispatch.
+	dispatchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var leaseLost atomic.Bool
+	go s.heartbeat(dispatchCtx, workflowID, nodeID, func() {
+		leaseLost.Store(true)
+		cancel()
+	})
+
+	// Build ExecuteNodeRequest; upstream and downstream nodes are attached
+	// by each attempt.
 	req := &pb.ExecuteNodeRequest{
 		WorkflowId: workflowID,
 		NodeId:     nodeID,
 		Node:       node,
-		// Upstream and downstream nodes can be fetched if needed
 	}
 
-	resp, err := s.NodeServiceClient.ExecuteNode(ctx, req)
-	if err != nil {
-		log.Printf("Error executing node %s: %v", nodeID, err)
-		node.Status = pb.Status_INFRA_ERROR
-		_ = s.StateManager.UpdateNode(ctx, workflowID, node)
-		return
+	var updatedNode *pb.Node
+	for {
+		result, attempt := s.executeAttempt(dispatchCtx, req)
+		if leaseLost.Load() {
+			log.Printf("Discarding result of node %s: lease lost during execution", nodeID)
+			return nil
+		}
+		node.Attempts = append(node.Attempts, attempt)
+		result.Attempts = node.Attempts
+		if attempt.Error != "" {
+			log.Printf("Attempt %d of node %s failed with %v: %s", attempt.Number, nodeID, attempt.Status, attempt.Error)
+		}
+
+		if !isRetryable(attempt.Status) || len(node.Attempts) >= maxAttempts(node) || dispatchCtx.Err() != nil {
+			updatedNode = result
+			break
+		}
+
+		// Persist the failed attempt before backing off so that the history
+		// survives a scheduler crash; the node stays RUNNING under our lease.
+		if err := s.StateManager.UpdateLeasedNode(ctx, workflowID, s.ID, node); errors.Is(err, persistence.ErrLeaseLost) {
+			log.Printf("Abandoning node %s: lease lost before retrying", nodeID)
+			return nil
+		} else if err != nil {
+			log.Printf("Failed to record attempt %d of node %s: %v", attempt.Number, nodeID, err)
+		}
+		select {
+		case <-dispatchCtx.Done():
+			return nil
+		case <-time.After(retryBackoff(node, int(attempt.Number))):
+		}
 	}
 
-	// Update node with response
-	updatedNode := resp.Node
-	if err := s.StateManager.UpdateNode(ctx, workflowID, updatedNode); err != nil {
+	// Update node with the final outcome, unless it was canceled or
+	// recovered while executing.
+	if err := s.StateManager.UpdateLeasedNode(ctx, workflowID, s.ID, updatedNode); errors.Is(err, persistence.ErrLeaseLost) {
+		log.Printf("Discarding result of node %s: lease lost before it was recorded", nodeID)
+		return nil
+	} else if err != nil {
 		log.Printf("Failed to update node %s after execution: %v", nodeID, err)
-		return
+		return nil
 	}
 
-	// Apply any NodeEdits transactionally
+	// Apply any NodeEdits transactionally, within the edit policy
 	if len(updatedNode.Edits) > 0 {
-		if err := s.StateManager.ApplyNodeEdits(ctx, workflowID, updatedNode.Edits); err != nil {
+		rejected, err := s.StateManager.ApplyAgentEdits(ctx, workflowID, nodeID, updatedNode.Edits)
+		if err != nil {
 			log.Printf("Failed to apply edits for node %s: %v", nodeID, err)
 		}
+		for _, r := range rejected {
+			log.Printf("Rejected %v edit of node %s from node %s: %s", r.Edit.GetType(), r.Edit.GetNode().GetNodeId(), nodeID, r.Reason)
+		}
 	}
+	return updatedNode
 }
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// recordingNodeClient is a NodeServiceClient that completes every node it is
+// asked to execute with a fixed status and records the requests it received.
+type recordingNodeClient struct {
+	mu       sync.Mutex
+	status   pb.Status
+	requests []*pb.ExecuteNodeRequest
+}
+
+func (c *recordingNodeClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	node := &pb.Node{
+		NodeId:      req.Node.NodeId,
+		Description: req.Node.Description,
+		ParentIds:   req.Node.ParentIds,
+		ChildIds:    req.Node.ChildIds,
+		Status:      c.status,
+	}
+	return &pb.ExecuteNodeResponse{Node: node}, nil
+}
+
+func (c *recordingNodeClient) executed() []*pb.ExecuteNodeRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*pb.ExecuteNodeRequest(nil), c.requests...)
+}
+
+func newIntegrationStateManager(t *testing.T) *persistence.PostgresStateManager {
+	t.Helper()
+	connStr := os.Getenv("TEST_DATABASE_URL")
+	if connStr == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping Postgres integration test")
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, connStr)
+	if err != nil {
+		t.Fatalf("failed to connect for cleanup: %v", err)
+	}
+	defer conn.Close(ctx)
+	if _, err := conn.Exec(ctx, "TRUNCATE node_edges, nodes, workflows RESTART IDENTITY CASCADE;"); err != nil {
+		t.Fatalf("failed to clean db: %v", err)
+	}
+
+	sm, err := persistence.NewPostgresStateManagerFromConnStr(ctx, connStr)
+	if err != nil {
+		t.Fatalf("failed to create state manager: %v", err)
+	}
+	t.Cleanup(func() { sm.Close() })
+	return sm
+}
+
+// createDiamondWorkflow creates a workflow shaped a -> {b, c} -> d and returns
+// its ID along with the node IDs in that order.
+func createDiamondWorkflow(t *testing.T, sm *persistence.PostgresStateManager, name string, status pb.Status) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	wf := &persistence.Workflow{Name: name, Description: "scheduler integration", Status: pb.Status_UNKNOWN}
+	workflowID, err := sm.CreateWorkflow(ctx, wf)
+	if err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	a, b, c, d := uuid.New().String(), uuid.New().String(), uuid.New().String(), uuid.New().String()
+	nodes := []*pb.Node{
+		{NodeId: a, Description: name + "/a", ChildIds: []string{b, c}, Status: status},
+		{NodeId: b, Description: name + "/b", ParentIds: []string{a}, ChildIds: []string{d}, Status: status},
+		{NodeId: c, Description: name + "/c", ParentIds: []string{a}, ChildIds: []string{d}, Status: status},
+		{NodeId: d, Description: name + "/d", ParentIds: []string{b, c}, Status: status},
+	}
+	var edits []*pb.NodeEdit
+	for _, n := range nodes {
+		edits = append(edits, &pb.NodeEdit{Type: pb.NodeEdit_INSERT, Node: n})
+	}
+	if err := sm.ApplyNodeEdits(ctx, workflowID, edits); err != nil {
+		t.Fatalf("ApplyNodeEdits failed: %v", err)
+	}
+	return workflowID, []string{a, b, c, d}
+}
+
+func TestSchedulerRunsMultiWorkflowDAGAgainstPostgres(t *testing.T) {
+	sm := newIntegrationStateManager(t)
+
+	wf1, nodes1 := createDiamondWorkflow(t, sm, "wf1", pb.Status_BLOCKED)
+	wf2, nodes2 := createDiamondWorkflow(t, sm, "wf2", pb.Status_BLOCKED)
+	owner := make(map[string]string)
+	for _, id := range nodes1 {
+		owner[id] = wf1
+	}
+	for _, id := range nodes2 {
+		owner[id] = wf2
+	}
+
+	// Two replicas share the database; claiming keeps them from racing.
+	client := &recordingNodeClient{status: pb.Status_PASS}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		go NewSimpleScheduler(sm, client, 10*time.Millisecond).Run(ctx)
+	}
+
+	for !allNodesHaveStatus(t, sm, owner, pb.Status_PASS) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for both workflows to complete")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	cancel()
+
+	// Every node is dispatched exactly once with its owning workflow, and no
+	// node is dispatched before all of its parents.
+	executedAt := make(map[string]int)
+	for i, req := range client.executed() {
+		if want := owner[req.NodeId]; req.WorkflowId != want {
+			t.Errorf("node %s dispatched with workflow %q, want %q", req.NodeId, req.WorkflowId, want)
+		}
+		if _, seen := executedAt[req.NodeId]; seen {
+			t.Errorf("node %s dispatched more than once", req.NodeId)
+		}
+		executedAt[req.NodeId] = i
+	}
+	for _, ids := range [][]string{nodes1, nodes2} {
+		a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+		for _, edge := range [][2]string{{a, b}, {a, c}, {b, d}, {c, d}} {
+			if executedAt[edge[0]] >= executedAt[edge[1]] {
+				t.Errorf("node %s dispatched before its parent %s", edge[1], edge[0])
+			}
+		}
+	}
+}
+
+func allNodesHaveStatus(t *testing.T, sm *persistence.PostgresStateManager, owner map[string]string, status pb.Status) bool {
+	t.Helper()
+	for id, workflowID := range owner {
+		node, err := sm.GetNode(context.Background(), workflowID, id)
+		if err != nil {
+			t.Fatalf("GetNode(%s, %s) failed: %v", workflowID, id, err)
+		}
+		if node.Status != status {
+			return false
+		}
+	}
+	return true
+}
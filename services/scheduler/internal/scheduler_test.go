@@ -3,50 +3,232 @@ package scheduler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
 	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
 )
 
 // FakeStateManager implements StateManager for testing.
 type FakeStateManager struct {
-	mu           sync.Mutex
-	readyNodes   []*pb.Node
-	updatedNodes []*pb.Node
-	appliedEdits [][]*pb.NodeEdit
+	mu                sync.Mutex
+	readyNodes        []*pb.Node
+	workflowID        string
+	ready             []*persistence.ReadyNode // ready nodes of other workflows
+	claimedBy         map[string]string
+	claimOrder        []string
+	nodes             map[string]*pb.Node // nodes returned by GetNodes
+	updatedNodes      []*pb.Node
+	updateWorkflowIDs []string
+	appliedEdits      [][]*pb.NodeEdit
+	editWorkflowIDs   []string
+	renewals          int
+	leaseLost         bool
+	failFast          bool            // a recorded failure revokes every other claimed node's lease
+	revoked           map[string]bool // nodes whose lease was revoked, e.g. by cancellation
+	expired           []*persistence.ReadyNode
+	recoveredStatuses []pb.Status
+	released          []string
+	subworkflows      []*pb.Node // nodes whose child workflow was started
+	subworkflowErr    error
+	paused            map[string]bool // workflows that are paused
+	findLimits        []int           // limits FindReadyNodes was called with
 }
 
-func (m *FakeStateManager) FindReadyNodes(ctx context.Context) ([]*pb.Node, error) {
+// FindReadyNodes returns up to limit ready nodes that have not been claimed
+// yet, taking them from each workflow in turn like the Postgres state manager.
+func (m *FakeStateManager) FindReadyNodes(ctx context.Context, limit int) ([]*persistence.ReadyNode, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return m.readyNodes, nil
+	m.findLimits = append(m.findLimits, limit)
+	workflowID := m.workflowID
+	if workflowID == "" {
+		workflowID = "wf-test"
+	}
+	var ready []*persistence.ReadyNode
+	for _, n := range m.readyNodes {
+		if _, claimed := m.claimedBy[n.NodeId]; !claimed {
+			ready = append(ready, &persistence.ReadyNode{WorkflowID: workflowID, Node: n})
+		}
+	}
+	for _, rn := range m.ready {
+		if _, claimed := m.claimedBy[rn.Node.NodeId]; !claimed {
+			ready = append(ready, rn)
+		}
+	}
+	if limit > 0 && len(ready) > limit {
+		ready = roundRobin(ready)[:limit]
+	}
+	return ready, nil
+}
+
+// roundRobin orders ready nodes by their rank within their workflow, keeping
+// the given order among nodes of the same rank.
+func roundRobin(ready []*persistence.ReadyNode) []*persistence.ReadyNode {
+	rank := make(map[*persistence.ReadyNode]int, len(ready))
+	seen := make(map[string]int)
+	for _, rn := range ready {
+		rank[rn] = seen[rn.WorkflowID]
+		seen[rn.WorkflowID]++
+	}
+	ordered := append([]*persistence.ReadyNode(nil), ready...)
+	sort.SliceStable(ordered, func(i, j int) bool { return rank[ordered[i]] < rank[ordered[j]] })
+	return ordered
+}
+
+// ClaimNodes hands out each ready node at most once, mirroring the exclusive
+// claim semantics of the Postgres state manager.
+func (m *FakeStateManager) ClaimNodes(ctx context.Context, schedulerID string, nodes []*persistence.ReadyNode) ([]*persistence.ReadyNode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.claimedBy == nil {
+		m.claimedBy = make(map[string]string)
+	}
+	var claimed []*persistence.ReadyNode
+	for _, rn := range nodes {
+		if _, ok := m.claimedBy[rn.Node.NodeId]; ok {
+			continue
+		}
+		m.claimedBy[rn.Node.NodeId] = schedulerID
+		m.claimOrder = append(m.claimOrder, rn.Node.NodeId)
+		claimed = append(claimed, rn)
+	}
+	return claimed, nil
+}
+
+func (m *FakeStateManager) PausedWorkflows(ctx context.Context, workflowIDs []string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var paused []string
+	for _, id := range workflowIDs {
+		if m.paused[id] {
+			paused = append(paused, id)
+		}
+	}
+	return paused, nil
+}
+
+func (m *FakeStateManager) GetNodes(ctx context.Context, workflowID string, nodeIDs []string) ([]*pb.Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var nodes []*pb.Node
+	for _, id := range nodeIDs {
+		if n, ok := m.nodes[id]; ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
 }
 
-func (m *FakeStateManager) UpdateNode(ctx context.Context, workflowID string, node *pb.Node) error {
+// UpdateLeasedNode records node unless its lease was lost or revoked.
+func (m *FakeStateManager) UpdateLeasedNode(ctx context.Context, workflowID, schedulerID string, node *pb.Node) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.leaseLost || m.revoked[node.NodeId] {
+		return persistence.ErrLeaseLost
+	}
 	m.updatedNodes = append(m.updatedNodes, node)
+	m.updateWorkflowIDs = append(m.updateWorkflowIDs, workflowID)
+	if m.failFast && persistence.IsFailureStatus(node.Status) {
+		if m.revoked == nil {
+			m.revoked = make(map[string]bool)
+		}
+		for id := range m.claimedBy {
+			if id != node.NodeId {
+				m.revoked[id] = true
+			}
+		}
+	}
 	return nil
 }
 
-func (m *FakeStateManager) ApplyNodeEdits(ctx context.Context, workflowID string, edits []*pb.NodeEdit) error {
+func (m *FakeStateManager) ApplyAgentEdits(ctx context.Context, workflowID, nodeID string, edits []*pb.NodeEdit) ([]*pb.RejectedEdit, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.appliedEdits = append(m.appliedEdits, edits)
-	return nil
+	m.editWorkflowIDs = append(m.editWorkflowIDs, workflowID)
+	return nil, nil
+}
+
+func (m *FakeStateManager) RenewLease(ctx context.Context, workflowID, nodeID, schedulerID string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renewals++
+	if m.leaseLost || m.revoked[nodeID] {
+		return time.Time{}, persistence.ErrLeaseLost
+	}
+	return time.Now().Add(time.Minute), nil
+}
+
+func (m *FakeStateManager) RecoverExpiredLeases(ctx context.Context, status pb.Status, reason string, limit int) ([]*persistence.ReadyNode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recoveredStatuses = append(m.recoveredStatuses, status)
+	recovered := m.expired
+	m.expired = nil
+	return recovered, nil
+}
+
+// ReleaseClaims revokes the leases of the given nodes, unless they were
+// already recorded or revoked.
+func (m *FakeStateManager) ReleaseClaims(ctx context.Context, schedulerID string, nodes []*persistence.ReadyNode) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.revoked == nil {
+		m.revoked = make(map[string]bool)
+	}
+	recorded := map[string]bool{}
+	for _, n := range m.updatedNodes {
+		recorded[n.NodeId] = true
+	}
+	released := 0
+	for _, rn := range nodes {
+		id := rn.Node.NodeId
+		if recorded[id] || m.revoked[id] || m.leaseLost {
+			continue
+		}
+		m.revoked[id] = true
+		m.released = append(m.released, id)
+		released++
+	}
+	return released, nil
+}
+
+// StartSubworkflow records node as waiting for a child workflow.
+func (m *FakeStateManager) StartSubworkflow(ctx context.Context, workflowID, schedulerID string, node *pb.Node) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leaseLost || m.revoked[node.NodeId] {
+		return "", persistence.ErrLeaseLost
+	}
+	if m.subworkflowErr != nil {
+		return "", m.subworkflowErr
+	}
+	childID := "child-of-" + node.NodeId
+	node.GetSubworkflowOptions().ChildWorkflowId = childID
+	node.Status = pb.Status_WAITING_FOR_WORKFLOW
+	m.subworkflows = append(m.subworkflows, node)
+	return childID, nil
 }
 
 // FakeNodeServiceClient implements NodeServiceClient for testing.
 type FakeNodeServiceClient struct {
+	mu       sync.Mutex
 	Response *pb.ExecuteNodeResponse
 	Err      error
 	Called   bool
+	Requests []*pb.ExecuteNodeRequest
 }
 
 func (m *FakeNodeServiceClient) ExecuteNode(ctx context.Context, req *pb.ExecuteNodeRequest) (*pb.ExecuteNodeResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Called = true
+	m.Requests = append(m.Requests, req)
 	return m.Response, m.Err
 }
 
@@ -197,6 +379,97 @@ func TestSchedulerAppliesNodeEdits(t *testing.T) {
 	}
 }
 
+func TestSchedulerPropagatesWorkflowID(t *testing.T) {
+	fakeSM := &FakeStateManager{
+		workflowID: "wf-42",
+		readyNodes: []*pb.Node{
+			{NodeId: "node4", Status: pb.Status_BLOCKED},
+		},
+	}
+	fakeClient := &FakeNodeServiceClient{
+		Response: &pb.ExecuteNodeResponse{
+			Node: &pb.Node{
+				NodeId: "node4",
+				Status: pb.Status_PASS,
+				Edits:  []*pb.NodeEdit{{Description: "edit"}},
+			},
+		},
+	}
+	sched := NewSimpleScheduler(fakeSM, fakeClient, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	sched.Run(ctx)
+	// Give in-flight dispatches a moment to persist their results.
+	time.Sleep(10 * time.Millisecond)
+
+	fakeClient.mu.Lock()
+	for _, req := range fakeClient.Requests {
+		if req.WorkflowId != "wf-42" {
+			t.Errorf("ExecuteNodeRequest.WorkflowId = %q, want %q", req.WorkflowId, "wf-42")
+		}
+	}
+	fakeClient.mu.Unlock()
+
+	fakeSM.mu.Lock()
+	defer fakeSM.mu.Unlock()
+	if len(fakeSM.updateWorkflowIDs) == 0 {
+		t.Fatalf("Expected UpdateLeasedNode to be called")
+	}
+	for _, id := range append(fakeSM.updateWorkflowIDs, fakeSM.editWorkflowIDs...) {
+		if id != "wf-42" {
+			t.Errorf("persistence call used workflow %q, want %q", id, "wf-42")
+		}
+	}
+}
+
+func TestSchedulerReplicasNeverDispatchTwice(t *testing.T) {
+	fakeSM := &FakeStateManager{}
+	for i := 0; i < 20; i++ {
+		fakeSM.readyNodes = append(fakeSM.readyNodes, &pb.Node{NodeId: fmt.Sprintf("node-%d", i), Status: pb.Status_READY})
+	}
+	fakeClient := &FakeNodeServiceClient{
+		Response: &pb.ExecuteNodeResponse{Node: &pb.Node{Status: pb.Status_PASS}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	replicas := []*SimpleScheduler{
+		NewSimpleScheduler(fakeSM, fakeClient, 1*time.Millisecond),
+		NewSimpleScheduler(fakeSM, fakeClient, 1*time.Millisecond),
+	}
+	if replicas[0].ID == replicas[1].ID {
+		t.Fatalf("expected distinct scheduler IDs, both were %q", replicas[0].ID)
+	}
+	var wg sync.WaitGroup
+	for _, r := range replicas {
+		r.ClaimBatchSize = 3
+		wg.Add(1)
+		go func(r *SimpleScheduler) {
+			defer wg.Done()
+			r.Run(ctx)
+		}(r)
+	}
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+	dispatched := make(map[string]int)
+	for _, req := range fakeClient.Requests {
+		dispatched[req.NodeId]++
+	}
+	if len(dispatched) != 20 {
+		t.Errorf("expected all 20 nodes to be dispatched, got %d", len(dispatched))
+	}
+	for id, n := range dispatched {
+		if n != 1 {
+			t.Errorf("node %s dispatched %d times, want 1", id, n)
+		}
+	}
+}
+
 func FuzzSchedulerAppliesNodeEdits(f *testing.F) {
 	f.Add("initial description", int32(pb.Status_BLOCKED), "edit description", int32(pb.Status_PASS))
 
@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// startSubworkflow starts the child workflow of a claimed SUBWORKFLOW node,
+// which then waits for the child to complete. A node without a template to
+// start fails with TASK_ERROR. If the child cannot be created, the claim on
+// node is released so that it is retried. It returns the node as recorded, or
+// nil if nothing was recorded.
+func (s *SimpleScheduler) startSubworkflow(ctx context.Context, workflowID string, node *pb.Node) *pb.Node {
+	if len(node.GetSubworkflowOptions().GetTemplate().GetNodes()) == 0 {
+		log.Printf("Subworkflow node %s has no template nodes to start", node.NodeId)
+		return s.completeBuiltin(ctx, workflowID, node, pb.Status_TASK_ERROR)
+	}
+	childID, err := s.StateManager.StartSubworkflow(ctx, workflowID, s.ID, node)
+	if errors.Is(err, persistence.ErrLeaseLost) {
+		log.Printf("Discarding subworkflow node %s: lease lost before its child was started", node.NodeId)
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to start child workflow of node %s: %v", node.NodeId, err)
+		s.releaseClaims(ctx, []*persistence.ReadyNode{{WorkflowID: workflowID, Node: node}})
+		return nil
+	}
+	log.Printf("Subworkflow node %s started child workflow %s", node.NodeId, childID)
+	return node
+}
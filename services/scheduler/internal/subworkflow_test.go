@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func TestSchedulerStartsSubworkflows(t *testing.T) {
+	template := &pb.CreateWorkflowRequest{Nodes: []*pb.Node{{NodeId: "step"}}}
+	tests := []struct {
+		name         string
+		options      *pb.SubworkflowOptions
+		startErr     error
+		wantStarted  bool
+		wantStatus   pb.Status // of the recorded node, if any
+		wantReleased bool
+	}{
+		{name: "started", options: &pb.SubworkflowOptions{Template: template}, wantStarted: true},
+		{name: "no template", options: &pb.SubworkflowOptions{}, wantStatus: pb.Status_TASK_ERROR},
+		{name: "start fails", options: &pb.SubworkflowOptions{Template: template}, startErr: errors.New("db down"), wantReleased: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSM := &FakeStateManager{
+				readyNodes:     []*pb.Node{{NodeId: "sub", Type: pb.NodeType_SUBWORKFLOW, SubworkflowOptions: tt.options}},
+				subworkflowErr: tt.startErr,
+			}
+			fakeClient := &FakeNodeServiceClient{}
+			sched := NewSimpleScheduler(fakeSM, fakeClient, 0)
+			sched.scheduleOnce(context.Background())
+			sched.Pool().Wait()
+
+			if fakeClient.Called {
+				t.Errorf("expected a subworkflow node not to be sent to the NodeService")
+			}
+			fakeSM.mu.Lock()
+			defer fakeSM.mu.Unlock()
+			if started := len(fakeSM.subworkflows) == 1; started != tt.wantStarted {
+				t.Errorf("started child workflows: %v, want %v", fakeSM.subworkflows, tt.wantStarted)
+			}
+			if tt.wantStarted && fakeSM.subworkflows[0].Status != pb.Status_WAITING_FOR_WORKFLOW {
+				t.Errorf("expected the node to wait for its child, got %v", fakeSM.subworkflows[0].Status)
+			}
+			if tt.wantStatus != pb.Status_UNKNOWN {
+				if len(fakeSM.updatedNodes) != 1 || fakeSM.updatedNodes[0].Status != tt.wantStatus {
+					t.Errorf("expected the node to be recorded with %v, got %v", tt.wantStatus, fakeSM.updatedNodes)
+				}
+			} else if len(fakeSM.updatedNodes) != 0 {
+				t.Errorf("expected nothing to be recorded, got %v", fakeSM.updatedNodes)
+			}
+			if released := len(fakeSM.released) == 1; released != tt.wantReleased {
+				t.Errorf("released claims %v, want released %v", fakeSM.released, tt.wantReleased)
+			}
+		})
+	}
+}
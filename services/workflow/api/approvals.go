@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+func (s *WorkflowServiceServerImpl) ListPendingApprovals(ctx context.Context, req *pb.ListPendingApprovalsRequest) (*pb.ListPendingApprovalsResponse, error) {
+	pending, err := s.StateManager.ListPendingApprovals(ctx, req.GetWorkflowId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list pending approvals: %v", err)
+	}
+	resp := &pb.ListPendingApprovalsResponse{}
+	for _, a := range pending {
+		resp.Approvals = append(resp.Approvals, &pb.PendingApproval{
+			WorkflowId:   a.WorkflowID,
+			Node:         a.Node,
+			WaitingSince: timestamppb.New(a.WaitingSince),
+		})
+	}
+	return resp, nil
+}
+
+func (s *WorkflowServiceServerImpl) ApproveNode(ctx context.Context, req *pb.ApproveNodeRequest) (*pb.ApproveNodeResponse, error) {
+	if err := s.decideApproval(ctx, req.GetWorkflowId(), req.GetNodeId(), &pb.Approval{
+		Approved: true,
+		Approver: req.GetCaller(),
+		Comment:  req.GetComment(),
+	}); err != nil {
+		return nil, err
+	}
+	s.logEvent(EventNodeApproved, "ApproveNodeRequest", req)
+	return &pb.ApproveNodeResponse{Success: true}, nil
+}
+
+func (s *WorkflowServiceServerImpl) RejectNode(ctx context.Context, req *pb.RejectNodeRequest) (*pb.RejectNodeResponse, error) {
+	if err := s.decideApproval(ctx, req.GetWorkflowId(), req.GetNodeId(), &pb.Approval{
+		Approver: req.GetCaller(),
+		Comment:  req.GetComment(),
+	}); err != nil {
+		return nil, err
+	}
+	s.logEvent(EventNodeRejected, "RejectNodeRequest", req)
+	return &pb.RejectNodeResponse{Success: true}, nil
+}
+
+// decideApproval records a decision on a waiting APPROVAL node, mapping
+// failures to gRPC status errors. The caller is required, since it is the
+// record of who made the decision.
+func (s *WorkflowServiceServerImpl) decideApproval(ctx context.Context, workflowID, nodeID string, approval *pb.Approval) error {
+	if workflowID == "" || nodeID == "" {
+		return status.Errorf(codes.InvalidArgument, "workflow_id and node_id are required")
+	}
+	if approval.GetApprover().GetAgent() == "" {
+		return status.Errorf(codes.InvalidArgument, "caller.agent is required to record the approver")
+	}
+	approval.DecidedAt = timestamppb.Now()
+
+	if _, err := s.StateManager.DecideApproval(ctx, workflowID, nodeID, approval); err != nil {
+		switch {
+		case errors.Is(err, persistence.ErrNodeNotFound):
+			return status.Errorf(codes.NotFound, "node %s not found in workflow %s", nodeID, workflowID)
+		case errors.Is(err, persistence.ErrNotWaitingForApproval):
+			return status.Errorf(codes.FailedPrecondition, "node %s is not waiting for approval", nodeID)
+		}
+		return status.Errorf(codes.Internal, "failed to record decision on node %s: %v", nodeID, err)
+	}
+	return nil
+}
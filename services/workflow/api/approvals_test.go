@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+func TestListPendingApprovals(t *testing.T) {
+	since := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	fakeSM := &fakeStateManager{
+		ListPendingApprovalsFunc: func(ctx context.Context, workflowID string) ([]*persistence.PendingApproval, error) {
+			if workflowID != "wf-1" {
+				t.Errorf("listed approvals of workflow %q, want wf-1", workflowID)
+			}
+			return []*persistence.PendingApproval{{WorkflowID: "wf-1", Node: &pb.Node{NodeId: "gate"}, WaitingSince: since}}, nil
+		},
+	}
+	server := NewWorkflowServiceServer(fakeSM, nil)
+	resp, err := server.ListPendingApprovals(context.Background(), &pb.ListPendingApprovalsRequest{WorkflowId: "wf-1"})
+	if err != nil {
+		t.Fatalf("ListPendingApprovals failed: %v", err)
+	}
+	if len(resp.Approvals) != 1 || resp.Approvals[0].Node.NodeId != "gate" || !resp.Approvals[0].WaitingSince.AsTime().Equal(since) {
+		t.Errorf("unexpected response %v", resp)
+	}
+}
+
+func TestApproveAndRejectNode(t *testing.T) {
+	logger := &recordingEventLogger{}
+	fakeSM := &fakeStateManager{}
+	server := NewWorkflowServiceServer(fakeSM, logger)
+	ctx := context.Background()
+	founder := &pb.Caller{Agent: "founder"}
+
+	var decisions []*pb.Approval
+	fakeSM.DecideApprovalFunc = func(ctx context.Context, workflowID, nodeID string, approval *pb.Approval) (*pb.Node, error) {
+		switch nodeID {
+		case "missing":
+			return nil, persistence.ErrNodeNotFound
+		case "decided":
+			return nil, persistence.ErrNotWaitingForApproval
+		}
+		decisions = append(decisions, approval)
+		return &pb.Node{NodeId: nodeID}, nil
+	}
+
+	if _, err := server.ApproveNode(ctx, &pb.ApproveNodeRequest{WorkflowId: "wf-1", NodeId: "gate", Comment: "ok", Caller: founder}); err != nil {
+		t.Fatalf("ApproveNode failed: %v", err)
+	}
+	if _, err := server.RejectNode(ctx, &pb.RejectNodeRequest{WorkflowId: "wf-1", NodeId: "gate", Caller: founder}); err != nil {
+		t.Fatalf("RejectNode failed: %v", err)
+	}
+	if len(decisions) != 2 || !decisions[0].Approved || decisions[1].Approved {
+		t.Fatalf("expected an approval then a rejection, got %v", decisions)
+	}
+	if decisions[0].Approver.GetAgent() != "founder" || decisions[0].Comment != "ok" || decisions[0].DecidedAt == nil {
+		t.Errorf("expected the approver, comment and time to be recorded, got %v", decisions[0])
+	}
+	if len(logger.events) != 2 || logger.events[0].Type != EventNodeApproved || logger.events[1].Type != EventNodeRejected {
+		t.Errorf("expected NodeApproved and NodeRejected events, got %+v", logger.events)
+	}
+
+	for _, tt := range []struct {
+		req  *pb.ApproveNodeRequest
+		want codes.Code
+	}{
+		{&pb.ApproveNodeRequest{WorkflowId: "wf-1", Caller: founder}, codes.InvalidArgument},
+		{&pb.ApproveNodeRequest{WorkflowId: "wf-1", NodeId: "gate"}, codes.InvalidArgument},
+		{&pb.ApproveNodeRequest{WorkflowId: "wf-1", NodeId: "missing", Caller: founder}, codes.NotFound},
+		{&pb.ApproveNodeRequest{WorkflowId: "wf-1", NodeId: "decided", Caller: founder}, codes.FailedPrecondition},
+	} {
+		_, err := server.ApproveNode(ctx, tt.req)
+		if st, ok := status.FromError(err); !ok || st.Code() != tt.want {
+			t.Errorf("ApproveNode(%v) = %v, want %v", tt.req, err, tt.want)
+		}
+	}
+}
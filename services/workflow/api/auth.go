@@ -82,10 +82,20 @@ var methodPermissions = map[string]Role{
 	"/protos.WorkflowService/CreateWorkflow": RoleAdmin,
 	"/protos.WorkflowService/UpdateWorkflow": RoleAdmin,
 	"/protos.WorkflowService/UpdateNode":     RoleAdmin,
+	"/protos.WorkflowService/CancelWorkflow": RoleAdmin,
+	"/protos.WorkflowService/CancelNode":     RoleAdmin,
+	"/protos.WorkflowService/PauseWorkflow":  RoleAdmin,
+	"/protos.WorkflowService/ResumeWorkflow": RoleAdmin,
+	"/protos.WorkflowService/CreateTrigger":  RoleAdmin,
+	"/protos.WorkflowService/DeleteTrigger":  RoleAdmin,
+	"/protos.WorkflowService/ApproveNode":    RoleAdmin,
+	"/protos.WorkflowService/RejectNode":     RoleAdmin,
 	// Read-only endpoints can be accessed by any authenticated user.
-	"/protos.WorkflowService/GetWorkflow":   RoleUser,
-	"/protos.WorkflowService/ListWorkflows": RoleUser,
-	"/protos.WorkflowService/GetNode":       RoleUser,
+	"/protos.WorkflowService/GetWorkflow":          RoleUser,
+	"/protos.WorkflowService/ListWorkflows":        RoleUser,
+	"/protos.WorkflowService/GetNode":              RoleUser,
+	"/protos.WorkflowService/ListTriggers":         RoleUser,
+	"/protos.WorkflowService/ListPendingApprovals": RoleUser,
 }
 
 // AuthInterceptor is a gRPC unary interceptor for authentication and authorization.
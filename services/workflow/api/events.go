@@ -16,9 +16,17 @@ const (
 	EventWorkflowUpdated    EventType = "WorkflowUpdated"
 	EventWorkflowCompleted  EventType = "WorkflowCompleted"
 	EventWorkflowDispatched EventType = "WorkflowDispatched"
+	EventWorkflowCanceled   EventType = "WorkflowCanceled"
+	EventWorkflowPaused     EventType = "WorkflowPaused"
+	EventWorkflowResumed    EventType = "WorkflowResumed"
 	EventNodeUpdated        EventType = "NodeUpdated"
 	EventNodeCompleted      EventType = "NodeCompleted"
 	EventNodeDispatched     EventType = "NodeDispatched"
+	EventNodeCanceled       EventType = "NodeCanceled"
+	EventNodeApproved       EventType = "NodeApproved"
+	EventNodeRejected       EventType = "NodeRejected"
+	EventTriggerCreated     EventType = "TriggerCreated"
+	EventTriggerDeleted     EventType = "TriggerDeleted"
 )
 
 // Event represents a workflow or node event.
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+// DefaultReconcileInterval is how often WorkflowReconciler re-examines every
+// active workflow when WorkflowReconciler.Interval is zero.
+const DefaultReconcileInterval = 30 * time.Second
+
+// NodeEventSource delivers the ID of a workflow whenever one of its nodes
+// changes. The channel is closed when the subscription ends.
+type NodeEventSource interface {
+	ListenNodeEvents(ctx context.Context) (<-chan string, error)
+}
+
+// WorkflowReconciler detects workflows whose nodes have all finished, records
+// their aggregate status and emits EventWorkflowCompleted.
+type WorkflowReconciler struct {
+	StateManager persistence.StateManager
+	EventLogger  EventLogger
+
+	// Interval is how often every active workflow is reconciled.
+	Interval time.Duration
+	// Events, if set, triggers reconciliation of a workflow as soon as one
+	// of its nodes changes.
+	Events NodeEventSource
+	// SatisfyingStatuses must match the state manager's; parents in any other
+	// final status leave their children blocked for good. Defaults to
+	// persistence.DefaultSatisfyingStatuses.
+	SatisfyingStatuses []pb.Status
+}
+
+// Run reconciles workflows until ctx is done.
+func (r *WorkflowReconciler) Run(ctx context.Context) {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultReconcileInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	events := r.subscribe(ctx)
+	r.ReconcileAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case workflowID, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if _, err := r.ReconcileWorkflow(ctx, workflowID); err != nil {
+				log.Printf("Failed to reconcile workflow %s: %v", workflowID, err)
+			}
+		case <-ticker.C:
+			if events == nil {
+				events = r.subscribe(ctx)
+			}
+			r.ReconcileAll(ctx)
+		}
+	}
+}
+
+func (r *WorkflowReconciler) subscribe(ctx context.Context) <-chan string {
+	if r.Events == nil {
+		return nil
+	}
+	events, err := r.Events.ListenNodeEvents(ctx)
+	if err != nil {
+		log.Printf("Failed to subscribe to node events: %v", err)
+		return nil
+	}
+	return events
+}
+
+// ReconcileAll reconciles every workflow that has not completed yet.
+func (r *WorkflowReconciler) ReconcileAll(ctx context.Context) {
+	ids, err := r.StateManager.ListActiveWorkflows(ctx)
+	if err != nil {
+		log.Printf("Failed to list active workflows: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if _, err := r.ReconcileWorkflow(ctx, id); err != nil {
+			log.Printf("Failed to reconcile workflow %s: %v", id, err)
+		}
+	}
+}
+
+// ReconcileWorkflow completes the workflow if all of its nodes have finished,
+// reporting whether this call completed it.
+func (r *WorkflowReconciler) ReconcileWorkflow(ctx context.Context, workflowID string) (bool, error) {
+	nodes, err := r.StateManager.ListNodes(ctx, workflowID)
+	if err != nil {
+		return false, err
+	}
+	status, done := RollupWorkflowStatus(nodes, r.satisfyingStatuses())
+	if !done {
+		return false, nil
+	}
+	completed, err := r.StateManager.CompleteWorkflow(ctx, workflowID, status)
+	if err != nil || !completed {
+		return false, err
+	}
+	log.Printf("Workflow %s completed with status %v", workflowID, status)
+
+	if r.EventLogger != nil {
+		now := time.Now()
+		payloadBytes, err := proto.Marshal(&pb.WorkflowCompletedEvent{
+			WorkflowId:  workflowID,
+			Status:      status,
+			CompletedAt: timestamppb.New(now),
+		})
+		if err == nil {
+			r.EventLogger.LogEvent(Event{
+				Type:      EventWorkflowCompleted,
+				Timestamp: now,
+				ProtoType: "WorkflowCompletedEvent",
+				Payload:   payloadBytes,
+			})
+		}
+	}
+	return true, nil
+}
+
+func (r *WorkflowReconciler) satisfyingStatuses() []pb.Status {
+	if len(r.SatisfyingStatuses) == 0 {
+		return persistence.DefaultSatisfyingStatuses
+	}
+	return r.SatisfyingStatuses
+}
+
+// RollupWorkflowStatus reports whether a workflow with the given nodes has
+// finished and, if so, its aggregate status. A workflow finishes once every
+// node has reached a final status or is blocked for good by a parent that
+// finished in a non-satisfying status. The aggregate status is FAIL if any
+// node failed, PASS if every node passed, and otherwise partial: SKIPPED if
+// any node was skipped or left blocked, else FILTERED.
+func RollupWorkflowStatus(nodes []*pb.Node, satisfying []pb.Status) (pb.Status, bool) {
+	if len(nodes) == 0 {
+		return pb.Status_UNKNOWN, false
+	}
+	byID := make(map[string]*pb.Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.NodeId] = n
+	}
+	isSatisfying := func(s pb.Status) bool {
+		for _, ok := range satisfying {
+			if s == ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// stuck memoizes whether a pending node can never run.
+	stuck := make(map[string]bool)
+	var isStuck func(n *pb.Node) bool
+	isStuck = func(n *pb.Node) bool {
+		if v, seen := stuck[n.NodeId]; seen {
+			return v
+		}
+		stuck[n.NodeId] = false // breaks cycles
+		if !isPending(n.Status) {
+			return false
+		}
+		for _, pid := range n.ParentIds {
+			parent, ok := byID[pid]
+			if !ok || (isFinal(parent.Status) && !isSatisfying(parent.Status)) || isStuck(parent) {
+				stuck[n.NodeId] = true
+				return true
+			}
+		}
+		return false
+	}
+
+	var failed, skipped, filtered bool
+	for _, n := range nodes {
+		switch {
+		case persistence.IsFailureStatus(n.Status):
+			failed = true
+		case n.Status == pb.Status_SKIPPED:
+			skipped = true
+		case n.Status == pb.Status_FILTERED:
+			filtered = true
+		case n.Status == pb.Status_PASS:
+		case isStuck(n):
+			skipped = true
+		default:
+			return pb.Status_UNKNOWN, false
+		}
+	}
+	switch {
+	case failed:
+		return pb.Status_FAIL, true
+	case skipped:
+		return pb.Status_SKIPPED, true
+	case filtered:
+		return pb.Status_FILTERED, true
+	default:
+		return pb.Status_PASS, true
+	}
+}
+
+func isPending(s pb.Status) bool {
+	return s == pb.Status_UNKNOWN || s == pb.Status_BLOCKED
+}
+
+func isFinal(s pb.Status) bool {
+	return persistence.IsFailureStatus(s) || s == pb.Status_PASS || s == pb.Status_SKIPPED || s == pb.Status_FILTERED
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// recordingEventLogger keeps every event it is given.
+type recordingEventLogger struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (l *recordingEventLogger) LogEvent(event Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func TestRollupWorkflowStatus(t *testing.T) {
+	node := func(id string, status pb.Status, parents ...string) *pb.Node {
+		return &pb.Node{NodeId: id, Status: status, ParentIds: parents}
+	}
+	pass := []pb.Status{pb.Status_PASS}
+	tests := []struct {
+		name       string
+		nodes      []*pb.Node
+		satisfying []pb.Status
+		wantStatus pb.Status
+		wantDone   bool
+	}{
+		{"empty", nil, pass, pb.Status_UNKNOWN, false},
+		{"all pass", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_PASS, "a")}, pass, pb.Status_PASS, true},
+		{"running", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_RUNNING, "a")}, pass, pb.Status_UNKNOWN, false},
+		{"ready", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_READY, "a")}, pass, pb.Status_UNKNOWN, false},
+		{"pending child of passed parent", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_BLOCKED, "a")}, pass, pb.Status_UNKNOWN, false},
+		{"failure blocks descendants", []*pb.Node{
+			node("a", pb.Status_TASK_ERROR), node("b", pb.Status_BLOCKED, "a"), node("c", pb.Status_BLOCKED, "b"),
+		}, pass, pb.Status_FAIL, true},
+		{"failure with work still running", []*pb.Node{
+			node("a", pb.Status_FAIL), node("b", pb.Status_RUNNING),
+		}, pass, pb.Status_UNKNOWN, false},
+		{"skipped", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_SKIPPED, "a")}, pass, pb.Status_SKIPPED, true},
+		{"skipped parent strands child", []*pb.Node{node("a", pb.Status_SKIPPED), node("b", pb.Status_BLOCKED, "a")}, pass, pb.Status_SKIPPED, true},
+		{"filtered", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_FILTERED, "a")}, pass, pb.Status_FILTERED, true},
+		{"filtered parent satisfies child", []*pb.Node{
+			node("a", pb.Status_FILTERED), node("b", pb.Status_BLOCKED, "a"),
+		}, []pb.Status{pb.Status_PASS, pb.Status_FILTERED}, pb.Status_UNKNOWN, false},
+		{"missing parent", []*pb.Node{node("a", pb.Status_PASS), node("b", pb.Status_BLOCKED, "gone")}, pass, pb.Status_SKIPPED, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, done := RollupWorkflowStatus(tt.nodes, tt.satisfying)
+			if status != tt.wantStatus || done != tt.wantDone {
+				t.Errorf("RollupWorkflowStatus() = %v, %v; want %v, %v", status, done, tt.wantStatus, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestReconcileWorkflowCompletesOnce(t *testing.T) {
+	completed := map[string]pb.Status{}
+	fakeSM := &fakeStateManager{
+		ListNodesFunc: func(ctx context.Context, workflowID string) ([]*pb.Node, error) {
+			if workflowID == "wf-done" {
+				return []*pb.Node{{NodeId: "a", Status: pb.Status_PASS}, {NodeId: "b", Status: pb.Status_FAIL, ParentIds: []string{"a"}}}, nil
+			}
+			return []*pb.Node{{NodeId: "c", Status: pb.Status_RUNNING}}, nil
+		},
+		ListActiveWorkflowsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"wf-done", "wf-running"}, nil
+		},
+		CompleteWorkflowFunc: func(ctx context.Context, workflowID string, status pb.Status) (bool, error) {
+			if _, ok := completed[workflowID]; ok {
+				return false, nil
+			}
+			completed[workflowID] = status
+			return true, nil
+		},
+	}
+	logger := &recordingEventLogger{}
+	r := &WorkflowReconciler{StateManager: fakeSM, EventLogger: logger}
+
+	r.ReconcileAll(context.Background())
+	r.ReconcileAll(context.Background())
+
+	if len(completed) != 1 || completed["wf-done"] != pb.Status_FAIL {
+		t.Fatalf("expected only wf-done to complete with FAIL, got %v", completed)
+	}
+	if len(logger.events) != 1 || logger.events[0].Type != EventWorkflowCompleted {
+		t.Fatalf("expected exactly one WorkflowCompleted event, got %v", logger.events)
+	}
+	var payload pb.WorkflowCompletedEvent
+	if err := proto.Unmarshal(logger.events[0].Payload, &payload); err != nil {
+		t.Fatalf("failed to unmarshal event payload: %v", err)
+	}
+	if payload.WorkflowId != "wf-done" || payload.Status != pb.Status_FAIL || payload.CompletedAt == nil {
+		t.Errorf("unexpected event payload %v", &payload)
+	}
+}
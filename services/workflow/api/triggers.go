@@ -0,0 +1,244 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/cron"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+const (
+	// DefaultTriggerInterval is how often TriggerRunner looks for due
+	// triggers when TriggerRunner.Interval is zero.
+	DefaultTriggerInterval = 30 * time.Second
+
+	// DefaultMissedRunGrace is how late a scheduled run may start before it
+	// counts as missed, when TriggerRunner.MissedRunGrace is zero.
+	DefaultMissedRunGrace = 5 * time.Minute
+
+	// DefaultMissedRunPolicy applies to triggers that set no policy.
+	DefaultMissedRunPolicy = pb.MissedRunPolicy_CATCH_UP_ONCE
+
+	// maxCatchUpRuns caps how many workflows one trigger starts at once, so
+	// that a long outage cannot flood the scheduler.
+	maxCatchUpRuns = 100
+)
+
+// EffectiveMissedRunPolicy returns policy, or DefaultMissedRunPolicy if it is
+// unset.
+func EffectiveMissedRunPolicy(policy pb.MissedRunPolicy) pb.MissedRunPolicy {
+	if policy == pb.MissedRunPolicy_MISSED_RUN_POLICY_UNSPECIFIED {
+		return DefaultMissedRunPolicy
+	}
+	return policy
+}
+
+func (s *WorkflowServiceServerImpl) CreateTrigger(ctx context.Context, req *pb.CreateTriggerRequest) (*pb.CreateTriggerResponse, error) {
+	t := req.GetTrigger()
+	if len(t.GetTemplate().GetNodes()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "trigger template must have at least one node")
+	}
+	if err := persistence.ValidateGraph(t.GetTemplate().GetNodes()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "trigger template: %v", err)
+	}
+	schedule, err := cron.Parse(t.GetSchedule())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule: %v", err)
+	}
+	next := schedule.Next(time.Now().UTC())
+	if next.IsZero() {
+		return nil, status.Errorf(codes.InvalidArgument, "schedule %q never fires", t.GetSchedule())
+	}
+
+	trigger := &persistence.Trigger{
+		Name:     t.GetName(),
+		Schedule: t.GetSchedule(),
+		Template: t.GetTemplate(),
+		// Record the default explicitly so that later default changes do
+		// not alter existing triggers.
+		MissedRunPolicy: EffectiveMissedRunPolicy(t.GetMissedRunPolicy()),
+		NextRunAt:       next,
+	}
+	triggerID, err := s.StateManager.CreateTrigger(ctx, trigger)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create trigger: %v", err)
+	}
+
+	s.logEvent(EventTriggerCreated, "CreateTriggerRequest", req)
+	return &pb.CreateTriggerResponse{TriggerId: triggerID, NextRunAt: timestamppb.New(next)}, nil
+}
+
+func (s *WorkflowServiceServerImpl) ListTriggers(ctx context.Context, req *pb.ListTriggersRequest) (*pb.ListTriggersResponse, error) {
+	triggers, err := s.StateManager.ListTriggers(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list triggers: %v", err)
+	}
+	resp := &pb.ListTriggersResponse{}
+	for _, t := range triggers {
+		pt := &pb.Trigger{
+			TriggerId:       t.ID,
+			Name:            t.Name,
+			Schedule:        t.Schedule,
+			Template:        t.Template,
+			MissedRunPolicy: t.MissedRunPolicy,
+			NextRunAt:       timestamppb.New(t.NextRunAt),
+			LastWorkflowId:  t.LastWorkflowID,
+		}
+		if !t.LastRunAt.IsZero() {
+			pt.LastRunAt = timestamppb.New(t.LastRunAt)
+		}
+		resp.Triggers = append(resp.Triggers, pt)
+	}
+	return resp, nil
+}
+
+func (s *WorkflowServiceServerImpl) DeleteTrigger(ctx context.Context, req *pb.DeleteTriggerRequest) (*pb.DeleteTriggerResponse, error) {
+	triggerID := req.GetTriggerId()
+	if triggerID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "trigger_id is required")
+	}
+	if err := s.StateManager.DeleteTrigger(ctx, triggerID); err != nil {
+		if errors.Is(err, persistence.ErrTriggerNotFound) {
+			return nil, status.Errorf(codes.NotFound, "trigger %s not found", triggerID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete trigger: %v", err)
+	}
+	s.logEvent(EventTriggerDeleted, "DeleteTriggerRequest", req)
+	return &pb.DeleteTriggerResponse{Success: true}, nil
+}
+
+// TriggerRunner starts a workflow from each trigger's template whenever its
+// schedule fires. Replicas may run concurrently: advancing a trigger is
+// conditional on its previous next run, so each run starts at most once.
+type TriggerRunner struct {
+	StateManager persistence.StateManager
+	// Server emits the usual events for the triggered workflows.
+	Server *WorkflowServiceServerImpl
+
+	// Interval is how often due triggers are looked for.
+	Interval time.Duration
+	// MissedRunGrace is how late a run may start before the trigger's
+	// MissedRunPolicy applies to it. Defaults to DefaultMissedRunGrace.
+	MissedRunGrace time.Duration
+}
+
+// Run fires due triggers until ctx is done.
+func (r *TriggerRunner) Run(ctx context.Context) {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultTriggerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	r.FireDueTriggers(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.FireDueTriggers(ctx, now)
+		}
+	}
+}
+
+// FireDueTriggers starts the workflows of every trigger due at now.
+func (r *TriggerRunner) FireDueTriggers(ctx context.Context, now time.Time) {
+	now = now.UTC()
+	triggers, err := r.StateManager.ListDueTriggers(ctx, now)
+	if err != nil {
+		log.Printf("Failed to list due triggers: %v", err)
+		return
+	}
+	for _, t := range triggers {
+		if err := r.fire(ctx, t, now); err != nil {
+			log.Printf("Failed to fire trigger %s: %v", t.ID, err)
+		}
+	}
+}
+
+func (r *TriggerRunner) fire(ctx context.Context, t *persistence.Trigger, now time.Time) error {
+	schedule, err := cron.Parse(t.Schedule)
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return fmt.Errorf("schedule %q never fires again", t.Schedule)
+	}
+	runs := r.runsDue(schedule, t, now)
+
+	reqs := make([]*pb.CreateWorkflowRequest, runs)
+	workflows := make([]*persistence.Workflow, runs)
+	for i := range workflows {
+		reqs[i] = persistence.InstantiateTemplate(t.Template, nil)
+		if workflows[i], err = newWorkflow(reqs[i]); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	// The runs' workflows are created and the trigger advanced together: if
+	// another replica fired the trigger first nothing is created, and if
+	// creating a workflow fails the trigger stays due, so that the next tick
+	// retries every run.
+	fired, err := r.StateManager.FireTrigger(ctx, t.ID, t.NextRunAt, next, workflows, now)
+	if err != nil {
+		return fmt.Errorf("failed to start workflows: %w", err)
+	}
+	if !fired {
+		return nil
+	}
+	if runs == 0 {
+		log.Printf("Trigger %s skipped runs missed since %s", t.ID, t.NextRunAt.Format(time.RFC3339))
+		return nil
+	}
+	for i, wf := range workflows {
+		log.Printf("Trigger %s started workflow %s", t.ID, wf.ID)
+		r.Server.logEvent(EventWorkflowCreated, "CreateWorkflowRequest", reqs[i])
+	}
+	return nil
+}
+
+// runsDue returns how many workflows t should start at now. Scheduled runs
+// from t.NextRunAt up to now that are within MissedRunGrace of now are on
+// time and always start; older ones were missed and start according to the
+// trigger's MissedRunPolicy.
+func (r *TriggerRunner) runsDue(schedule *cron.Schedule, t *persistence.Trigger, now time.Time) int {
+	grace := r.MissedRunGrace
+	if grace <= 0 {
+		grace = DefaultMissedRunGrace
+	}
+	cutoff := now.Add(-grace)
+
+	countFrom := func(at time.Time) int {
+		n := 0
+		for ; !at.IsZero() && !at.After(now) && n < maxCatchUpRuns; at = schedule.Next(at) {
+			n++
+		}
+		return n
+	}
+
+	missed := t.NextRunAt.Before(cutoff)
+	if !missed {
+		return countFrom(t.NextRunAt)
+	}
+	switch EffectiveMissedRunPolicy(t.MissedRunPolicy) {
+	case pb.MissedRunPolicy_CATCH_UP_ALL:
+		return countFrom(t.NextRunAt)
+	case pb.MissedRunPolicy_SKIP_MISSED:
+		return countFrom(schedule.Next(cutoff.Add(-time.Nanosecond)))
+	default:
+		// A single workflow covers the missed runs, along with any on time.
+		if onTime := countFrom(schedule.Next(cutoff.Add(-time.Nanosecond))); onTime > 0 {
+			return onTime
+		}
+		return 1
+	}
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/persistence"
+)
+
+func TestCreateTrigger(t *testing.T) {
+	logger := &recordingEventLogger{}
+	fakeSM := &fakeStateManager{}
+	server := NewWorkflowServiceServer(fakeSM, logger)
+	template := &pb.CreateWorkflowRequest{Nodes: []*pb.Node{{NodeId: "report"}}}
+
+	var stored *persistence.Trigger
+	fakeSM.CreateTriggerFunc = func(ctx context.Context, trigger *persistence.Trigger) (string, error) {
+		stored = trigger
+		return "trigger-1", nil
+	}
+	resp, err := server.CreateTrigger(context.Background(), &pb.CreateTriggerRequest{
+		Trigger: &pb.Trigger{Name: "nightly", Schedule: "@daily", Template: template},
+	})
+	if err != nil {
+		t.Fatalf("CreateTrigger failed: %v", err)
+	}
+	if resp.TriggerId != "trigger-1" || !resp.NextRunAt.AsTime().After(time.Now()) {
+		t.Errorf("unexpected response %v", resp)
+	}
+	if stored.MissedRunPolicy != DefaultMissedRunPolicy || !stored.NextRunAt.Equal(resp.NextRunAt.AsTime()) {
+		t.Errorf("expected the default policy and next run to be stored, got %+v", stored)
+	}
+	if len(logger.events) != 1 || logger.events[0].Type != EventTriggerCreated {
+		t.Errorf("expected a TriggerCreated event, got %+v", logger.events)
+	}
+
+	for name, trigger := range map[string]*pb.Trigger{
+		"bad schedule": {Schedule: "every day", Template: template},
+		"never fires":  {Schedule: "0 0 30 2 *", Template: template},
+		"no nodes":     {Schedule: "@daily", Template: &pb.CreateWorkflowRequest{}},
+	} {
+		_, err := server.CreateTrigger(context.Background(), &pb.CreateTriggerRequest{Trigger: trigger})
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument error, got %v", name, err)
+		}
+	}
+}
+
+func TestDeleteTrigger(t *testing.T) {
+	fakeSM := &fakeStateManager{}
+	server := NewWorkflowServiceServer(fakeSM, nil)
+	fakeSM.DeleteTriggerFunc = func(ctx context.Context, triggerID string) error {
+		if triggerID != "trigger-1" {
+			return persistence.ErrTriggerNotFound
+		}
+		return nil
+	}
+	if _, err := server.DeleteTrigger(context.Background(), &pb.DeleteTriggerRequest{TriggerId: "trigger-1"}); err != nil {
+		t.Errorf("DeleteTrigger failed: %v", err)
+	}
+	_, err := server.DeleteTrigger(context.Background(), &pb.DeleteTriggerRequest{TriggerId: "missing"})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+		t.Errorf("expected NotFound error, got %v", err)
+	}
+}
+
+func TestTriggerRunnerMissedRunPolicies(t *testing.T) {
+	hour := func(h, m int) time.Time { return time.Date(2025, 1, 15, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		name      string
+		policy    pb.MissedRunPolicy
+		nextRunAt time.Time
+		now       time.Time
+		wantRuns  int
+	}{
+		{name: "on time", policy: pb.MissedRunPolicy_SKIP_MISSED, nextRunAt: hour(12, 0), now: hour(12, 1), wantRuns: 1},
+		{name: "skip with a run on time", policy: pb.MissedRunPolicy_SKIP_MISSED, nextRunAt: hour(9, 0), now: hour(12, 1), wantRuns: 1},
+		{name: "skip all missed", policy: pb.MissedRunPolicy_SKIP_MISSED, nextRunAt: hour(9, 0), now: hour(12, 30), wantRuns: 0},
+		{name: "catch up once with a run on time", policy: pb.MissedRunPolicy_CATCH_UP_ONCE, nextRunAt: hour(9, 0), now: hour(12, 1), wantRuns: 1},
+		{name: "catch up once", policy: pb.MissedRunPolicy_CATCH_UP_ONCE, nextRunAt: hour(9, 0), now: hour(12, 30), wantRuns: 1},
+		{name: "default catches up once", nextRunAt: hour(9, 0), now: hour(12, 30), wantRuns: 1},
+		{name: "catch up all", policy: pb.MissedRunPolicy_CATCH_UP_ALL, nextRunAt: hour(9, 0), now: hour(12, 30), wantRuns: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &pb.CreateWorkflowRequest{Nodes: []*pb.Node{
+				{NodeId: "fetch", ChildIds: []string{"summarize"}},
+				{NodeId: "summarize", ParentIds: []string{"fetch"}},
+			}}
+			trigger := &persistence.Trigger{
+				ID: "trigger-1", Schedule: "0 * * * *", Template: template,
+				MissedRunPolicy: tt.policy, NextRunAt: tt.nextRunAt,
+			}
+
+			var created []*persistence.Workflow
+			var advancedTo time.Time
+			fakeSM := &fakeStateManager{
+				ListDueTriggersFunc: func(ctx context.Context, now time.Time) ([]*persistence.Trigger, error) {
+					return []*persistence.Trigger{trigger}, nil
+				},
+				FireTriggerFunc: func(ctx context.Context, triggerID string, from, next time.Time, workflows []*persistence.Workflow, firedAt time.Time) (bool, error) {
+					if !from.Equal(tt.nextRunAt) {
+						t.Errorf("advanced from %v, want %v", from, tt.nextRunAt)
+					}
+					advancedTo = next
+					created = workflows
+					return true, nil
+				},
+				CreateWorkflowFunc: func(ctx context.Context, wf *persistence.Workflow) (string, error) {
+					t.Error("triggered workflows must be created along with advancing the trigger")
+					return "wf", nil
+				},
+			}
+			events := &recordingEventLogger{}
+			runner := &TriggerRunner{StateManager: fakeSM, Server: NewWorkflowServiceServer(fakeSM, events)}
+			runner.FireDueTriggers(context.Background(), tt.now)
+
+			if len(created) != tt.wantRuns || len(events.events) != tt.wantRuns {
+				t.Errorf("started %d workflows and reported %d, want %d", len(created), len(events.events), tt.wantRuns)
+			}
+			if want := tt.now.Truncate(time.Hour).Add(time.Hour); !advancedTo.Equal(want) {
+				t.Errorf("advanced to %v, want %v", advancedTo, want)
+			}
+
+			// Every run gets its own node IDs, with edges following them.
+			seen := map[string]bool{}
+			for _, wf := range created {
+				fetch, summarize := wf.Nodes[0], wf.Nodes[1]
+				if seen[fetch.NodeId] || fetch.NodeId == "fetch" {
+					t.Errorf("node ID %s was not replaced with a fresh one", fetch.NodeId)
+				}
+				seen[fetch.NodeId] = true
+				if fetch.ChildIds[0] != summarize.NodeId || summarize.ParentIds[0] != fetch.NodeId {
+					t.Errorf("edges were not remapped: %v -> %v", fetch, summarize)
+				}
+			}
+			if template.Nodes[0].NodeId != "fetch" {
+				t.Errorf("the stored template was modified")
+			}
+		})
+	}
+}
+
+func TestTriggerRunnerSkipsTriggersAdvancedElsewhere(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fire func(ctx context.Context, triggerID string, from, next time.Time, workflows []*persistence.Workflow, firedAt time.Time) (bool, error)
+	}{
+		{name: "fired by another replica", fire: func(context.Context, string, time.Time, time.Time, []*persistence.Workflow, time.Time) (bool, error) {
+			return false, nil
+		}},
+		// The trigger stays due, so the next tick retries the run.
+		{name: "failed to create the workflow", fire: func(context.Context, string, time.Time, time.Time, []*persistence.Workflow, time.Time) (bool, error) {
+			return false, errors.New("db down")
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSM := &fakeStateManager{
+				ListDueTriggersFunc: func(ctx context.Context, now time.Time) ([]*persistence.Trigger, error) {
+					return []*persistence.Trigger{{
+						ID: "trigger-1", Schedule: "@hourly", NextRunAt: now.Add(-time.Minute),
+						Template: &pb.CreateWorkflowRequest{Nodes: []*pb.Node{{NodeId: "a"}}},
+					}}, nil
+				},
+				FireTriggerFunc: tt.fire,
+			}
+			events := &recordingEventLogger{}
+			runner := &TriggerRunner{StateManager: fakeSM, Server: NewWorkflowServiceServer(fakeSM, events)}
+			runner.FireDueTriggers(context.Background(), time.Now())
+			if len(events.events) != 0 {
+				t.Errorf("expected no workflow to be reported started, got %v", events.events)
+			}
+		})
+	}
+}
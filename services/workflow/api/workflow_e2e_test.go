@@ -46,12 +46,14 @@ func TestWorkflowLifecycle_E2E(t *testing.T) {
 				NodeId:      aID,
 				Description: "Node A",
 				ParentIds:   nil,
+				ChildIds:    []string{bID},
 				Status:      pb.Status_BLOCKED,
 			},
 			{
 				NodeId:      bID,
 				Description: "Node B",
 				ParentIds:   []string{aID},
+				ChildIds:    []string{cID},
 				Status:      pb.Status_BLOCKED,
 			},
 			{
@@ -30,44 +30,18 @@ func NewWorkflowServiceServer(sm persistence.StateManager, logger EventLogger) *
 }
 
 func (s *WorkflowServiceServerImpl) CreateWorkflow(ctx context.Context, req *pb.CreateWorkflowRequest) (*pb.CreateWorkflowResponse, error) {
-	// Generate a new UUID for the workflow
-	workflowID := uuid.New().String()
-
-	// Prepare workflow struct
-	workflow := &persistence.Workflow{
-		ID:    workflowID,
-		Nodes: req.GetNodes(),
-		// Optionally set Name, Description, Status if available in request
-	}
-
-	// Persist workflow metadata
-	returnedID, err := s.StateManager.CreateWorkflow(ctx, workflow)
+	workflow, err := newWorkflow(req)
 	if err != nil {
 		return nil, err
 	}
-	workflowID = returnedID
 
-	// Persist initial nodes
-	for _, node := range req.GetNodes() {
-		err := s.StateManager.CreateNode(ctx, workflowID, node)
-		if err != nil {
-			return nil, err
-		}
+	// Persist the workflow along with its initial nodes
+	workflowID, err := s.StateManager.CreateWorkflow(ctx, workflow)
+	if err != nil {
+		return nil, err
 	}
 
-	// Emit WorkflowCreated event
-	if s.EventLogger != nil {
-		payloadBytes, err := proto.Marshal(req)
-		if err == nil {
-			event := Event{
-				Type:      EventWorkflowCreated,
-				Timestamp: time.Now(),
-				ProtoType: "CreateWorkflowRequest",
-				Payload:   payloadBytes,
-			}
-			s.EventLogger.LogEvent(event)
-		}
-	}
+	s.logEvent(EventWorkflowCreated, "CreateWorkflowRequest", req)
 
 	// Return response with workflow ID
 	return &pb.CreateWorkflowResponse{
@@ -75,6 +49,29 @@ func (s *WorkflowServiceServerImpl) CreateWorkflow(ctx context.Context, req *pb.
 	}, nil
 }
 
+// newWorkflow validates req and returns the workflow it creates, with edges
+// listed on both of their nodes.
+func newWorkflow(req *pb.CreateWorkflowRequest) (*persistence.Workflow, error) {
+	if err := persistence.ValidateGraph(req.GetNodes()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	persistence.DeriveEdges(req.GetNodes())
+
+	owner := req.GetOwner()
+	if owner == "" {
+		owner = req.GetCaller().GetAgent()
+	}
+	return &persistence.Workflow{
+		Priority: req.GetPriority(),
+		Owner:    owner,
+		// Record the default explicitly so that later default changes do
+		// not alter how existing workflows react to failures.
+		FailurePolicy: persistence.EffectiveFailurePolicy(nil, req.GetFailurePolicy()),
+		Nodes:         req.GetNodes(),
+		// Optionally set Name, Description, Status if available in request
+	}, nil
+}
+
 func (s *WorkflowServiceServerImpl) GetWorkflow(ctx context.Context, req *pb.GetWorkflowRequest) (*pb.GetWorkflowResponse, error) {
 	workflowID := req.GetWorkflowId()
 	if workflowID == "" {
@@ -96,7 +93,17 @@ func (s *WorkflowServiceServerImpl) GetWorkflow(ctx context.Context, req *pb.Get
 	}
 
 	resp := &pb.GetWorkflowResponse{
-		Nodes: workflow.Nodes,
+		Nodes:         workflow.Nodes,
+		FailurePolicy: persistence.EffectiveFailurePolicy(nil, workflow.FailurePolicy),
+		Paused:        !workflow.PausedAt.IsZero(),
+
+		ParentWorkflowId: workflow.ParentWorkflowID,
+		ParentNodeId:     workflow.ParentNodeID,
+		ChildWorkflowIds: workflow.ChildWorkflowIDs,
+	}
+	if !workflow.CompletedAt.IsZero() {
+		resp.Status = workflow.Status
+		resp.CompletedAt = timestamppb.New(workflow.CompletedAt)
 	}
 
 	return resp, nil
@@ -138,6 +145,12 @@ func (s *WorkflowServiceServerImpl) UpdateWorkflow(ctx context.Context, req *pb.
 		}
 		incomingNodes[n.GetNodeId()] = n
 	}
+	if err := persistence.ValidateGraph(req.GetNodes()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// Each update replaces the edges of its node, so both sides of every
+	// edge must be listed.
+	persistence.DeriveEdges(req.GetNodes())
 
 	var edits []*pb.NodeEdit
 	now := time.Now()
@@ -180,7 +193,9 @@ func (s *WorkflowServiceServerImpl) UpdateWorkflow(ctx context.Context, req *pb.
 
 	// Apply edits transactionally
 	if len(edits) > 0 {
-		if err := s.StateManager.ApplyNodeEdits(ctx, workflowID, edits); err != nil {
+		if err := s.StateManager.ApplyNodeEdits(ctx, workflowID, edits); errors.Is(err, persistence.ErrInvalidGraph) {
+			return &pb.UpdateWorkflowResponse{Success: false}, status.Error(codes.InvalidArgument, err.Error())
+		} else if err != nil {
 			return &pb.UpdateWorkflowResponse{Success: false}, status.Errorf(codes.Internal, "failed to apply node edits: %v", err)
 		}
 	}
@@ -255,3 +270,90 @@ func (s *WorkflowServiceServerImpl) UpdateNode(ctx context.Context, req *pb.Upda
 	}
 	return &pb.UpdateNodeResponse{Success: true}, nil
 }
+
+func (s *WorkflowServiceServerImpl) CancelWorkflow(ctx context.Context, req *pb.CancelWorkflowRequest) (*pb.CancelWorkflowResponse, error) {
+	workflowID := req.GetWorkflowId()
+	if workflowID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "workflow_id is required")
+	}
+
+	canceled, err := s.StateManager.CancelWorkflow(ctx, workflowID, req.GetReason())
+	if err != nil {
+		if errors.Is(err, persistence.ErrWorkflowNotFound) {
+			return nil, status.Errorf(codes.NotFound, "workflow %s not found", workflowID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to cancel workflow: %v", err)
+	}
+
+	s.logEvent(EventWorkflowCanceled, "CancelWorkflowRequest", req)
+	return &pb.CancelWorkflowResponse{CanceledNodeIds: canceled}, nil
+}
+
+func (s *WorkflowServiceServerImpl) CancelNode(ctx context.Context, req *pb.CancelNodeRequest) (*pb.CancelNodeResponse, error) {
+	workflowID := req.GetWorkflowId()
+	nodeID := req.GetNodeId()
+	if workflowID == "" || nodeID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "workflow_id and node_id are required")
+	}
+
+	canceled, err := s.StateManager.CancelNode(ctx, workflowID, nodeID, req.GetReason())
+	if err != nil {
+		if errors.Is(err, persistence.ErrNodeNotFound) {
+			return nil, status.Errorf(codes.NotFound, "node %s not found in workflow %s", nodeID, workflowID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to cancel node: %v", err)
+	}
+
+	if len(canceled) > 0 {
+		s.logEvent(EventNodeCanceled, "CancelNodeRequest", req)
+	}
+	return &pb.CancelNodeResponse{CanceledNodeIds: canceled}, nil
+}
+
+func (s *WorkflowServiceServerImpl) PauseWorkflow(ctx context.Context, req *pb.PauseWorkflowRequest) (*pb.PauseWorkflowResponse, error) {
+	if err := s.setWorkflowPaused(ctx, req.GetWorkflowId(), true); err != nil {
+		return nil, err
+	}
+	s.logEvent(EventWorkflowPaused, "PauseWorkflowRequest", req)
+	return &pb.PauseWorkflowResponse{Success: true}, nil
+}
+
+func (s *WorkflowServiceServerImpl) ResumeWorkflow(ctx context.Context, req *pb.ResumeWorkflowRequest) (*pb.ResumeWorkflowResponse, error) {
+	if err := s.setWorkflowPaused(ctx, req.GetWorkflowId(), false); err != nil {
+		return nil, err
+	}
+	s.logEvent(EventWorkflowResumed, "ResumeWorkflowRequest", req)
+	return &pb.ResumeWorkflowResponse{Success: true}, nil
+}
+
+// setWorkflowPaused pauses or resumes a workflow, mapping failures to gRPC
+// status errors.
+func (s *WorkflowServiceServerImpl) setWorkflowPaused(ctx context.Context, workflowID string, paused bool) error {
+	if workflowID == "" {
+		return status.Errorf(codes.InvalidArgument, "workflow_id is required")
+	}
+	if err := s.StateManager.SetWorkflowPaused(ctx, workflowID, paused); err != nil {
+		if errors.Is(err, persistence.ErrWorkflowNotFound) {
+			return status.Errorf(codes.NotFound, "workflow %s not found", workflowID)
+		}
+		return status.Errorf(codes.Internal, "failed to update workflow %s: %v", workflowID, err)
+	}
+	return nil
+}
+
+// logEvent records an event whose payload is the request that caused it.
+func (s *WorkflowServiceServerImpl) logEvent(eventType EventType, protoType string, req proto.Message) {
+	if s.EventLogger == nil {
+		return
+	}
+	payloadBytes, err := proto.Marshal(req)
+	if err != nil {
+		return
+	}
+	s.EventLogger.LogEvent(Event{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		ProtoType: protoType,
+		Payload:   payloadBytes,
+	})
+}
@@ -3,8 +3,12 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -32,6 +36,22 @@ type fakeStateManager struct {
 	ApplyNodeEditsFunc func(ctx context.Context, workflowID string, edits []*pb.NodeEdit) error
 	GetNodeFunc        func(ctx context.Context, workflowID, nodeID string) (*pb.Node, error)
 	UpdateNodeFunc     func(ctx context.Context, workflowID string, node *pb.Node) error
+
+	ListActiveWorkflowsFunc func(ctx context.Context) ([]string, error)
+	CompleteWorkflowFunc    func(ctx context.Context, workflowID string, status pb.Status) (bool, error)
+	ListNodesFunc           func(ctx context.Context, workflowID string) ([]*pb.Node, error)
+	CancelWorkflowFunc      func(ctx context.Context, workflowID, reason string) ([]string, error)
+	CancelNodeFunc          func(ctx context.Context, workflowID, nodeID, reason string) ([]string, error)
+	SetWorkflowPausedFunc   func(ctx context.Context, workflowID string, paused bool) error
+
+	ListPendingApprovalsFunc func(ctx context.Context, workflowID string) ([]*persistence.PendingApproval, error)
+	DecideApprovalFunc       func(ctx context.Context, workflowID, nodeID string, approval *pb.Approval) (*pb.Node, error)
+
+	CreateTriggerFunc   func(ctx context.Context, trigger *persistence.Trigger) (string, error)
+	ListTriggersFunc    func(ctx context.Context) ([]*persistence.Trigger, error)
+	DeleteTriggerFunc   func(ctx context.Context, triggerID string) error
+	ListDueTriggersFunc func(ctx context.Context, now time.Time) ([]*persistence.Trigger, error)
+	FireTriggerFunc     func(ctx context.Context, triggerID string, from, next time.Time, workflows []*persistence.Workflow, firedAt time.Time) (bool, error)
 }
 
 func (m *fakeStateManager) CreateWorkflow(ctx context.Context, workflow *persistence.Workflow) (string, error) {
@@ -57,6 +77,9 @@ func (m *fakeStateManager) ApplyNodeEdits(ctx context.Context, workflowID string
 	}
 	return nil
 }
+func (m *fakeStateManager) ApplyAgentEdits(ctx context.Context, workflowID, nodeID string, edits []*pb.NodeEdit) ([]*pb.RejectedEdit, error) {
+	return nil, nil
+}
 
 func (m *fakeStateManager) UpdateNode(ctx context.Context, workflowID string, node *pb.Node) error {
 	if m.UpdateNodeFunc != nil {
@@ -65,6 +88,10 @@ func (m *fakeStateManager) UpdateNode(ctx context.Context, workflowID string, no
 	return nil
 }
 
+func (m *fakeStateManager) UpdateLeasedNode(ctx context.Context, workflowID, schedulerID string, node *pb.Node) error {
+	return m.UpdateNode(ctx, workflowID, node)
+}
+
 func (m *fakeStateManager) GetNode(ctx context.Context, workflowID, nodeID string) (*pb.Node, error) {
 	return m.GetNodeFunc(ctx, workflowID, nodeID)
 }
@@ -74,10 +101,112 @@ func (m *fakeStateManager) ListWorkflows(ctx context.Context) ([]string, error)
 	}
 	return nil, nil
 }
+func (m *fakeStateManager) ListActiveWorkflows(ctx context.Context) ([]string, error) {
+	if m.ListActiveWorkflowsFunc != nil {
+		return m.ListActiveWorkflowsFunc(ctx)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) CompleteWorkflow(ctx context.Context, workflowID string, status pb.Status) (bool, error) {
+	if m.CompleteWorkflowFunc != nil {
+		return m.CompleteWorkflowFunc(ctx, workflowID, status)
+	}
+	return true, nil
+}
+func (m *fakeStateManager) StartSubworkflow(ctx context.Context, workflowID, schedulerID string, node *pb.Node) (string, error) {
+	return "", nil
+}
+func (m *fakeStateManager) ListNodes(ctx context.Context, workflowID string) ([]*pb.Node, error) {
+	if m.ListNodesFunc != nil {
+		return m.ListNodesFunc(ctx, workflowID)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) CancelWorkflow(ctx context.Context, workflowID, reason string) ([]string, error) {
+	if m.CancelWorkflowFunc != nil {
+		return m.CancelWorkflowFunc(ctx, workflowID, reason)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) CancelNode(ctx context.Context, workflowID, nodeID, reason string) ([]string, error) {
+	if m.CancelNodeFunc != nil {
+		return m.CancelNodeFunc(ctx, workflowID, nodeID, reason)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) SetWorkflowPaused(ctx context.Context, workflowID string, paused bool) error {
+	if m.SetWorkflowPausedFunc != nil {
+		return m.SetWorkflowPausedFunc(ctx, workflowID, paused)
+	}
+	return nil
+}
+func (m *fakeStateManager) ListPendingApprovals(ctx context.Context, workflowID string) ([]*persistence.PendingApproval, error) {
+	if m.ListPendingApprovalsFunc != nil {
+		return m.ListPendingApprovalsFunc(ctx, workflowID)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) DecideApproval(ctx context.Context, workflowID, nodeID string, approval *pb.Approval) (*pb.Node, error) {
+	if m.DecideApprovalFunc != nil {
+		return m.DecideApprovalFunc(ctx, workflowID, nodeID, approval)
+	}
+	return &pb.Node{NodeId: nodeID}, nil
+}
+func (m *fakeStateManager) CreateTrigger(ctx context.Context, trigger *persistence.Trigger) (string, error) {
+	if m.CreateTriggerFunc != nil {
+		return m.CreateTriggerFunc(ctx, trigger)
+	}
+	return "fake-trigger-id", nil
+}
+func (m *fakeStateManager) ListTriggers(ctx context.Context) ([]*persistence.Trigger, error) {
+	if m.ListTriggersFunc != nil {
+		return m.ListTriggersFunc(ctx)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) DeleteTrigger(ctx context.Context, triggerID string) error {
+	if m.DeleteTriggerFunc != nil {
+		return m.DeleteTriggerFunc(ctx, triggerID)
+	}
+	return nil
+}
+func (m *fakeStateManager) ListDueTriggers(ctx context.Context, now time.Time) ([]*persistence.Trigger, error) {
+	if m.ListDueTriggersFunc != nil {
+		return m.ListDueTriggersFunc(ctx, now)
+	}
+	return nil, nil
+}
+func (m *fakeStateManager) FireTrigger(ctx context.Context, triggerID string, from, next time.Time, workflows []*persistence.Workflow, firedAt time.Time) (bool, error) {
+	if m.FireTriggerFunc != nil {
+		return m.FireTriggerFunc(ctx, triggerID, from, next, workflows, firedAt)
+	}
+	return true, nil
+}
 func (m *fakeStateManager) Close() error {
 	return nil
 }
-func (m *fakeStateManager) FindReadyNodes(ctx context.Context) ([]*pb.Node, error) {
+func (m *fakeStateManager) FindReadyNodes(ctx context.Context, limit int) ([]*persistence.ReadyNode, error) {
+	return nil, nil
+}
+func (m *fakeStateManager) ClaimReadyNodes(ctx context.Context, limit int, schedulerID string) ([]*persistence.ReadyNode, error) {
+	return nil, nil
+}
+func (m *fakeStateManager) GetNodes(ctx context.Context, workflowID string, nodeIDs []string) ([]*pb.Node, error) {
+	return nil, nil
+}
+func (m *fakeStateManager) ClaimNodes(ctx context.Context, schedulerID string, nodes []*persistence.ReadyNode) ([]*persistence.ReadyNode, error) {
+	return nil, nil
+}
+func (m *fakeStateManager) RenewLease(ctx context.Context, workflowID, nodeID, schedulerID string) (time.Time, error) {
+	return time.Time{}, nil
+}
+func (m *fakeStateManager) RecoverExpiredLeases(ctx context.Context, status pb.Status, reason string, limit int) ([]*persistence.ReadyNode, error) {
+	return nil, nil
+}
+func (m *fakeStateManager) ReleaseClaims(ctx context.Context, schedulerID string, nodes []*persistence.ReadyNode) (int, error) {
+	return 0, nil
+}
+func (m *fakeStateManager) PausedWorkflows(ctx context.Context, workflowIDs []string) ([]string, error) {
 	return nil, nil
 }
 
@@ -99,6 +228,182 @@ func TestCreateWorkflow_Success(t *testing.T) {
 	}
 }
 
+func TestCreateWorkflow_PriorityAndOwner(t *testing.T) {
+	var got *persistence.Workflow
+	fakeSM := &fakeStateManager{
+		CreateWorkflowFunc: func(ctx context.Context, workflow *persistence.Workflow) (string, error) {
+			got = workflow
+			return "generated-id", nil
+		},
+	}
+	server := NewWorkflowServiceServer(fakeSM, &StdoutEventLogger{})
+
+	_, err := server.CreateWorkflow(authenticatedContext(), &pb.CreateWorkflowRequest{
+		Priority: 5,
+		Caller:   &pb.Caller{Agent: "planner"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Priority != 5 || got.Owner != "planner" {
+		t.Errorf("expected priority 5 owned by the caller, got priority %d owner %q", got.Priority, got.Owner)
+	}
+}
+
+func TestCreateWorkflow_FailurePolicy(t *testing.T) {
+	var got *persistence.Workflow
+	fakeSM := &fakeStateManager{
+		CreateWorkflowFunc: func(ctx context.Context, workflow *persistence.Workflow) (string, error) {
+			got = workflow
+			return "generated-id", nil
+		},
+	}
+	server := NewWorkflowServiceServer(fakeSM, &StdoutEventLogger{})
+
+	if _, err := server.CreateWorkflow(authenticatedContext(), &pb.CreateWorkflowRequest{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.FailurePolicy != persistence.DefaultFailurePolicy {
+		t.Errorf("expected the default failure policy to be recorded, got %v", got.FailurePolicy)
+	}
+
+	req := &pb.CreateWorkflowRequest{FailurePolicy: pb.FailurePolicy_FAIL_FAST}
+	if _, err := server.CreateWorkflow(authenticatedContext(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.FailurePolicy != pb.FailurePolicy_FAIL_FAST {
+		t.Errorf("expected FAIL_FAST, got %v", got.FailurePolicy)
+	}
+
+	fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
+		return got, nil
+	}
+	resp, err := server.GetWorkflow(context.Background(), &pb.GetWorkflowRequest{WorkflowId: "generated-id"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.FailurePolicy != pb.FailurePolicy_FAIL_FAST {
+		t.Errorf("expected GetWorkflow to report FAIL_FAST, got %v", resp.FailurePolicy)
+	}
+}
+
+func TestCreateWorkflow_InvalidGraph(t *testing.T) {
+	fakeSM := &fakeStateManager{
+		CreateWorkflowFunc: func(ctx context.Context, workflow *persistence.Workflow) (string, error) {
+			t.Errorf("expected an invalid graph not to be stored")
+			return "generated-id", nil
+		},
+	}
+	server := NewWorkflowServiceServer(fakeSM, &StdoutEventLogger{})
+
+	tests := []struct {
+		name  string
+		nodes []*pb.Node
+		want  string
+	}{
+		{
+			name:  "missing ID",
+			nodes: []*pb.Node{{NodeId: "a"}, {}},
+			want:  "node 1 has no node_id",
+		},
+		{
+			name:  "duplicate ID",
+			nodes: []*pb.Node{{NodeId: "a"}, {NodeId: "a"}},
+			want:  `duplicate node_id "a"`,
+		},
+		{
+			name:  "dangling parent",
+			nodes: []*pb.Node{{NodeId: "a", ParentIds: []string{"ghost"}}},
+			want:  `node "a" lists parent "ghost", which does not exist`,
+		},
+		{
+			name:  "dangling child",
+			nodes: []*pb.Node{{NodeId: "a", ChildIds: []string{"ghost"}}},
+			want:  `node "a" lists child "ghost", which does not exist`,
+		},
+		{
+			name: "cycle",
+			nodes: []*pb.Node{
+				{NodeId: "root", ChildIds: []string{"a"}},
+				{NodeId: "a", ParentIds: []string{"root", "c"}, ChildIds: []string{"b"}},
+				{NodeId: "b", ParentIds: []string{"a"}, ChildIds: []string{"c"}},
+				{NodeId: "c", ParentIds: []string{"b"}, ChildIds: []string{"a"}},
+			},
+			want: "cycle a -> b -> c -> a",
+		},
+		{
+			name:  "self loop",
+			nodes: []*pb.Node{{NodeId: "a", ParentIds: []string{"a"}, ChildIds: []string{"a"}}},
+			want:  "cycle a -> a",
+		},
+		{
+			name: "invalid subworkflow template",
+			nodes: []*pb.Node{{NodeId: "sub", Type: pb.NodeType_SUBWORKFLOW, SubworkflowOptions: &pb.SubworkflowOptions{
+				Template: &pb.CreateWorkflowRequest{Nodes: []*pb.Node{{NodeId: "x", ParentIds: []string{"y"}}}},
+			}}},
+			want: `subworkflow template of node "sub": node "x" lists parent "y", which does not exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.CreateWorkflow(authenticatedContext(), &pb.CreateWorkflowRequest{Nodes: tt.nodes})
+			st, ok := status.FromError(err)
+			if !ok || st.Code() != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument error, got %v", err)
+			}
+			if !strings.Contains(st.Message(), tt.want) {
+				t.Errorf("expected the error to explain %q, got %q", tt.want, st.Message())
+			}
+		})
+	}
+
+	diamond := []*pb.Node{
+		{NodeId: "a", ChildIds: []string{"b", "c"}},
+		{NodeId: "b", ParentIds: []string{"a"}, ChildIds: []string{"d"}},
+		{NodeId: "c", ParentIds: []string{"a"}, ChildIds: []string{"d"}},
+		{NodeId: "d", ParentIds: []string{"b", "c"}},
+	}
+	if err := persistence.ValidateGraph(diamond); err != nil {
+		t.Errorf("expected a diamond to be valid, got %v", err)
+	}
+}
+
+func TestCreateWorkflow_DerivesEdges(t *testing.T) {
+	created := map[string]*pb.Node{}
+	fakeSM := &fakeStateManager{}
+	fakeSM.CreateWorkflowFunc = func(ctx context.Context, workflow *persistence.Workflow) (string, error) {
+		for _, n := range workflow.Nodes {
+			created[n.NodeId] = n
+		}
+		return "generated-id", nil
+	}
+	server := NewWorkflowServiceServer(fakeSM, &StdoutEventLogger{})
+
+	// Each edge is listed on one side only, or on both.
+	nodes := []*pb.Node{
+		{NodeId: "a", ChildIds: []string{"b"}},
+		{NodeId: "b", ParentIds: []string{"a"}},
+		{NodeId: "c", ParentIds: []string{"a", "b"}, ChildIds: []string{"d"}},
+		{NodeId: "d"},
+	}
+	if _, err := server.CreateWorkflow(authenticatedContext(), &pb.CreateWorkflowRequest{Nodes: nodes}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := map[string][2][]string{
+		"a": {nil, {"b", "c"}},
+		"b": {{"a"}, {"c"}},
+		"c": {{"a", "b"}, {"d"}},
+		"d": {{"c"}, nil},
+	}
+	for id, edges := range want {
+		n := created[id]
+		if !reflect.DeepEqual(n.GetParentIds(), edges[0]) || !reflect.DeepEqual(n.GetChildIds(), edges[1]) {
+			t.Errorf("node %s: got parents %v and children %v, want %v and %v",
+				id, n.GetParentIds(), n.GetChildIds(), edges[0], edges[1])
+		}
+	}
+}
+
 func TestCreateWorkflow_Error(t *testing.T) {
 	fakeSM := &fakeStateManager{
 		CreateWorkflowFunc: func(ctx context.Context, workflow *persistence.Workflow) (string, error) {
@@ -137,6 +442,26 @@ func TestGetWorkflow(t *testing.T) {
 		}
 	})
 
+	t.Run("subworkflow linkage", func(t *testing.T) {
+		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
+			return &persistence.Workflow{
+				ID:               workflowID,
+				ParentWorkflowID: "wf-parent",
+				ParentNodeID:     "node-sub",
+				ChildWorkflowIDs: []string{"wf-child"},
+			}, nil
+		}
+
+		resp, err := server.GetWorkflow(context.Background(), &pb.GetWorkflowRequest{WorkflowId: "wf-123"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.ParentWorkflowId != "wf-parent" || resp.ParentNodeId != "node-sub" ||
+			len(resp.ChildWorkflowIds) != 1 || resp.ChildWorkflowIds[0] != "wf-child" {
+			t.Errorf("unexpected linkage in %v", resp)
+		}
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
 			return nil, persistence.ErrWorkflowNotFound
@@ -234,6 +559,39 @@ func TestUpdateWorkflow(t *testing.T) {
 		}
 	})
 
+	t.Run("edges listed on one side", func(t *testing.T) {
+		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
+			return &persistence.Workflow{
+				ID: workflowID,
+				Nodes: []*pb.Node{
+					{NodeId: "node1", ChildIds: []string{"node2"}},
+					{NodeId: "node2", ParentIds: []string{"node1"}},
+				},
+			}, nil
+		}
+		var edits []*pb.NodeEdit
+		fakeSM.ApplyNodeEditsFunc = func(ctx context.Context, workflowID string, e []*pb.NodeEdit) error {
+			edits = e
+			return nil
+		}
+
+		// The stored edge is kept, rather than dropped by an update of
+		// node1 that does not list it.
+		req := &pb.UpdateWorkflowRequest{
+			WorkflowId: "wf-123",
+			Nodes: []*pb.Node{
+				{NodeId: "node1", Description: "Updated Node"},
+				{NodeId: "node2", ParentIds: []string{"node1"}},
+			},
+		}
+		if _, err := server.UpdateWorkflow(context.Background(), req); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(edits) != 1 || edits[0].Node.NodeId != "node1" || !reflect.DeepEqual(edits[0].Node.ChildIds, []string{"node2"}) {
+			t.Errorf("expected a single update of node1 that keeps its child, got %v", edits)
+		}
+	})
+
 	t.Run("workflow not found", func(t *testing.T) {
 		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
 			return nil, errors.New("not found")
@@ -253,6 +611,40 @@ func TestUpdateWorkflow(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid graph", func(t *testing.T) {
+		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
+			return &persistence.Workflow{ID: workflowID}, nil
+		}
+		fakeSM.ApplyNodeEditsFunc = func(ctx context.Context, workflowID string, edits []*pb.NodeEdit) error {
+			t.Errorf("expected an invalid graph not to be applied")
+			return nil
+		}
+
+		req := &pb.UpdateWorkflowRequest{
+			WorkflowId: "wf-123",
+			Nodes:      []*pb.Node{{NodeId: "node1", ParentIds: []string{"missing"}}},
+		}
+		_, err := server.UpdateWorkflow(context.Background(), req)
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument error, got %v", err)
+		}
+	})
+
+	t.Run("edits rejected by the state manager", func(t *testing.T) {
+		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
+			return &persistence.Workflow{ID: workflowID}, nil
+		}
+		fakeSM.ApplyNodeEditsFunc = func(ctx context.Context, workflowID string, edits []*pb.NodeEdit) error {
+			return fmt.Errorf("%w: cycle a -> b -> a", persistence.ErrInvalidGraph)
+		}
+
+		req := &pb.UpdateWorkflowRequest{WorkflowId: "wf-123", Nodes: []*pb.Node{{NodeId: "node1"}}}
+		_, err := server.UpdateWorkflow(context.Background(), req)
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument error, got %v", err)
+		}
+	})
+
 	t.Run("apply edits error", func(t *testing.T) {
 		fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
 			return &persistence.Workflow{
@@ -510,3 +902,138 @@ func TestUpdateNode_EmitsCorrectEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestCancelWorkflow(t *testing.T) {
+	logger := &recordingEventLogger{}
+	fakeSM := &fakeStateManager{}
+	server := NewWorkflowServiceServer(fakeSM, logger)
+
+	t.Run("success", func(t *testing.T) {
+		fakeSM.CancelWorkflowFunc = func(ctx context.Context, workflowID, reason string) ([]string, error) {
+			if workflowID != "wf-1" || reason != "prompt is wrong" {
+				t.Errorf("unexpected CancelWorkflow(%q, %q)", workflowID, reason)
+			}
+			return []string{"a", "b"}, nil
+		}
+		resp, err := server.CancelWorkflow(context.Background(), &pb.CancelWorkflowRequest{WorkflowId: "wf-1", Reason: "prompt is wrong"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(resp.CanceledNodeIds) != 2 {
+			t.Errorf("expected 2 canceled nodes, got %v", resp.CanceledNodeIds)
+		}
+		if len(logger.events) != 1 || logger.events[0].Type != EventWorkflowCanceled {
+			t.Errorf("expected a WorkflowCanceled event, got %+v", logger.events)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fakeSM.CancelWorkflowFunc = func(ctx context.Context, workflowID, reason string) ([]string, error) {
+			return nil, persistence.ErrWorkflowNotFound
+		}
+		_, err := server.CancelWorkflow(context.Background(), &pb.CancelWorkflowRequest{WorkflowId: "missing"})
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+			t.Fatalf("expected NotFound error, got %v", err)
+		}
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		_, err := server.CancelWorkflow(context.Background(), &pb.CancelWorkflowRequest{})
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument error, got %v", err)
+		}
+	})
+}
+
+func TestCancelNode(t *testing.T) {
+	logger := &recordingEventLogger{}
+	fakeSM := &fakeStateManager{}
+	server := NewWorkflowServiceServer(fakeSM, logger)
+
+	t.Run("already finished", func(t *testing.T) {
+		fakeSM.CancelNodeFunc = func(ctx context.Context, workflowID, nodeID, reason string) ([]string, error) {
+			return nil, nil
+		}
+		resp, err := server.CancelNode(context.Background(), &pb.CancelNodeRequest{WorkflowId: "wf-1", NodeId: "done"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(resp.CanceledNodeIds) != 0 || len(logger.events) != 0 {
+			t.Errorf("expected nothing to be canceled, got %v and events %+v", resp.CanceledNodeIds, logger.events)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		fakeSM.CancelNodeFunc = func(ctx context.Context, workflowID, nodeID, reason string) ([]string, error) {
+			return []string{nodeID, "child"}, nil
+		}
+		resp, err := server.CancelNode(context.Background(), &pb.CancelNodeRequest{WorkflowId: "wf-1", NodeId: "running"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(resp.CanceledNodeIds) != 2 || resp.CanceledNodeIds[0] != "running" {
+			t.Errorf("expected the node and its child to be canceled, got %v", resp.CanceledNodeIds)
+		}
+		if len(logger.events) != 1 || logger.events[0].Type != EventNodeCanceled {
+			t.Errorf("expected a NodeCanceled event, got %+v", logger.events)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fakeSM.CancelNodeFunc = func(ctx context.Context, workflowID, nodeID, reason string) ([]string, error) {
+			return nil, persistence.ErrNodeNotFound
+		}
+		_, err := server.CancelNode(context.Background(), &pb.CancelNodeRequest{WorkflowId: "wf-1", NodeId: "missing"})
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+			t.Fatalf("expected NotFound error, got %v", err)
+		}
+	})
+}
+
+func TestPauseAndResumeWorkflow(t *testing.T) {
+	logger := &recordingEventLogger{}
+	fakeSM := &fakeStateManager{}
+	server := NewWorkflowServiceServer(fakeSM, logger)
+
+	paused := map[string]bool{}
+	fakeSM.SetWorkflowPausedFunc = func(ctx context.Context, workflowID string, p bool) error {
+		if workflowID == "missing" {
+			return persistence.ErrWorkflowNotFound
+		}
+		paused[workflowID] = p
+		return nil
+	}
+	fakeSM.GetWorkflowFunc = func(ctx context.Context, workflowID string) (*persistence.Workflow, error) {
+		wf := &persistence.Workflow{ID: workflowID}
+		if paused[workflowID] {
+			wf.PausedAt = time.Now()
+		}
+		return wf, nil
+	}
+	ctx := context.Background()
+
+	if _, err := server.PauseWorkflow(ctx, &pb.PauseWorkflowRequest{WorkflowId: "wf-1"}); err != nil {
+		t.Fatalf("PauseWorkflow failed: %v", err)
+	}
+	if resp, err := server.GetWorkflow(ctx, &pb.GetWorkflowRequest{WorkflowId: "wf-1"}); err != nil || !resp.Paused {
+		t.Errorf("GetWorkflow = %v, %v; want a paused workflow", resp, err)
+	}
+	if _, err := server.ResumeWorkflow(ctx, &pb.ResumeWorkflowRequest{WorkflowId: "wf-1"}); err != nil {
+		t.Fatalf("ResumeWorkflow failed: %v", err)
+	}
+	if resp, err := server.GetWorkflow(ctx, &pb.GetWorkflowRequest{WorkflowId: "wf-1"}); err != nil || resp.Paused {
+		t.Errorf("GetWorkflow = %v, %v; want a resumed workflow", resp, err)
+	}
+	if len(logger.events) != 2 || logger.events[0].Type != EventWorkflowPaused || logger.events[1].Type != EventWorkflowResumed {
+		t.Errorf("expected WorkflowPaused and WorkflowResumed events, got %+v", logger.events)
+	}
+
+	_, err := server.PauseWorkflow(ctx, &pb.PauseWorkflowRequest{WorkflowId: "missing"})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+		t.Errorf("expected NotFound error, got %v", err)
+	}
+	_, err = server.ResumeWorkflow(ctx, &pb.ResumeWorkflowRequest{})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument error, got %v", err)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	pb "paul.hobbs.page/aisociety/protos"
 	"paul.hobbs.page/aisociety/services/workflow/api"
 	"paul.hobbs.page/aisociety/services/workflow/persistence"
+	"paul.hobbs.page/aisociety/services/workflow/schema"
 
+	"github.com/jackc/pgx/v4/pgxpool"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
@@ -24,10 +26,23 @@ func main() {
 	}
 
 	ctx := context.Background()
-	sm, err := persistence.NewPostgresStateManagerFromConnStr(ctx, connStr)
+	pool, err := pgxpool.Connect(ctx, connStr)
 	if err != nil {
 		panic(fmt.Sprintf("failed to connect to DB: %v", err))
 	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, pool, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	// Bring the schema up to date, refusing to run against one migrated by
+	// a newer release.
+	if err := schema.Up(ctx, pool); err != nil {
+		panic(fmt.Sprintf("failed to migrate DB: %v", err))
+	}
+	sm := persistence.NewPostgresStateManager(pool)
 
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -36,9 +51,18 @@ func main() {
 
 	s := grpc.NewServer(grpc.UnaryInterceptor(api.AuthInterceptor))
 
-	workflowSvc := api.NewWorkflowServiceServer(sm, &api.StdoutEventLogger{})
+	eventLogger := &api.StdoutEventLogger{}
+	workflowSvc := api.NewWorkflowServiceServer(sm, eventLogger)
 	pb.RegisterWorkflowServiceServer(s, workflowSvc)
 
+	// Detect finished workflows and record their final status.
+	reconciler := &api.WorkflowReconciler{StateManager: sm, EventLogger: eventLogger, Events: sm}
+	go reconciler.Run(ctx)
+
+	// Start workflows from triggers whose cron schedule fired.
+	triggerRunner := &api.TriggerRunner{StateManager: sm, Server: workflowSvc}
+	go triggerRunner.Run(ctx)
+
 	reflection.Register(s)
 
 	fmt.Printf("WorkflowService server listening on port %s\n", port)
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"paul.hobbs.page/aisociety/services/workflow/schema"
+)
+
+const migrateUsage = `usage: workflow migrate [up | down [VERSION] | version]
+
+  up       apply every pending migration (the default)
+  down     revert to VERSION, by default the version before the current one
+  version  print the current and the latest schema version`
+
+// runMigrate implements the migrate subcommand.
+func runMigrate(ctx context.Context, db schema.DB, args []string) error {
+	cmd := "up"
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+	switch {
+	case cmd == "up" && len(args) == 0:
+		return schema.Up(ctx, db)
+
+	case cmd == "down" && len(args) <= 1:
+		target, err := schema.Version(ctx, db)
+		if err != nil {
+			return err
+		}
+		target--
+		if len(args) == 1 {
+			if target, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("invalid version %q\n%s", args[0], migrateUsage)
+			}
+		}
+		return schema.To(ctx, db, target)
+
+	case cmd == "version" && len(args) == 0:
+		current, err := schema.Version(ctx, db)
+		if err != nil {
+			return err
+		}
+		latest, err := schema.Latest()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("schema version %d, latest %d\n", current, latest)
+		return nil
+	}
+	return errors.New(migrateUsage)
+}
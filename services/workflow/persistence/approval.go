@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func (p *PostgresStateManager) ListPendingApprovals(ctx context.Context, workflowID string) ([]*PendingApproval, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT workflow_id::text, node, updated_at FROM nodes
+		 WHERE status = $1 AND ($2 = '' OR workflow_id::text = $2)
+		 ORDER BY updated_at`,
+		int32(pb.Status_WAITING_FOR_APPROVAL), workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("ListPendingApprovals query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []*PendingApproval
+	for rows.Next() {
+		var a PendingApproval
+		var nodeBytes []byte
+		if err := rows.Scan(&a.WorkflowID, &nodeBytes, &a.WaitingSince); err != nil {
+			return nil, fmt.Errorf("ListPendingApprovals scan failed: %w", err)
+		}
+		if a.Node, err = unmarshalNode(nodeBytes, int32(pb.Status_WAITING_FOR_APPROVAL)); err != nil {
+			return nil, fmt.Errorf("ListPendingApprovals unmarshal failed: %w", err)
+		}
+		pending = append(pending, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListPendingApprovals rows error: %w", err)
+	}
+	workflowIDs := make([]string, len(pending))
+	nodes := make([]*pb.Node, len(pending))
+	for i, a := range pending {
+		workflowIDs[i], nodes[i] = a.WorkflowID, a.Node
+	}
+	if err := loadEdges(ctx, p.pool, workflowIDs, nodes); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// DecideApproval records approval on a waiting APPROVAL node and moves it to
+// PASS or FAIL, so that its children are promoted or its failure policy is
+// applied in the same transaction.
+func (p *PostgresStateManager) DecideApproval(ctx context.Context, workflowID, nodeID string, approval *pb.Approval) (*pb.Node, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status int32
+	var nodeBytes []byte
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(status, 0), node FROM nodes WHERE workflow_id = $1 AND id = $2 FOR UPDATE`,
+		workflowID, nodeID).Scan(&status, &nodeBytes)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNodeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DecideApproval lookup failed: %w", err)
+	}
+	if pb.Status(status) != pb.Status_WAITING_FOR_APPROVAL {
+		return nil, ErrNotWaitingForApproval
+	}
+	node, err := unmarshalNode(nodeBytes, status)
+	if err != nil {
+		return nil, fmt.Errorf("DecideApproval unmarshal failed: %w", err)
+	}
+	if err := loadWorkflowEdges(ctx, tx, workflowID, node); err != nil {
+		return nil, err
+	}
+
+	if approval.DecidedAt == nil {
+		approval.DecidedAt = timestamppb.Now()
+	}
+	node.Approval = approval
+	node.Status = pb.Status_FAIL
+	verb := "rejected"
+	if approval.Approved {
+		node.Status = pb.Status_PASS
+		verb = "approved"
+	}
+	message := fmt.Sprintf("%s by %q", verb, approval.GetApprover().GetAgent())
+	if approval.Comment != "" {
+		message += ": " + approval.Comment
+	}
+	appendStatusUpdate(node, node.Status, message)
+
+	if err := p.updateNodeTx(ctx, tx, workflowID, node); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return node, nil
+}
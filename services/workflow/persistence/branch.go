@@ -0,0 +1,181 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// takeBranch selects the children taken by a BRANCH node that has just
+// passed, recording them in its BranchOptions, and filters the others. Unless
+// FILTERED satisfies their children, the pending nodes downstream of the
+// filtered ones can never run, so they are filtered too.
+func (p *PostgresStateManager) takeBranch(ctx context.Context, tx pgx.Tx, workflowID string, node *pb.Node) error {
+	if node.GetType() != pb.NodeType_BRANCH || node.Status != pb.Status_PASS {
+		return nil
+	}
+	subjects, err := loadBranchSubjects(ctx, tx, workflowID, node)
+	if err != nil {
+		return err
+	}
+	taken := selectBranch(node, subjects)
+	if node.BranchOptions == nil {
+		node.BranchOptions = &pb.BranchOptions{}
+	}
+	node.BranchOptions.TakenChildIds = taken
+	appendStatusUpdate(node, node.Status, fmt.Sprintf("took %d of %d children", len(taken), len(node.ChildIds)))
+
+	isTaken := make(map[string]bool, len(taken))
+	for _, id := range taken {
+		isTaken[id] = true
+	}
+	var notTaken []string
+	for _, id := range node.ChildIds {
+		if !isTaken[id] {
+			notTaken = append(notTaken, id)
+		}
+	}
+	if len(notTaken) == 0 {
+		return nil
+	}
+
+	reason := fmt.Sprintf("filtered: branch node %s did not take this branch", node.NodeId)
+	filtered, err := markNodes(ctx, tx, workflowID, pb.Status_FILTERED, reason,
+		`SELECT node FROM nodes WHERE workflow_id = $1 AND id::text = ANY($2) AND status = ANY($3) FOR UPDATE`,
+		workflowID, notTaken, pendingStatuses)
+	if err != nil {
+		return fmt.Errorf("failed to filter branches of node %s: %w", node.NodeId, err)
+	}
+	for _, f := range filtered {
+		if p.isSatisfying(pb.Status_FILTERED) {
+			if err := p.promoteReadyChildren(ctx, tx, workflowID, f); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err := markNodes(ctx, tx, workflowID, pb.Status_FILTERED, reason,
+			pendingDescendantsQuery, workflowID, f.NodeId, pendingStatuses)
+		if err != nil {
+			return fmt.Errorf("failed to filter branches of node %s: %w", node.NodeId, err)
+		}
+	}
+	return nil
+}
+
+// loadBranchSubjects loads the nodes whose results the cases of a BRANCH
+// node test, keyed by ID. The BRANCH node itself is used as given.
+func loadBranchSubjects(ctx context.Context, tx pgx.Tx, workflowID string, node *pb.Node) (map[string]*pb.Node, error) {
+	subjects := map[string]*pb.Node{node.NodeId: node}
+	var ids []string
+	for _, c := range node.GetBranchOptions().GetCases() {
+		if id := branchSubject(node, c.GetWhen()); subjects[id] == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return subjects, nil
+	}
+	rows, err := tx.Query(ctx,
+		`SELECT COALESCE(status, 0), node FROM nodes WHERE workflow_id = $1 AND id::text = ANY($2)`,
+		workflowID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load branch subjects of node %s: %w", node.NodeId, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status int32
+		var nodeBytes []byte
+		if err := rows.Scan(&status, &nodeBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan branch subject: %w", err)
+		}
+		n, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal branch subject: %w", err)
+		}
+		subjects[n.NodeId] = n
+	}
+	return subjects, rows.Err()
+}
+
+// branchSubject returns the ID of the node whose result pred tests.
+func branchSubject(node *pb.Node, pred *pb.ResultPredicate) string {
+	if id := pred.GetNodeId(); id != "" {
+		return id
+	}
+	if len(node.ParentIds) > 0 {
+		return node.ParentIds[0]
+	}
+	return node.NodeId
+}
+
+// selectBranch returns the children of a BRANCH node that it takes: those of
+// the first case that matches, or the default children if none does, plus
+// every child no case or default names.
+func selectBranch(node *pb.Node, subjects map[string]*pb.Node) []string {
+	opts := node.GetBranchOptions()
+	named := make(map[string]bool)
+	for _, c := range opts.GetCases() {
+		for _, id := range c.ChildIds {
+			named[id] = true
+		}
+	}
+	for _, id := range opts.GetDefaultChildIds() {
+		named[id] = true
+	}
+
+	chosen := opts.GetDefaultChildIds()
+	for _, c := range opts.GetCases() {
+		if matchesResult(c.GetWhen(), subjects[branchSubject(node, c.GetWhen())]) {
+			chosen = c.ChildIds
+			break
+		}
+	}
+	isChosen := make(map[string]bool, len(chosen))
+	for _, id := range chosen {
+		isChosen[id] = true
+	}
+
+	var taken []string
+	for _, id := range node.ChildIds {
+		if !named[id] || isChosen[id] {
+			taken = append(taken, id)
+		}
+	}
+	return taken
+}
+
+// matchesResult reports whether the latest result of subject's assigned task
+// matches pred. An unset predicate matches any existing node.
+func matchesResult(pred *pb.ResultPredicate, subject *pb.Node) bool {
+	if subject == nil {
+		return false
+	}
+	var result *pb.Task_Result
+	if results := subject.GetAssignedTask().GetResults(); len(results) > 0 {
+		result = results[len(results)-1]
+	}
+	if want := pred.GetStatus(); want != pb.Status_UNKNOWN {
+		got := subject.Status
+		if result != nil {
+			got = result.Status
+		}
+		if got != want {
+			return false
+		}
+	}
+	if sub := pred.GetSummaryContains(); sub != "" &&
+		!strings.Contains(strings.ToLower(result.GetSummary()), strings.ToLower(sub)) {
+		return false
+	}
+	for name, want := range pred.GetArtifacts() {
+		got, ok := result.GetArtifacts()[name]
+		if !ok || (want != "" && got != want) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+func (p *PostgresStateManager) CancelWorkflow(ctx context.Context, workflowID, reason string) ([]string, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT true FROM workflows WHERE id = $1`, workflowID).Scan(&exists)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CancelWorkflow lookup failed: %w", err)
+	}
+
+	skipped, err := skipNodes(ctx, tx, workflowID, cancelMessage(reason),
+		`SELECT node FROM nodes WHERE workflow_id = $1 AND status = ANY($2) FOR UPDATE`,
+		workflowID, unfinishedStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("CancelWorkflow failed: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nodeIDs(skipped), nil
+}
+
+func (p *PostgresStateManager) CancelNode(ctx context.Context, workflowID, nodeID, reason string) ([]string, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status int32
+	err = tx.QueryRow(ctx, `SELECT COALESCE(status, 0) FROM nodes WHERE workflow_id = $1 AND id = $2 FOR UPDATE`,
+		workflowID, nodeID).Scan(&status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNodeNotFound
+		}
+		return nil, fmt.Errorf("CancelNode lookup failed: %w", err)
+	}
+
+	message := cancelMessage(reason)
+	skipped, err := skipNodes(ctx, tx, workflowID, message,
+		`SELECT node FROM nodes WHERE workflow_id = $1 AND id = $2 AND status = ANY($3) FOR UPDATE`,
+		workflowID, nodeID, unfinishedStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("CancelNode failed: %w", err)
+	}
+	if len(skipped) == 0 {
+		// The node already finished; there is nothing to cancel.
+		return nil, nil
+	}
+
+	// Unless SKIPPED satisfies its children, the nodes downstream of a
+	// canceled node can never run, so cancel them too.
+	if p.isSatisfying(pb.Status_SKIPPED) {
+		if err := p.promoteReadyChildren(ctx, tx, workflowID, skipped[0]); err != nil {
+			return nil, err
+		}
+	} else {
+		descendants, err := skipNodes(ctx, tx, workflowID,
+			fmt.Sprintf("%s (upstream node %s was canceled)", message, nodeID),
+			pendingDescendantsQuery, workflowID, nodeID, pendingStatuses)
+		if err != nil {
+			return nil, fmt.Errorf("CancelNode failed: %w", err)
+		}
+		skipped = append(skipped, descendants...)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nodeIDs(skipped), nil
+}
+
+// cancelMessage is the status log message recorded on canceled nodes.
+func cancelMessage(reason string) string {
+	if reason == "" {
+		return "canceled"
+	}
+	return "canceled: " + reason
+}
+
+func nodeIDs(nodes []*pb.Node) []string {
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.NodeId
+	}
+	return ids
+}
@@ -0,0 +1,450 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+const (
+	// DefaultMaxWorkflowNodes is how many nodes agent edits may grow a
+	// workflow to when EditPolicy.MaxNodes is zero.
+	DefaultMaxWorkflowNodes = 1000
+
+	// DefaultMaxEditDepth is the deepest Node.edit_depth agent edits may
+	// insert nodes at when EditPolicy.MaxEditDepth is zero.
+	DefaultMaxEditDepth = 3
+)
+
+// EditPolicy limits the NodeEdits ApplyAgentEdits applies on behalf of an
+// agent. Whatever the limits, an agent may only insert nodes below its own
+// node, and only update or delete unfinished, not yet dispatched nodes
+// downstream of it.
+type EditPolicy struct {
+	// MaxNodes caps how many nodes a workflow may grow to through agent
+	// edits. Defaults to DefaultMaxWorkflowNodes.
+	MaxNodes int
+	// MaxEditDepth caps the edit depth of inserted nodes, so that nodes
+	// inserted by edits cannot keep inserting nodes forever. Defaults to
+	// DefaultMaxEditDepth.
+	MaxEditDepth int32
+}
+
+func (e EditPolicy) maxNodes() int {
+	if e.MaxNodes <= 0 {
+		return DefaultMaxWorkflowNodes
+	}
+	return e.MaxNodes
+}
+
+func (e EditPolicy) maxEditDepth() int32 {
+	if e.MaxEditDepth <= 0 {
+		return DefaultMaxEditDepth
+	}
+	return e.MaxEditDepth
+}
+
+func (p *PostgresStateManager) ApplyAgentEdits(ctx context.Context, workflowID, nodeID string, edits []*pb.NodeEdit) ([]*pb.RejectedEdit, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize agent edits per workflow, since each is checked against the
+	// graph as the previous ones left it.
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT true FROM workflows WHERE id = $1 FOR UPDATE`, workflowID).Scan(&exists)
+	if err == pgx.ErrNoRows {
+		return nil, ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ApplyAgentEdits lookup failed: %w", err)
+	}
+	nodes, err := listNodesTx(ctx, tx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	g := newEditGraph(nodes)
+	origin, ok := g.nodes[nodeID]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	var rejected []*pb.RejectedEdit
+	for _, edit := range edits {
+		next, node, reason := p.planAgentEdit(g, origin, edit)
+		if reason != "" {
+			rejected = append(rejected, &pb.RejectedEdit{Edit: edit, Reason: reason, RejectedAt: timestamppb.Now()})
+			continue
+		}
+		if err := p.applyAgentEdit(ctx, tx, workflowID, edit, node); err != nil {
+			return nil, err
+		}
+		g = next
+	}
+
+	if len(rejected) > 0 {
+		origin = g.nodes[nodeID]
+		origin.RejectedEdits = append(origin.RejectedEdits, rejected...)
+		if err := writeNode(ctx, tx, workflowID, origin); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return rejected, nil
+}
+
+// planAgentEdit checks an edit by origin's agent against the edit policy and
+// the graph g. It returns the graph after the edit and the node to write, or
+// the reason the edit is rejected. The nodes at the other ends of the edges
+// the edit adds or removes are only updated in the returned graph, since the
+// stored edges are authoritative.
+func (p *PostgresStateManager) planAgentEdit(g *editGraph, origin *pb.Node, edit *pb.NodeEdit) (next *editGraph, node *pb.Node, reason string) {
+	if edit.GetNode() == nil {
+		return nil, nil, "edit has no node"
+	}
+	descendants := g.descendants(origin.NodeId)
+	node = proto.Clone(edit.Node).(*pb.Node)
+	id := node.NodeId
+	deleted := ""
+	var touched []*pb.Node
+
+	switch edit.Type {
+	case pb.NodeEdit_INSERT:
+		if id == "" {
+			id = uuid.New().String()
+			node.NodeId = id
+		}
+		if _, exists := g.nodes[id]; exists {
+			return nil, nil, fmt.Sprintf("node %s already exists", id)
+		}
+		depth := origin.EditDepth + 1
+		if max := p.EditPolicy.maxEditDepth(); depth > max {
+			return nil, nil, fmt.Sprintf("node %s is at edit depth %d and may not insert nodes below depth %d",
+				origin.NodeId, origin.EditDepth, max)
+		}
+		if max := p.EditPolicy.maxNodes(); len(g.ids) >= max {
+			return nil, nil, fmt.Sprintf("workflow already has %d nodes, the most agent edits may grow it to", max)
+		}
+		if len(node.ParentIds) == 0 {
+			return nil, nil, fmt.Sprintf("inserted node %s has no parents; it must descend from node %s", id, origin.NodeId)
+		}
+		if touched, reason = linkAgentEdges(g, origin, descendants, node, nil, nil); reason != "" {
+			return nil, nil, reason
+		}
+		clearExecutionState(node)
+		node.EditDepth = depth
+		node.RejectedEdits = nil
+
+	case pb.NodeEdit_UPDATE, pb.NodeEdit_DELETE:
+		target, ok := g.nodes[id]
+		if !ok {
+			return nil, nil, fmt.Sprintf("node %s does not exist", id)
+		}
+		if !descendants[id] {
+			return nil, nil, fmt.Sprintf("node %s is not a descendant of node %s", id, origin.NodeId)
+		}
+		if reason := editBlocker(target); reason != "" {
+			return nil, nil, reason
+		}
+		if edit.Type == pb.NodeEdit_UPDATE {
+			if reason := executionStateChange(target, node); reason != "" {
+				return nil, nil, reason
+			}
+			keepExecutionState(target, node)
+			// The update's parent and child lists replace the target's edges.
+			if touched, reason = linkAgentEdges(g, origin, descendants, node, target.ParentIds, target.ChildIds); reason != "" {
+				return nil, nil, reason
+			}
+			node.EditDepth = target.EditDepth
+			node.RejectedEdits = target.RejectedEdits
+			break
+		}
+		if len(target.ChildIds) > 0 {
+			return nil, nil, fmt.Sprintf("node %s has children, which must be deleted first", id)
+		}
+		for _, pid := range target.ParentIds {
+			if parent, ok := g.nodes[pid]; ok {
+				touched = append(touched, unlinked(parent, id, false))
+			}
+		}
+		deleted = id
+
+	default:
+		return nil, nil, fmt.Sprintf("unknown edit type %v", edit.Type)
+	}
+
+	changed := touched
+	if deleted == "" {
+		changed = append([]*pb.Node{node}, touched...)
+	}
+	next = g.with(changed, deleted)
+	if err := ValidateGraph(next.list()); err != nil {
+		return nil, nil, err.Error()
+	}
+	return next, node, ""
+}
+
+// linkAgentEdges checks the edges node lists in place of the given former
+// parents and children against the edit policy: new parents must be origin or
+// its descendants, and children gained or lost must be descendants an agent
+// may edit. It returns copies of the nodes at the other ends of the edges
+// gained or lost, listing or no longer listing node.
+func linkAgentEdges(g *editGraph, origin *pb.Node, descendants map[string]bool, node *pb.Node, parents, children []string) (touched []*pb.Node, reason string) {
+	id := node.NodeId
+	for _, pid := range node.ParentIds {
+		if containsID(parents, pid) {
+			continue
+		}
+		if pid != origin.NodeId && !descendants[pid] {
+			return nil, fmt.Sprintf("parent %s of node %s is not node %s or one of its descendants", pid, id, origin.NodeId)
+		}
+		if parent, ok := g.nodes[pid]; ok {
+			touched = append(touched, linked(parent, id, false))
+		}
+	}
+	for _, pid := range parents {
+		if parent, ok := g.nodes[pid]; ok && !containsID(node.ParentIds, pid) {
+			touched = append(touched, unlinked(parent, id, false))
+		}
+	}
+	for _, cid := range node.ChildIds {
+		child, ok := g.nodes[cid]
+		if !ok || containsID(children, cid) {
+			continue // missing children are reported by ValidateGraph
+		}
+		if !descendants[cid] {
+			return nil, fmt.Sprintf("child %s of node %s is not a descendant of node %s", cid, id, origin.NodeId)
+		}
+		if reason := editBlocker(child); reason != "" {
+			return nil, reason
+		}
+		touched = append(touched, linked(child, id, true))
+	}
+	for _, cid := range children {
+		child, ok := g.nodes[cid]
+		if !ok || containsID(node.ChildIds, cid) {
+			continue
+		}
+		if reason := editBlocker(child); reason != "" {
+			return nil, reason
+		}
+		touched = append(touched, unlinked(child, id, true))
+	}
+	return touched, ""
+}
+
+func containsID(ids []string, id string) bool {
+	for _, ref := range ids {
+		if ref == id {
+			return true
+		}
+	}
+	return false
+}
+
+// executionStateChange returns why node, an update of target, may not be
+// applied because it changes what only running the node may change: its
+// status, finality, attempts, approval or child workflow. Unset fields leave
+// them as they are.
+func executionStateChange(target, node *pb.Node) string {
+	id := target.NodeId
+	switch {
+	case node.Status != pb.Status_UNKNOWN && node.Status != target.Status:
+		return fmt.Sprintf("node %s is %v; agent edits may not change its status", id, target.Status)
+	case node.IsFinal && !target.IsFinal:
+		return fmt.Sprintf("agent edits may not mark node %s final", id)
+	case len(node.Attempts) > 0 && !attemptsEqual(node.Attempts, target.Attempts):
+		return fmt.Sprintf("agent edits may not change the attempts of node %s", id)
+	case node.Approval != nil && !proto.Equal(node.Approval, target.Approval):
+		return fmt.Sprintf("agent edits may not approve or reject node %s", id)
+	case node.GetSubworkflowOptions().GetChildWorkflowId() != "" &&
+		node.GetSubworkflowOptions().GetChildWorkflowId() != target.GetSubworkflowOptions().GetChildWorkflowId():
+		return fmt.Sprintf("agent edits may not set the child workflow of node %s", id)
+	}
+	return ""
+}
+
+func attemptsEqual(a, b []*pb.Attempt) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// keepExecutionState copies target's execution state onto node, its update.
+func keepExecutionState(target, node *pb.Node) {
+	node.Status = target.Status
+	node.IsFinal = target.IsFinal
+	node.Attempts = target.Attempts
+	node.Approval = target.Approval
+	if node.SubworkflowOptions != nil {
+		node.SubworkflowOptions.ChildWorkflowId = target.GetSubworkflowOptions().GetChildWorkflowId()
+	}
+}
+
+// clearExecutionState makes an inserted node pending, without any of the
+// execution state an agent may not make up. Like any pending node, it becomes
+// ready once its parents are satisfied.
+func clearExecutionState(node *pb.Node) {
+	node.Status = pb.Status_BLOCKED
+	node.IsFinal = false
+	node.Attempts = nil
+	node.Approval = nil
+	if node.SubworkflowOptions != nil {
+		node.SubworkflowOptions.ChildWorkflowId = ""
+	}
+}
+
+// applyAgentEdit writes an edit planned by planAgentEdit.
+func (p *PostgresStateManager) applyAgentEdit(ctx context.Context, tx pgx.Tx, workflowID string, edit *pb.NodeEdit, node *pb.Node) error {
+	var err error
+	switch edit.Type {
+	case pb.NodeEdit_INSERT:
+		err = p.createNodeTx(ctx, tx, workflowID, node)
+	case pb.NodeEdit_UPDATE:
+		err = p.applyUpdateEdit(ctx, tx, workflowID, &pb.NodeEdit{
+			Type: edit.Type, Timestamp: edit.Timestamp, Description: edit.Description, Node: node,
+		})
+	case pb.NodeEdit_DELETE:
+		err = p.applyDeleteEdit(ctx, tx, workflowID, edit)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply %v edit of node %s: %w", edit.Type, node.NodeId, err)
+	}
+	return nil
+}
+
+// editBlocker returns why an agent may not edit n, or "" if it may: nodes
+// that are finished or in flight are off limits.
+func editBlocker(n *pb.Node) string {
+	if n.IsFinal {
+		return fmt.Sprintf("node %s is final", n.NodeId)
+	}
+	switch n.Status {
+	case pb.Status_READY, pb.Status_UNKNOWN, pb.Status_BLOCKED:
+		return ""
+	case pb.Status_RUNNING, pb.Status_WAITING_FOR_APPROVAL, pb.Status_WAITING_FOR_WORKFLOW:
+		return fmt.Sprintf("node %s is in flight", n.NodeId)
+	}
+	return fmt.Sprintf("node %s is final", n.NodeId)
+}
+
+// writeNode stores n as is, without applying the consequences of its status.
+func writeNode(ctx context.Context, tx pgx.Tx, workflowID string, n *pb.Node) error {
+	edit := &pb.NodeEdit{Node: n}
+	nodeBytes, allTasksBytes, editsBytes, err := serializeNodeData(edit)
+	if err != nil {
+		return err
+	}
+	if err := updateNodeRecord(ctx, tx, workflowID, edit, nodeBytes, allTasksBytes, editsBytes); err != nil {
+		return fmt.Errorf("failed to update node %s: %w", n.NodeId, err)
+	}
+	return nil
+}
+
+// linked returns a copy of n that lists id as a parent, or as a child if
+// asParent is false.
+func linked(n *pb.Node, id string, asParent bool) *pb.Node {
+	n = proto.Clone(n).(*pb.Node)
+	refs := &n.ChildIds
+	if asParent {
+		refs = &n.ParentIds
+	}
+	if !containsID(*refs, id) {
+		*refs = append(*refs, id)
+	}
+	return n
+}
+
+// unlinked returns a copy of n that no longer lists id as a parent, or as a
+// child if asParent is false.
+func unlinked(n *pb.Node, id string, asParent bool) *pb.Node {
+	n = proto.Clone(n).(*pb.Node)
+	refs := &n.ChildIds
+	if asParent {
+		refs = &n.ParentIds
+	}
+	var kept []string
+	for _, ref := range *refs {
+		if ref != id {
+			kept = append(kept, ref)
+		}
+	}
+	*refs = kept
+	return n
+}
+
+// editGraph is the view of a workflow's nodes that agent edits are checked
+// against.
+type editGraph struct {
+	ids   []string // in creation order
+	nodes map[string]*pb.Node
+}
+
+func newEditGraph(nodes []*pb.Node) *editGraph {
+	g := &editGraph{nodes: make(map[string]*pb.Node, len(nodes))}
+	for _, n := range nodes {
+		g.ids = append(g.ids, n.NodeId)
+		g.nodes[n.NodeId] = n
+	}
+	return g
+}
+
+func (g *editGraph) list() []*pb.Node {
+	nodes := make([]*pb.Node, len(g.ids))
+	for i, id := range g.ids {
+		nodes[i] = g.nodes[id]
+	}
+	return nodes
+}
+
+// with returns a copy of g with changed nodes added or replaced, and the node
+// deleted, if any, removed.
+func (g *editGraph) with(changed []*pb.Node, deleted string) *editGraph {
+	next := &editGraph{nodes: make(map[string]*pb.Node, len(g.nodes)+1)}
+	for _, id := range g.ids {
+		if id != deleted {
+			next.ids = append(next.ids, id)
+			next.nodes[id] = g.nodes[id]
+		}
+	}
+	for _, n := range changed {
+		if _, ok := next.nodes[n.NodeId]; !ok {
+			next.ids = append(next.ids, n.NodeId)
+		}
+		next.nodes[n.NodeId] = n
+	}
+	return next
+}
+
+// descendants returns the IDs of the nodes downstream of id.
+func (g *editGraph) descendants(id string) map[string]bool {
+	seen := map[string]bool{}
+	queue := append([]string{}, g.nodes[id].GetChildIds()...)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		queue = append(queue, g.nodes[next].GetChildIds()...)
+	}
+	return seen
+}
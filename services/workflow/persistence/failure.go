@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// DefaultFailurePolicy applies when neither a failed node nor its workflow
+// sets a failure policy.
+const DefaultFailurePolicy = pb.FailurePolicy_SKIP_DESCENDANTS
+
+// failureStatuses are the final statuses that trigger a failure policy.
+var failureStatuses = []pb.Status{
+	pb.Status_FAIL, pb.Status_TASK_ERROR, pb.Status_INFRA_ERROR, pb.Status_TIMEOUT, pb.Status_CRASH,
+}
+
+// unfinishedStatuses are the statuses of nodes that FAIL_FAST and cancellation
+// skip: pending, ready, in-flight and waiting nodes.
+var unfinishedStatuses = append([]int32{
+	int32(pb.Status_READY), int32(pb.Status_RUNNING), int32(pb.Status_WAITING_FOR_APPROVAL),
+	int32(pb.Status_WAITING_FOR_WORKFLOW),
+}, pendingStatuses...)
+
+// EffectiveFailurePolicy returns the failure policy that applies when node
+// fails in a workflow whose own policy is workflowPolicy.
+func EffectiveFailurePolicy(node *pb.Node, workflowPolicy pb.FailurePolicy) pb.FailurePolicy {
+	if p := node.GetFailurePolicy(); p != pb.FailurePolicy_FAILURE_POLICY_UNSPECIFIED {
+		return p
+	}
+	if workflowPolicy != pb.FailurePolicy_FAILURE_POLICY_UNSPECIFIED {
+		return workflowPolicy
+	}
+	return DefaultFailurePolicy
+}
+
+// IsFailureStatus reports whether status is a final status in which a node has
+// failed, which is what triggers its failure policy.
+func IsFailureStatus(status pb.Status) bool {
+	for _, s := range failureStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFailurePolicy skips the nodes that node's failure makes pointless,
+// according to its effective failure policy: its pending descendants under
+// SKIP_DESCENDANTS, or every other unfinished node of the workflow under
+// FAIL_FAST. Skipped nodes that were RUNNING lose their lease, which tells the
+// scheduler dispatching them to abandon them. It is a no-op unless node has
+// just reached a failure status that does not satisfy its children.
+func (p *PostgresStateManager) applyFailurePolicy(ctx context.Context, tx pgx.Tx, workflowID string, node *pb.Node) error {
+	if !IsFailureStatus(node.Status) || p.isSatisfying(node.Status) {
+		return nil
+	}
+	var workflowPolicy int32
+	if node.GetFailurePolicy() == pb.FailurePolicy_FAILURE_POLICY_UNSPECIFIED {
+		err := tx.QueryRow(ctx, `SELECT failure_policy FROM workflows WHERE id = $1`, workflowID).Scan(&workflowPolicy)
+		if err != nil && err != pgx.ErrNoRows {
+			return fmt.Errorf("failed to load failure policy of workflow %s: %w", workflowID, err)
+		}
+	}
+
+	switch EffectiveFailurePolicy(node, pb.FailurePolicy(workflowPolicy)) {
+	case pb.FailurePolicy_SKIP_DESCENDANTS:
+		reason := fmt.Sprintf("skipped: upstream node %s ended with %v", node.NodeId, node.Status)
+		_, err := skipNodes(ctx, tx, workflowID, reason, pendingDescendantsQuery, workflowID, node.NodeId, pendingStatuses)
+		return err
+	case pb.FailurePolicy_FAIL_FAST:
+		reason := fmt.Sprintf("canceled: node %s ended with %v and the workflow fails fast", node.NodeId, node.Status)
+		_, err := skipNodes(ctx, tx, workflowID, reason,
+			`SELECT node FROM nodes WHERE workflow_id = $1 AND status = ANY($2) AND id::text <> $3 FOR UPDATE`,
+			workflowID, unfinishedStatuses, node.NodeId)
+		return err
+	}
+	return nil
+}
+
+// pendingDescendantsQuery selects and locks the pending descendants of node $2
+// in workflow $1, with $3 bound to the pending statuses.
+const pendingDescendantsQuery = `WITH RECURSIVE descendants(id) AS (
+		SELECT child_node_id FROM node_edges WHERE workflow_id = $1 AND parent_node_id = $2
+		UNION
+		SELECT e.child_node_id FROM node_edges e
+		JOIN descendants d ON e.parent_node_id = d.id
+		WHERE e.workflow_id = $1
+	)
+	SELECT node FROM nodes
+	WHERE workflow_id = $1 AND status = ANY($3) AND id::text IN (SELECT id FROM descendants)
+	FOR UPDATE`
+
+// skipNodes moves the nodes of a workflow selected by query, which must select
+// the node column, to SKIPPED and records reason in their status logs. Nodes
+// that were RUNNING lose their lease. It returns the skipped nodes.
+func skipNodes(ctx context.Context, tx pgx.Tx, workflowID, reason, query string, args ...interface{}) ([]*pb.Node, error) {
+	return markNodes(ctx, tx, workflowID, pb.Status_SKIPPED, reason, query, args...)
+}
+
+// markNodes is skipNodes for any final status.
+func markNodes(ctx context.Context, tx pgx.Tx, workflowID string, status pb.Status, reason, query string, args ...interface{}) ([]*pb.Node, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find nodes to skip: %w", err)
+	}
+	var skipped []*pb.Node
+	for rows.Next() {
+		var nodeBytes []byte
+		if err := rows.Scan(&nodeBytes); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan node to skip: %w", err)
+		}
+		n, err := unmarshalNode(nodeBytes, int32(status))
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to unmarshal node to skip: %w", err)
+		}
+		appendStatusUpdate(n, status, reason)
+		skipped = append(skipped, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find nodes to skip: %w", err)
+	}
+
+	for _, n := range skipped {
+		edit := &pb.NodeEdit{Node: n}
+		nodeBytes, allTasksBytes, editsBytes, err := serializeNodeData(edit)
+		if err != nil {
+			return nil, err
+		}
+		if err := updateNodeRecord(ctx, tx, workflowID, edit, nodeBytes, allTasksBytes, editsBytes); err != nil {
+			return nil, err
+		}
+	}
+	if err := cancelChildWorkflows(ctx, tx, skipped, reason); err != nil {
+		return nil, err
+	}
+	return skipped, nil
+}
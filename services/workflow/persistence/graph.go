@@ -0,0 +1,284 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// ErrInvalidGraph is wrapped by the errors of ValidateGraph, and of
+// ApplyNodeEdits when the edits would leave an invalid graph.
+var ErrInvalidGraph = errors.New("invalid workflow graph")
+
+// ValidateGraph checks that nodes form a DAG the scheduler can resolve: every
+// node has a unique ID, every parent and child it lists exists, and no node is
+// its own ancestor. An edge may be listed by either of its nodes or by both,
+// as DeriveEdges completes the other side. The templates of SUBWORKFLOW nodes
+// must be valid graphs too. The error explains the first problem found, e.g.
+// the path of a cycle.
+func ValidateGraph(nodes []*pb.Node) error {
+	if err := checkGraph(nodes); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGraph, err)
+	}
+	return nil
+}
+
+func checkGraph(nodes []*pb.Node) error {
+	byID := make(map[string]*pb.Node, len(nodes))
+	for i, n := range nodes {
+		if n.GetNodeId() == "" {
+			return fmt.Errorf("node %d has no node_id", i)
+		}
+		if _, dup := byID[n.NodeId]; dup {
+			return fmt.Errorf("duplicate node_id %q", n.NodeId)
+		}
+		byID[n.NodeId] = n
+	}
+
+	for _, n := range nodes {
+		for _, pid := range n.ParentIds {
+			if _, ok := byID[pid]; !ok {
+				return fmt.Errorf("node %q lists parent %q, which does not exist", n.NodeId, pid)
+			}
+		}
+		for _, cid := range n.ChildIds {
+			if _, ok := byID[cid]; !ok {
+				return fmt.Errorf("node %q lists child %q, which does not exist", n.NodeId, cid)
+			}
+		}
+	}
+
+	if cycle := findCycle(nodes, deriveChildren(nodes)); cycle != nil {
+		return fmt.Errorf("cycle %s", strings.Join(cycle, " -> "))
+	}
+
+	for _, n := range nodes {
+		if template := n.GetSubworkflowOptions().GetTemplate(); template != nil {
+			if err := checkGraph(template.Nodes); err != nil {
+				return fmt.Errorf("subworkflow template of node %q: %v", n.NodeId, err)
+			}
+		}
+	}
+	return nil
+}
+
+// findCycle returns the node IDs along a cycle, starting and ending with the
+// same node, or nil if the graph is acyclic.
+func findCycle(nodes []*pb.Node, children map[string][]string) []string {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = onPath
+		path = append(path, id)
+		for _, cid := range children[id] {
+			switch state[cid] {
+			case onPath:
+				for i, p := range path {
+					if p == cid {
+						return append(append([]string{}, path[i:]...), cid)
+					}
+				}
+			case unvisited:
+				if cycle := visit(cid); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+	for _, n := range nodes {
+		if state[n.NodeId] == unvisited {
+			if cycle := visit(n.NodeId); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// DeriveEdges completes the parent_ids and child_ids of nodes so that both
+// nodes of every edge list it, wherever it was listed; callers need only
+// maintain one side. Duplicates are dropped, and references to nodes not in
+// nodes are kept as they are, for ValidateGraph to report.
+func DeriveEdges(nodes []*pb.Node) {
+	byID := make(map[string]*pb.Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.GetNodeId()] = n
+	}
+	parents := make(map[string][]string, len(nodes))
+	for _, n := range nodes {
+		parents[n.NodeId] = append(parents[n.NodeId], n.ParentIds...)
+		for _, cid := range n.ChildIds {
+			if _, ok := byID[cid]; ok {
+				parents[cid] = append(parents[cid], n.NodeId)
+			}
+		}
+	}
+	children := deriveChildren(nodes)
+	for _, n := range nodes {
+		n.ParentIds = mergeRefs(n.ParentIds, parents[n.NodeId])
+		n.ChildIds = mergeRefs(n.ChildIds, children[n.NodeId])
+	}
+}
+
+// deriveChildren returns the children of each node, as listed by either side
+// of each edge.
+func deriveChildren(nodes []*pb.Node) map[string][]string {
+	ids := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		ids[n.GetNodeId()] = true
+	}
+	children := make(map[string][]string, len(nodes))
+	for _, n := range nodes {
+		children[n.NodeId] = append(children[n.NodeId], n.ChildIds...)
+		for _, pid := range n.ParentIds {
+			if ids[pid] {
+				children[pid] = append(children[pid], n.NodeId)
+			}
+		}
+	}
+	return children
+}
+
+// mergeRefs returns the IDs in refs, without duplicates, ordered as they are
+// in listed and then as they are in refs.
+func mergeRefs(listed, refs []string) []string {
+	in := make(map[string]bool, len(refs))
+	for _, id := range refs {
+		in[id] = true
+	}
+	var merged []string
+	seen := make(map[string]bool, len(refs))
+	add := func(id string) {
+		if in[id] && !seen[id] {
+			seen[id] = true
+			merged = append(merged, id)
+		}
+	}
+	for _, id := range listed {
+		add(id)
+	}
+	for _, id := range refs {
+		add(id)
+	}
+	return merged
+}
+
+// querier is satisfied by both the pool and transactions.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// loadEdges sets the parent_ids and child_ids of nodes, the ith of which
+// belongs to workflowIDs[i], from node_edges. The edges are authoritative, like
+// the status column: the lists stored with a node only keep their order, and
+// may be missing the side of an edge that another node listed.
+func loadEdges(ctx context.Context, q querier, workflowIDs []string, nodes []*pb.Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	type nodeRef struct{ workflowID, nodeID string }
+	byRef := make(map[nodeRef]*pb.Node, len(nodes))
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		byRef[nodeRef{workflowIDs[i], n.NodeId}] = n
+		ids[i] = n.NodeId
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT workflow_id::text, parent_node_id, child_node_id FROM node_edges
+		 WHERE workflow_id = ANY($1::uuid[]) AND (parent_node_id = ANY($2) OR child_node_id = ANY($2))
+		 ORDER BY created_at, id`,
+		workflowIDs, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load edges: %w", err)
+	}
+	defer rows.Close()
+
+	parents := make(map[*pb.Node][]string, len(nodes))
+	children := make(map[*pb.Node][]string, len(nodes))
+	for rows.Next() {
+		var workflowID, parentID, childID string
+		if err := rows.Scan(&workflowID, &parentID, &childID); err != nil {
+			return fmt.Errorf("failed to scan edge: %w", err)
+		}
+		if n, ok := byRef[nodeRef{workflowID, childID}]; ok {
+			parents[n] = append(parents[n], parentID)
+		}
+		if n, ok := byRef[nodeRef{workflowID, parentID}]; ok {
+			children[n] = append(children[n], childID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load edges: %w", err)
+	}
+	for _, n := range nodes {
+		n.ParentIds = mergeRefs(n.ParentIds, parents[n])
+		n.ChildIds = mergeRefs(n.ChildIds, children[n])
+	}
+	return nil
+}
+
+// loadWorkflowEdges is loadEdges for nodes of a single workflow.
+func loadWorkflowEdges(ctx context.Context, q querier, workflowID string, nodes ...*pb.Node) error {
+	workflowIDs := make([]string, len(nodes))
+	for i := range workflowIDs {
+		workflowIDs[i] = workflowID
+	}
+	return loadEdges(ctx, q, workflowIDs, nodes)
+}
+
+// loadReadyNodeEdges is loadEdges for ready nodes.
+func loadReadyNodeEdges(ctx context.Context, q querier, readyNodes []*ReadyNode) error {
+	workflowIDs := make([]string, len(readyNodes))
+	nodes := make([]*pb.Node, len(readyNodes))
+	for i, rn := range readyNodes {
+		workflowIDs[i], nodes[i] = rn.WorkflowID, rn.Node
+	}
+	return loadEdges(ctx, q, workflowIDs, nodes)
+}
+
+// listNodesTx is ListNodes within tx.
+func listNodesTx(ctx context.Context, tx pgx.Tx, workflowID string) ([]*pb.Node, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT COALESCE(status, 0), node FROM nodes WHERE workflow_id = $1 ORDER BY created_at, id`, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*pb.Node
+	for rows.Next() {
+		var status int32
+		var nodeBytes []byte
+		if err := rows.Scan(&status, &nodeBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan node: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal node: %w", err)
+		}
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	rows.Close()
+	if err := loadWorkflowEdges(ctx, tx, workflowID, nodes...); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/protobuf/proto"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// maxMapItems caps how many item nodes a MAP node expands into, so that a
+// runaway output cannot flood a workflow.
+const maxMapItems = 1000
+
+// fanoutCondition limits the concurrency of a MAP node's items: an item is
+// only ready while fewer than fanout_limit of its earlier siblings are
+// unfinished. The items dispatched at any time are thus the first
+// fanout_limit unfinished ones, whichever scheduler claims them.
+var fanoutCondition = `(n.fanout_limit IS NULL OR (
+			SELECT count(*) FROM nodes f
+			WHERE f.fanout_parent = n.fanout_parent AND f.fanout_index < n.fanout_index
+			  AND f.status IN (` + statusList(unfinishedStatuses) + `)
+		) < n.fanout_limit)`
+
+func statusList(statuses []int32) string {
+	codes := make([]string, len(statuses))
+	for i, s := range statuses {
+		codes[i] = fmt.Sprint(s)
+	}
+	return strings.Join(codes, ", ")
+}
+
+// expandMap inserts the item nodes of a MAP node that has just passed between
+// it and its children, unless it was expanded before. If the node's items
+// cannot be determined, it fails with TASK_ERROR instead.
+func (p *PostgresStateManager) expandMap(ctx context.Context, tx pgx.Tx, workflowID string, node *pb.Node) error {
+	if node.GetType() != pb.NodeType_MAP || node.Status != pb.Status_PASS {
+		return nil
+	}
+	var expanded bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM nodes WHERE fanout_parent = $1)`, node.NodeId).Scan(&expanded)
+	if err != nil {
+		return fmt.Errorf("failed to check expansion of map node %s: %w", node.NodeId, err)
+	}
+	if expanded {
+		return nil
+	}
+
+	tasks, err := mapItems(node)
+	if err != nil {
+		node.Status = pb.Status_TASK_ERROR
+		appendStatusUpdate(node, node.Status, fmt.Sprintf("failed to expand: %v", err))
+		return nil
+	}
+
+	opts := node.GetMapOptions()
+	var limit *int32
+	if c := opts.GetMaxConcurrency(); c > 0 {
+		limit = &c
+	}
+	children := append([]string(nil), node.ChildIds...)
+	itemIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		item := &pb.Node{}
+		if opts.GetTemplate() != nil {
+			item = proto.Clone(opts.GetTemplate()).(*pb.Node)
+		}
+		item.NodeId = uuid.New().String()
+		item.ParentIds = []string{node.NodeId}
+		// The original children now also wait for every item.
+		item.ChildIds = children
+		item.AssignedTask = task
+		item.Status = pb.Status_BLOCKED
+		if err := p.createNodeTx(ctx, tx, workflowID, item); err != nil {
+			return fmt.Errorf("failed to create item %d of map node %s: %w", i, node.NodeId, err)
+		}
+		_, err := tx.Exec(ctx,
+			`UPDATE nodes SET fanout_parent = $1, fanout_index = $2, fanout_limit = $3 WHERE id = $4`,
+			node.NodeId, i, limit, item.NodeId)
+		if err != nil {
+			return fmt.Errorf("failed to record item %d of map node %s: %w", i, node.NodeId, err)
+		}
+		itemIDs[i] = item.NodeId
+	}
+
+	node.ChildIds = append(node.ChildIds, itemIDs...)
+	appendStatusUpdate(node, node.Status, fmt.Sprintf("expanded into %d items", len(itemIDs)))
+	return nil
+}
+
+// mapItems returns the assigned tasks of the item nodes of a MAP node.
+func mapItems(node *pb.Node) ([]*pb.Task, error) {
+	task := node.GetAssignedTask()
+	var tasks []*pb.Task
+	switch node.GetMapOptions().GetSource() {
+	case pb.MapOptions_OUTPUT_ITEMS:
+		results := task.GetResults()
+		if len(results) == 0 {
+			return nil, errors.New("assigned task has no result to map over")
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal([]byte(results[len(results)-1].Output), &elems); err != nil {
+			return nil, fmt.Errorf("latest result output is not a JSON array: %w", err)
+		}
+		for i, e := range elems {
+			// String elements become the goal as is; others as JSON.
+			goal := string(e)
+			var s string
+			if json.Unmarshal(e, &s) == nil {
+				goal = s
+			}
+			tasks = append(tasks, &pb.Task{Id: fmt.Sprintf("%s[%d]", task.GetId(), i), Goal: goal})
+		}
+	default:
+		for _, st := range task.GetSubtasks() {
+			tasks = append(tasks, proto.Clone(st).(*pb.Task))
+		}
+	}
+	if len(tasks) > maxMapItems {
+		return nil, fmt.Errorf("%d items exceed the limit of %d", len(tasks), maxMapItems)
+	}
+	return tasks, nil
+}
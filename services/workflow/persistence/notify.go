@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// NodeEventsChannel is the Postgres NOTIFY channel on which the ID of a
+// workflow is published whenever one of its nodes changes status or gains
+// edges, i.e. whenever new nodes may have become ready.
+const NodeEventsChannel = "aisociety_node_events"
+
+// nodeEventBuffer bounds how many undelivered events ListenNodeEvents holds.
+// Events only signal that readiness should be rechecked, so overflow is
+// dropped rather than blocking the listener.
+const nodeEventBuffer = 64
+
+// notifyNodeEvent queues a notification for workflowID. Postgres delivers it
+// when tx commits and folds duplicates within the same transaction.
+func notifyNodeEvent(ctx context.Context, tx pgx.Tx, workflowID string) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_notify($1, $2)`, NodeEventsChannel, workflowID); err != nil {
+		return fmt.Errorf("failed to notify node event: %w", err)
+	}
+	return nil
+}
+
+// ListenNodeEvents subscribes to NodeEventsChannel on a dedicated connection
+// and delivers the workflow ID carried by each notification. The returned
+// channel is closed when ctx is done or the connection fails, after which the
+// caller should subscribe again.
+func (p *PostgresStateManager) ListenNodeEvents(ctx context.Context) (<-chan string, error) {
+	pooled, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire listen connection: %w", err)
+	}
+	// The connection carries LISTEN state for its whole life, so take it out
+	// of the pool rather than handing it back to other queries.
+	conn := pooled.Hijack()
+	if _, err := conn.Exec(ctx, "LISTEN "+NodeEventsChannel); err != nil {
+		conn.Close(context.Background())
+		return nil, fmt.Errorf("failed to listen on %s: %w", NodeEventsChannel, err)
+	}
+
+	events := make(chan string, nodeEventBuffer)
+	go func() {
+		defer close(events)
+		defer conn.Close(context.Background())
+		for {
+			n, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Listening for node events failed: %v", err)
+				}
+				return
+			}
+			select {
+			case events <- n.Payload:
+			default:
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetWorkflowPaused records whether workflowID is paused. Both pausing and
+// resuming notify listeners: schedulers hand back the claimed nodes of a
+// paused workflow that have not started yet, and a resumed workflow's ready
+// nodes become dispatchable again.
+func (p *PostgresStateManager) SetWorkflowPaused(ctx context.Context, workflowID string, paused bool) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE workflows SET paused_at = NULL, updated_at = now() WHERE id = $1`
+	if paused {
+		query = `UPDATE workflows SET paused_at = COALESCE(paused_at, now()), updated_at = now() WHERE id = $1`
+	}
+	tag, err := tx.Exec(ctx, query, workflowID)
+	if err != nil {
+		return fmt.Errorf("SetWorkflowPaused failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWorkflowNotFound
+	}
+	if err := notifyNodeEvent(ctx, tx, workflowID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// PausedWorkflows returns which of the given workflows are paused.
+func (p *PostgresStateManager) PausedWorkflows(ctx context.Context, workflowIDs []string) ([]string, error) {
+	if len(workflowIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := p.pool.Query(ctx,
+		`SELECT id FROM workflows WHERE id = ANY($1::uuid[]) AND paused_at IS NOT NULL`, workflowIDs)
+	if err != nil {
+		return nil, fmt.Errorf("PausedWorkflows query failed: %w", err)
+	}
+	defer rows.Close()
+	var paused []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("PausedWorkflows scan failed: %w", err)
+		}
+		paused = append(paused, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PausedWorkflows rows error: %w", err)
+	}
+	return paused, nil
+}
@@ -9,13 +9,46 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "paul.hobbs.page/aisociety/protos"
 )
 
+// DefaultSatisfyingStatuses are the parent statuses that unblock a child node
+// when PostgresStateManager.SatisfyingStatuses is empty.
+var DefaultSatisfyingStatuses = []pb.Status{pb.Status_PASS}
+
+// pendingStatuses are the statuses of nodes that have not been dispatched yet
+// and are waiting on their parents.
+var pendingStatuses = []int32{int32(pb.Status_UNKNOWN), int32(pb.Status_BLOCKED)}
+
+// unsatisfiedParentExists is a SQL predicate matching a node aliased as n that
+// has at least one parent in node_edges which is missing or whose status is not
+// in the satisfying set bound to the given placeholder.
+const unsatisfiedParentExists = `EXISTS (
+		SELECT 1 FROM node_edges pe
+		LEFT JOIN nodes pn ON pn.workflow_id = pe.workflow_id AND pn.id::text = pe.parent_node_id
+		WHERE pe.workflow_id = n.workflow_id AND pe.child_node_id = n.id::text
+		  AND (pn.id IS NULL OR NOT (pn.status = ANY(%s))))`
+
+// DefaultLeaseDuration is how long a claimed node stays leased to its scheduler
+// when PostgresStateManager.LeaseDuration is zero.
+const DefaultLeaseDuration = 5 * time.Minute
+
 // PostgresStateManager manages workflow state in Postgres
 type PostgresStateManager struct {
 	pool *pgxpool.Pool
+
+	// SatisfyingStatuses lists the parent statuses that count as a completed
+	// dependency. Defaults to DefaultSatisfyingStatuses when empty.
+	SatisfyingStatuses []pb.Status
+
+	// LeaseDuration is how long ClaimReadyNodes leases a node to the claiming
+	// scheduler. Defaults to DefaultLeaseDuration when zero.
+	LeaseDuration time.Duration
+
+	// EditPolicy limits the edits ApplyAgentEdits applies.
+	EditPolicy EditPolicy
 }
 
 // NewPostgresStateManager creates a new PostgresStateManager
@@ -51,6 +84,16 @@ func (p *PostgresStateManager) ApplyNodeEdits(ctx context.Context, workflowID st
 		}
 	}
 
+	// Edits come from agents as well as clients; never commit a graph the
+	// scheduler cannot resolve.
+	nodes, err := listNodesTx(ctx, tx, workflowID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateGraph(nodes); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -86,7 +129,11 @@ func (p *PostgresStateManager) applyUpdateEdit(ctx context.Context, tx pgx.Tx, w
 		return err
 	}
 
-	return nil
+	if err := p.promoteReadyChildren(ctx, tx, workflowID, edit.Node); err != nil {
+		return err
+	}
+
+	return p.applyFailurePolicy(ctx, tx, workflowID, edit.Node)
 }
 
 func (p *PostgresStateManager) applyDeleteEdit(ctx context.Context, tx pgx.Tx, workflowID string, edit *pb.NodeEdit) error {
@@ -139,19 +186,29 @@ func serializeNodeData(edit *pb.NodeEdit) ([]byte, []byte, []byte, error) {
 }
 
 func updateNodeRecord(ctx context.Context, tx pgx.Tx, workflowID string, edit *pb.NodeEdit, nodeBytes, allTasksBytes, editsBytes []byte) error {
+	// Leases only apply to RUNNING nodes; any other status releases the claim.
+	// An update may push not_before back but never brings it forward, so that
+	// a delay already applied survives rewrites of the node.
 	result, err := tx.Exec(ctx,
-		`UPDATE nodes SET status = $1, node = $2, all_tasks = $3, edits = $4, updated_at = $5
+		`UPDATE nodes SET status = $1, node = $2, all_tasks = $3, edits = $4, updated_at = $5,
+		       lease_owner = CASE WHEN $1 = $8 THEN lease_owner END,
+		       lease_expires_at = CASE WHEN $1 = $8 THEN lease_expires_at END,
+		       not_before = GREATEST(not_before, $9::timestamptz),
+		       delay_ms = $10
 		       WHERE workflow_id = $6 AND id = $7`,
-		int(edit.Node.Status), nodeBytes, allTasksBytes, editsBytes, time.Now(), workflowID, edit.Node.NodeId)
+		int(edit.Node.Status), nodeBytes, allTasksBytes, editsBytes, time.Now(), workflowID, edit.Node.NodeId, int(pb.Status_RUNNING),
+		notBefore(edit.Node), delayMillis(edit.Node))
 	if err != nil {
 		return fmt.Errorf("failed to apply UPDATE edit: %w", err)
 	}
 	if result.RowsAffected() == 0 {
 		return fmt.Errorf("node not found for UPDATE: %s", edit.Node.NodeId)
 	}
-	return nil
+	return notifyNodeEvent(ctx, tx, workflowID)
 }
 
+// replaceNodeEdges replaces the edges of the edited node with those its
+// parent_ids and child_ids list.
 func replaceNodeEdges(ctx context.Context, tx pgx.Tx, workflowID string, edit *pb.NodeEdit) error {
 	if err := deleteNodeEdges(ctx, tx, workflowID, edit.Node.NodeId); err != nil {
 		return fmt.Errorf("failed to delete existing edges for UPDATE: %w", err)
@@ -190,9 +247,11 @@ func (p *PostgresStateManager) createNodeTx(ctx context.Context, tx pgx.Tx, work
 	}
 
 	_, err = tx.Exec(ctx,
-		`INSERT INTO nodes (workflow_id, id, status, node, all_tasks, edits, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $7)`,
+		`INSERT INTO nodes (workflow_id, id, status, node, all_tasks, edits, created_at, updated_at, not_before, delay_ms)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $7,
+		         GREATEST($8::timestamptz, now() + $9::bigint * interval '1 millisecond'), $9)`,
 		workflowID, node.NodeId, int(node.Status), nodeBytes, allTasksBytes, editsBytes, time.Now(),
+		notBefore(node), delayMillis(node),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert node: %w", err)
@@ -212,7 +271,7 @@ func (p *PostgresStateManager) createNodeTx(ctx context.Context, tx pgx.Tx, work
 		}
 	}
 
-	return nil
+	return notifyNodeEvent(ctx, tx, workflowID)
 }
 
 // batchInsertEdges inserts multiple edges efficiently
@@ -223,49 +282,42 @@ func batchInsertEdges(ctx context.Context, tx pgx.Tx, workflowID string, ids1 in
 	var idx = 1
 	var placeholdersBuilder strings.Builder
 
+	appendEdge := func(parentID, childID string) {
+		if placeholdersBuilder.Len() > 0 {
+			placeholdersBuilder.WriteString(",")
+		}
+		fmt.Fprintf(&placeholdersBuilder, "($%d, $%d, $%d)", idx, idx+1, idx+2)
+		values = append(values, workflowID, parentID, childID)
+		idx += 3
+	}
+
 	switch {
 	case isParentInsert:
-		parentIDs := ids1.([]string)
-		var placeholdersBuilder strings.Builder
-
-		appendPlaceholder := func(idx int) {
-			fmt.Fprintf(&placeholdersBuilder, "($%d, $%d, $%d)", idx, idx+1, idx+2)
-		}
-
-		switch {
-		case isParentInsert:
-			childID, ok := ids2.(string)
-			if !ok {
-				return fmt.Errorf("expected ids2 to be string, got %T", ids2)
-			}
-			for _, parentID := range parentIDs {
-				if placeholdersBuilder.Len() > 0 {
-					placeholdersBuilder.WriteString(",")
-				}
-				appendPlaceholder(idx)
-				values = append(values, workflowID, parentID, childID)
-				idx += 3
-			}
-
-		case !isParentInsert:
-			parentIDsSlice, ok := ids1.([]string)
-			if !ok || len(parentIDsSlice) == 0 {
-				return fmt.Errorf("expected ids1 to be non-empty []string, got %T", ids1)
-			}
-			parentID := parentIDsSlice[0]
-
-			childIDs, ok := ids2.([]string)
-			if !ok {
-				return fmt.Errorf("expected ids2 to be []string, got %T", ids2)
-			}
-			for _, childID := range childIDs {
-				if placeholdersBuilder.Len() > 0 {
-					placeholdersBuilder.WriteString(",")
-				}
-				appendPlaceholder(idx)
-				values = append(values, workflowID, parentID, childID)
-				idx += 3
-			}
+		parentIDs, ok := ids1.([]string)
+		if !ok {
+			return fmt.Errorf("expected ids1 to be []string, got %T", ids1)
+		}
+		childID, ok := ids2.(string)
+		if !ok {
+			return fmt.Errorf("expected ids2 to be string, got %T", ids2)
+		}
+		for _, parentID := range parentIDs {
+			appendEdge(parentID, childID)
+		}
+
+	case !isParentInsert:
+		parentIDsSlice, ok := ids1.([]string)
+		if !ok || len(parentIDsSlice) == 0 {
+			return fmt.Errorf("expected ids1 to be non-empty []string, got %T", ids1)
+		}
+		parentID := parentIDsSlice[0]
+
+		childIDs, ok := ids2.([]string)
+		if !ok {
+			return fmt.Errorf("expected ids2 to be []string, got %T", ids2)
+		}
+		for _, childID := range childIDs {
+			appendEdge(parentID, childID)
 		}
 	}
 
@@ -273,13 +325,15 @@ func batchInsertEdges(ctx context.Context, tx pgx.Tx, workflowID string, ids1 in
 		return nil
 	}
 
-	query := `INSERT INTO node_edges (workflow_id, parent_node_id, child_node_id) VALUES ` + placeholdersBuilder.String()
+	// Both nodes of an edge may list it; it is stored once.
+	query := `INSERT INTO node_edges (workflow_id, parent_node_id, child_node_id) VALUES ` + placeholdersBuilder.String() +
+		` ON CONFLICT (workflow_id, parent_node_id, child_node_id) DO NOTHING`
 
 	_, err := tx.Exec(ctx, query, values...)
 	if err != nil {
 		return err
 	}
-	return nil
+	return notifyNodeEvent(ctx, tx, workflowID)
 }
 
 // NewPostgresStateManagerFromConnStr creates a new PostgresStateManager from context and connection string
@@ -320,19 +374,49 @@ func (p *PostgresStateManager) ListWorkflows(ctx context.Context) ([]string, err
 	return ids, nil
 }
 func (p *PostgresStateManager) CreateWorkflow(ctx context.Context, wf *Workflow) (string, error) {
-	query := `INSERT INTO workflows (name, description, status) VALUES ($1, $2, $3) RETURNING id`
-	err := p.pool.QueryRow(ctx, query, wf.Name, wf.Description, int32(wf.Status)).Scan(&wf.ID)
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
-		return "", fmt.Errorf("CreateWorkflow insert failed: %w", err)
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.createWorkflowTx(ctx, tx, wf); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return wf.ID, nil
 }
 
+// createWorkflowTx inserts wf and its nodes, setting wf.ID.
+func (p *PostgresStateManager) createWorkflowTx(ctx context.Context, tx pgx.Tx, wf *Workflow) error {
+	query := `INSERT INTO workflows (name, description, status, priority, owner, failure_policy)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err := tx.QueryRow(ctx, query, wf.Name, wf.Description, int32(wf.Status), wf.Priority, wf.Owner,
+		int32(wf.FailurePolicy)).Scan(&wf.ID)
+	if err != nil {
+		return fmt.Errorf("CreateWorkflow insert failed: %w", err)
+	}
+	for _, n := range wf.Nodes {
+		if err := p.createNodeTx(ctx, tx, wf.ID, n); err != nil {
+			return fmt.Errorf("CreateWorkflow node insert failed: %w", err)
+		}
+	}
+	return nil
+}
+
 func (p *PostgresStateManager) GetWorkflow(ctx context.Context, workflowID string) (*Workflow, error) {
-	query := `SELECT id, name, description, status FROM workflows WHERE id = $1`
+	query := `SELECT id, name, description, COALESCE(status, 0), priority, owner, failure_policy,
+		created_at, updated_at, completed_at, paused_at,
+		COALESCE(parent_workflow_id::text, ''), COALESCE(parent_node_id::text, '')
+		FROM workflows WHERE id = $1`
 	var wf Workflow
-	var statusCode int32
-	err := p.pool.QueryRow(ctx, query, workflowID).Scan(&wf.ID, &wf.Name, &wf.Description, &statusCode)
+	var statusCode, failurePolicy int32
+	var completedAt, pausedAt *time.Time
+	err := p.pool.QueryRow(ctx, query, workflowID).Scan(&wf.ID, &wf.Name, &wf.Description, &statusCode,
+		&wf.Priority, &wf.Owner, &failurePolicy, &wf.CreatedAt, &wf.UpdatedAt, &completedAt, &pausedAt,
+		&wf.ParentWorkflowID, &wf.ParentNodeID)
 	if err != nil {
 		// Return ErrWorkflowNotFound if no workflow found
 		if err.Error() == "no rows in result set" {
@@ -341,27 +425,103 @@ func (p *PostgresStateManager) GetWorkflow(ctx context.Context, workflowID strin
 		return nil, fmt.Errorf("GetWorkflow query failed: %w", err)
 	}
 	wf.Status = pb.Status(statusCode)
+	wf.FailurePolicy = pb.FailurePolicy(failurePolicy)
+	if completedAt != nil {
+		wf.CompletedAt = *completedAt
+	}
+	if pausedAt != nil {
+		wf.PausedAt = *pausedAt
+	}
+
+	rows, err := p.pool.Query(ctx,
+		`SELECT id::text FROM workflows WHERE parent_workflow_id = $1 ORDER BY created_at`, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("GetWorkflow child query failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var childID string
+		if err := rows.Scan(&childID); err != nil {
+			return nil, fmt.Errorf("GetWorkflow child scan failed: %w", err)
+		}
+		wf.ChildWorkflowIDs = append(wf.ChildWorkflowIDs, childID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetWorkflow child rows error: %w", err)
+	}
 	return &wf, nil
 }
 
-func (p *PostgresStateManager) CreateNode(ctx context.Context, workflowID string, node *pb.Node) error {
-	nodeBytes, err := proto.Marshal(node)
+func (p *PostgresStateManager) ListActiveWorkflows(ctx context.Context) ([]string, error) {
+	rows, err := p.pool.Query(ctx, "SELECT id FROM workflows WHERE completed_at IS NULL ORDER BY created_at")
 	if err != nil {
-		return fmt.Errorf("failed to marshal node proto: %w", err)
+		return nil, fmt.Errorf("ListActiveWorkflows query failed: %w", err)
 	}
+	defer rows.Close()
 
-	query := `INSERT INTO nodes (workflow_id, id, status, node) VALUES ($1, $2, $3, $4)`
-	_, err = p.pool.Exec(ctx, query, workflowID, node.NodeId, int32(node.Status), nodeBytes)
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ListActiveWorkflows scan failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListActiveWorkflows rows error: %w", err)
+	}
+	return ids, nil
+}
+
+func (p *PostgresStateManager) CompleteWorkflow(ctx context.Context, workflowID string, status pb.Status) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		`UPDATE workflows SET status = $1, completed_at = now(), updated_at = now()
+		 WHERE id = $2 AND completed_at IS NULL`,
+		int32(status), workflowID)
+	if err != nil {
+		return false, fmt.Errorf("CompleteWorkflow failed: %w", err)
+	}
+	if result.RowsAffected() != 1 {
+		return false, nil
+	}
+	if err := p.finishParentNode(ctx, tx, workflowID, status); err != nil {
+		return false, fmt.Errorf("CompleteWorkflow failed: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (p *PostgresStateManager) CreateNode(ctx context.Context, workflowID string, node *pb.Node) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.createNodeTx(ctx, tx, workflowID, node); err != nil {
 		return fmt.Errorf("CreateNode insert failed: %w", err)
 	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
 func (p *PostgresStateManager) GetNode(ctx context.Context, workflowID, nodeID string) (*pb.Node, error) {
-	query := `SELECT node FROM nodes WHERE workflow_id = $1 AND id = $2`
+	query := `SELECT COALESCE(status, 0), node FROM nodes WHERE workflow_id = $1 AND id = $2`
+	var status int32
 	var nodeBytes []byte
-	err := p.pool.QueryRow(ctx, query, workflowID, nodeID).Scan(&nodeBytes)
+	err := p.pool.QueryRow(ctx, query, workflowID, nodeID).Scan(&status, &nodeBytes)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, pgx.ErrNoRows
@@ -369,26 +529,147 @@ func (p *PostgresStateManager) GetNode(ctx context.Context, workflowID, nodeID s
 		return nil, fmt.Errorf("GetNode query failed: %w", err)
 	}
 
-	var node pb.Node
-	err = proto.Unmarshal(nodeBytes, &node)
+	node, err := unmarshalNode(nodeBytes, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal node proto: %w", err)
 	}
-	return &node, nil
+	if err := loadWorkflowEdges(ctx, p.pool, workflowID, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (p *PostgresStateManager) GetNodes(ctx context.Context, workflowID string, nodeIDs []string) ([]*pb.Node, error) {
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := p.pool.Query(ctx,
+		`SELECT COALESCE(status, 0), node FROM nodes WHERE workflow_id = $1 AND id::text = ANY($2)`,
+		workflowID, nodeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("GetNodes query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*pb.Node
+	for rows.Next() {
+		var status int32
+		var nodeBytes []byte
+		if err := rows.Scan(&status, &nodeBytes); err != nil {
+			return nil, fmt.Errorf("GetNodes scan failed: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
+			return nil, fmt.Errorf("GetNodes unmarshal failed: %w", err)
+		}
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetNodes rows error: %w", err)
+	}
+	if err := loadWorkflowEdges(ctx, p.pool, workflowID, nodes...); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (p *PostgresStateManager) ListNodes(ctx context.Context, workflowID string) ([]*pb.Node, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT COALESCE(status, 0), node FROM nodes WHERE workflow_id = $1 ORDER BY created_at`, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("ListNodes query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*pb.Node
+	for rows.Next() {
+		var status int32
+		var nodeBytes []byte
+		if err := rows.Scan(&status, &nodeBytes); err != nil {
+			return nil, fmt.Errorf("ListNodes scan failed: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
+			return nil, fmt.Errorf("ListNodes unmarshal failed: %w", err)
+		}
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListNodes rows error: %w", err)
+	}
+	if err := loadWorkflowEdges(ctx, p.pool, workflowID, nodes...); err != nil {
+		return nil, err
+	}
+	return nodes, nil
 }
 
 func (p *PostgresStateManager) UpdateNode(ctx context.Context, workflowID string, node *pb.Node) error {
-	// Wrap node in a temporary NodeEdit to reuse serialization and update logic
-	edit := &pb.NodeEdit{
-		Node: node,
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.updateNodeTx(ctx, tx, workflowID, node); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	return nil
+}
+
+// UpdateLeasedNode updates a node like UpdateNode, but only while it is still
+// RUNNING under schedulerID's lease. The row stays locked until the update
+// commits, so a concurrent cancellation either wins or sees the update.
+func (p *PostgresStateManager) UpdateLeasedNode(ctx context.Context, workflowID, schedulerID string, node *pb.Node) error {
 	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	var held bool
+	err = tx.QueryRow(ctx,
+		`SELECT true FROM nodes
+		 WHERE workflow_id = $1 AND id = $2 AND lease_owner = $3 AND status = $4
+		 FOR UPDATE`,
+		workflowID, node.NodeId, schedulerID, int32(pb.Status_RUNNING)).Scan(&held)
+	if err == pgx.ErrNoRows {
+		return ErrLeaseLost
+	}
+	if err != nil {
+		return fmt.Errorf("UpdateLeasedNode lease check failed: %w", err)
+	}
+
+	if err := p.updateNodeTx(ctx, tx, workflowID, node); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updateNodeTx writes node and applies the consequences of its new status to
+// the rest of the workflow.
+func (p *PostgresStateManager) updateNodeTx(ctx context.Context, tx pgx.Tx, workflowID string, node *pb.Node) error {
+	if err := p.expandMap(ctx, tx, workflowID, node); err != nil {
+		return err
+	}
+	if err := p.takeBranch(ctx, tx, workflowID, node); err != nil {
+		return err
+	}
+
+	// Wrap node in a temporary NodeEdit to reuse serialization and update logic
+	edit := &pb.NodeEdit{
+		Node: node,
+	}
+
 	nodeBytes, allTasksBytes, editsBytes, err := serializeNodeData(edit)
 	if err != nil {
 		return fmt.Errorf("failed to serialize node data: %w", err)
@@ -398,35 +679,440 @@ func (p *PostgresStateManager) UpdateNode(ctx context.Context, workflowID string
 		return fmt.Errorf("failed to update node record: %w", err)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if err := p.promoteReadyChildren(ctx, tx, workflowID, node); err != nil {
+		return err
 	}
 
-	return nil
+	return p.applyFailurePolicy(ctx, tx, workflowID, node)
 }
 
-// FindReadyNodes returns all nodes with status PASS (1) across all workflows.
-func (p *PostgresStateManager) FindReadyNodes(ctx context.Context) ([]*pb.Node, error) {
-	rows, err := p.pool.Query(ctx, `SELECT node FROM nodes WHERE status = $1`, int32(pb.Status_PASS))
+// readyNodeCondition is the WHERE clause shared by FindReadyNodes and
+// ClaimReadyNodes. It binds $1 to READY, $2 to the pending statuses and $3 to
+// the satisfying statuses. Nodes of paused workflows, nodes whose not_before
+// has not passed and MAP items over their concurrency limit are never ready.
+var readyNodeCondition = `(n.status = $1
+		   OR (n.status = ANY($2) AND NOT ` + fmt.Sprintf(unsatisfiedParentExists, "$3") + `))
+		AND (n.not_before IS NULL OR n.not_before <= now())
+		AND ` + fanoutCondition + `
+		AND NOT EXISTS (SELECT 1 FROM workflows pw WHERE pw.id = n.workflow_id AND pw.paused_at IS NOT NULL)`
+
+// FindReadyNodes returns up to limit nodes, across all workflows, that can be
+// dispatched: nodes already promoted to READY, plus pending (UNKNOWN or BLOCKED)
+// nodes whose parents in node_edges have all reached a satisfying status. Each
+// node is paired with the ID of the workflow that owns it. A limit that is not
+// positive returns every ready node.
+//
+// Nodes are taken from each workflow in turn, oldest first, and from workflows
+// of higher priority first within each turn, so that a limited result still
+// offers every workflow's next nodes to the scheduling policy, however many
+// nodes a single workflow has ready.
+func (p *PostgresStateManager) FindReadyNodes(ctx context.Context, limit int) ([]*ReadyNode, error) {
+	var limitArg *int
+	if limit > 0 {
+		limitArg = &limit
+	}
+	query := `SELECT n.workflow_id, COALESCE(n.status, 0), n.node, w.priority, w.owner, w.failure_policy, n.updated_at
+		FROM nodes n JOIN workflows w ON w.id = n.workflow_id
+		WHERE ` + readyNodeCondition + `
+		ORDER BY ROW_NUMBER() OVER (PARTITION BY n.workflow_id ORDER BY n.updated_at, n.created_at),
+		         w.priority DESC, n.updated_at, n.created_at
+		LIMIT $4`
+	rows, err := p.pool.Query(ctx, query, int32(pb.Status_READY), pendingStatuses, p.satisfyingStatusCodes(), limitArg)
 	if err != nil {
 		return nil, fmt.Errorf("FindReadyNodes query failed: %w", err)
 	}
 	defer rows.Close()
 
-	var readyNodes []*pb.Node
+	var readyNodes []*ReadyNode
 	for rows.Next() {
+		var status, failurePolicy int32
 		var nodeBytes []byte
-		if err := rows.Scan(&nodeBytes); err != nil {
+		rn := &ReadyNode{}
+		if err := rows.Scan(&rn.WorkflowID, &status, &nodeBytes, &rn.WorkflowPriority, &rn.Owner, &failurePolicy, &rn.ReadySince); err != nil {
 			return nil, fmt.Errorf("FindReadyNodes scan failed: %w", err)
 		}
-		var node pb.Node
-		if err := proto.Unmarshal(nodeBytes, &node); err != nil {
+		node, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
 			return nil, fmt.Errorf("FindReadyNodes unmarshal failed: %w", err)
 		}
-		readyNodes = append(readyNodes, &node)
+		rn.Node = node
+		rn.WorkflowFailurePolicy = pb.FailurePolicy(failurePolicy)
+		readyNodes = append(readyNodes, rn)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("FindReadyNodes rows error: %w", err)
 	}
+	if err := loadReadyNodeEdges(ctx, p.pool, readyNodes); err != nil {
+		return nil, err
+	}
 	return readyNodes, nil
 }
+
+// ClaimReadyNodes atomically moves up to limit ready nodes to RUNNING and
+// leases them to schedulerID for LeaseDuration. Rows locked by a concurrent
+// claim are skipped rather than waited on, so several schedulers can claim in
+// parallel without ever receiving the same node.
+func (p *PostgresStateManager) ClaimReadyNodes(ctx context.Context, limit int, schedulerID string) ([]*ReadyNode, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	query := `WITH claimable AS (
+			SELECT n.id FROM nodes n
+			WHERE ` + readyNodeCondition + `
+			ORDER BY n.created_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE nodes SET status = $5, lease_owner = $6,
+			lease_expires_at = now() + make_interval(secs => $7), updated_at = now()
+		FROM claimable
+		WHERE nodes.id = claimable.id
+		RETURNING nodes.workflow_id, nodes.status, nodes.node, nodes.lease_expires_at`
+	rows, err := p.pool.Query(ctx, query,
+		int32(pb.Status_READY), pendingStatuses, p.satisfyingStatusCodes(),
+		limit, int32(pb.Status_RUNNING), schedulerID, p.leaseDuration().Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("ClaimReadyNodes query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var claimed []*ReadyNode
+	for rows.Next() {
+		var workflowID string
+		var status int32
+		var nodeBytes []byte
+		var expiresAt time.Time
+		if err := rows.Scan(&workflowID, &status, &nodeBytes, &expiresAt); err != nil {
+			return nil, fmt.Errorf("ClaimReadyNodes scan failed: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
+			return nil, fmt.Errorf("ClaimReadyNodes unmarshal failed: %w", err)
+		}
+		claimed = append(claimed, &ReadyNode{WorkflowID: workflowID, Node: node, LeaseExpiresAt: expiresAt})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ClaimReadyNodes rows error: %w", err)
+	}
+	if err := loadReadyNodeEdges(ctx, p.pool, claimed); err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ClaimNodes moves the given nodes to RUNNING and leases them to schedulerID,
+// skipping any that are no longer ready or are locked by a concurrent claim.
+// Claimed nodes are returned in the order they were given.
+func (p *PostgresStateManager) ClaimNodes(ctx context.Context, schedulerID string, nodes []*ReadyNode) ([]*ReadyNode, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(nodes))
+	for i, rn := range nodes {
+		ids[i] = rn.Node.NodeId
+	}
+	query := `WITH claimable AS (
+			SELECT n.id FROM nodes n
+			WHERE n.id = ANY($4::uuid[]) AND ` + readyNodeCondition + `
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE nodes SET status = $5, lease_owner = $6,
+			lease_expires_at = now() + make_interval(secs => $7), updated_at = now()
+		FROM claimable
+		WHERE nodes.id = claimable.id
+		RETURNING nodes.id::text, nodes.status, nodes.node, nodes.lease_expires_at`
+	rows, err := p.pool.Query(ctx, query,
+		int32(pb.Status_READY), pendingStatuses, p.satisfyingStatusCodes(),
+		ids, int32(pb.Status_RUNNING), schedulerID, p.leaseDuration().Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("ClaimNodes query failed: %w", err)
+	}
+	defer rows.Close()
+
+	claimedByID := make(map[string]*ReadyNode)
+	for rows.Next() {
+		var nodeID string
+		var status int32
+		var nodeBytes []byte
+		var expiresAt time.Time
+		if err := rows.Scan(&nodeID, &status, &nodeBytes, &expiresAt); err != nil {
+			return nil, fmt.Errorf("ClaimNodes scan failed: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, status)
+		if err != nil {
+			return nil, fmt.Errorf("ClaimNodes unmarshal failed: %w", err)
+		}
+		claimedByID[nodeID] = &ReadyNode{Node: node, LeaseExpiresAt: expiresAt}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ClaimNodes rows error: %w", err)
+	}
+
+	var claimed []*ReadyNode
+	for _, rn := range nodes {
+		c, ok := claimedByID[rn.Node.NodeId]
+		if !ok {
+			continue
+		}
+		c.WorkflowID = rn.WorkflowID
+		c.WorkflowPriority = rn.WorkflowPriority
+		c.Owner = rn.Owner
+		c.ReadySince = rn.ReadySince
+		c.WorkflowFailurePolicy = rn.WorkflowFailurePolicy
+		claimed = append(claimed, c)
+	}
+	if err := loadReadyNodeEdges(ctx, p.pool, claimed); err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// RenewLease pushes the lease expiry of a RUNNING node forward by
+// LeaseDuration, provided schedulerID still owns the lease.
+func (p *PostgresStateManager) RenewLease(ctx context.Context, workflowID, nodeID, schedulerID string) (time.Time, error) {
+	var expiresAt time.Time
+	err := p.pool.QueryRow(ctx,
+		`UPDATE nodes SET lease_expires_at = now() + make_interval(secs => $1)
+		 WHERE workflow_id = $2 AND id = $3 AND lease_owner = $4 AND status = $5
+		 RETURNING lease_expires_at`,
+		p.leaseDuration().Seconds(), workflowID, nodeID, schedulerID, int32(pb.Status_RUNNING),
+	).Scan(&expiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return time.Time{}, ErrLeaseLost
+		}
+		return time.Time{}, fmt.Errorf("RenewLease failed: %w", err)
+	}
+	return expiresAt, nil
+}
+
+// ReleaseClaims moves the RUNNING nodes that schedulerID leases among nodes
+// back to READY, noting the release in their status logs.
+func (p *PostgresStateManager) ReleaseClaims(ctx context.Context, schedulerID string, nodes []*ReadyNode) (int, error) {
+	if len(nodes) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, len(nodes))
+	for i, rn := range nodes {
+		ids[i] = rn.Node.NodeId
+	}
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT workflow_id, node FROM nodes
+		 WHERE id = ANY($1::uuid[]) AND lease_owner = $2 AND status = $3
+		 FOR UPDATE`,
+		ids, schedulerID, int32(pb.Status_RUNNING))
+	if err != nil {
+		return 0, fmt.Errorf("ReleaseClaims query failed: %w", err)
+	}
+	var released []*ReadyNode
+	for rows.Next() {
+		var workflowID string
+		var nodeBytes []byte
+		if err := rows.Scan(&workflowID, &nodeBytes); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("ReleaseClaims scan failed: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, int32(pb.Status_READY))
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("ReleaseClaims unmarshal failed: %w", err)
+		}
+		appendStatusUpdate(node, pb.Status_READY, fmt.Sprintf("released by scheduler %q before it finished", schedulerID))
+		released = append(released, &ReadyNode{WorkflowID: workflowID, Node: node})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("ReleaseClaims rows error: %w", err)
+	}
+
+	for _, rn := range released {
+		edit := &pb.NodeEdit{Node: rn.Node}
+		nodeBytes, allTasksBytes, editsBytes, err := serializeNodeData(edit)
+		if err != nil {
+			return 0, err
+		}
+		if err := updateNodeRecord(ctx, tx, rn.WorkflowID, edit, nodeBytes, allTasksBytes, editsBytes); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(released), nil
+}
+
+// RecoverExpiredLeases finds RUNNING nodes whose lease has expired, moves them
+// to status and appends a NodeStatus.Update explaining the recovery. Nodes
+// locked by a concurrent recovery are skipped.
+func (p *PostgresStateManager) RecoverExpiredLeases(ctx context.Context, status pb.Status, reason string, limit int) ([]*ReadyNode, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT workflow_id, node, COALESCE(lease_owner, ''), lease_expires_at FROM nodes
+		 WHERE status = $1 AND lease_expires_at < now()
+		 ORDER BY lease_expires_at
+		 LIMIT $2
+		 FOR UPDATE SKIP LOCKED`,
+		int32(pb.Status_RUNNING), limit)
+	if err != nil {
+		return nil, fmt.Errorf("RecoverExpiredLeases query failed: %w", err)
+	}
+	var recovered []*ReadyNode
+	for rows.Next() {
+		var workflowID, owner string
+		var nodeBytes []byte
+		var expiredAt time.Time
+		if err := rows.Scan(&workflowID, &nodeBytes, &owner, &expiredAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("RecoverExpiredLeases scan failed: %w", err)
+		}
+		node, err := unmarshalNode(nodeBytes, int32(status))
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("RecoverExpiredLeases unmarshal failed: %w", err)
+		}
+		appendStatusUpdate(node, status, fmt.Sprintf("%s: lease held by %q expired at %s",
+			reason, owner, expiredAt.UTC().Format(time.RFC3339)))
+		recovered = append(recovered, &ReadyNode{WorkflowID: workflowID, Node: node})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("RecoverExpiredLeases rows error: %w", err)
+	}
+
+	for _, rn := range recovered {
+		edit := &pb.NodeEdit{Node: rn.Node}
+		nodeBytes, allTasksBytes, editsBytes, err := serializeNodeData(edit)
+		if err != nil {
+			return nil, err
+		}
+		if err := updateNodeRecord(ctx, tx, rn.WorkflowID, edit, nodeBytes, allTasksBytes, editsBytes); err != nil {
+			return nil, err
+		}
+		if err := p.promoteReadyChildren(ctx, tx, rn.WorkflowID, rn.Node); err != nil {
+			return nil, err
+		}
+		if err := p.applyFailurePolicy(ctx, tx, rn.WorkflowID, rn.Node); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return recovered, nil
+}
+
+// appendStatusUpdate records a status transition in the node's status log.
+func appendStatusUpdate(node *pb.Node, status pb.Status, message string) {
+	now := time.Now()
+	if node.NodeStatus == nil {
+		node.NodeStatus = &pb.NodeStatus{}
+	}
+	node.NodeStatus.LastUpdated = now.UnixMilli()
+	node.NodeStatus.Progress = append(node.NodeStatus.Progress, &pb.NodeStatus_Update{
+		Status:        status,
+		UpdatedMillis: timestamppb.New(now),
+		Message:       proto.String(message),
+	})
+}
+
+func (p *PostgresStateManager) leaseDuration() time.Duration {
+	if p.LeaseDuration <= 0 {
+		return DefaultLeaseDuration
+	}
+	return p.LeaseDuration
+}
+
+// promoteReadyChildren moves the pending children of node to READY once every
+// one of their parents has reached a satisfying status. It is a no-op unless
+// node itself has just reached a satisfying status.
+func (p *PostgresStateManager) promoteReadyChildren(ctx context.Context, tx pgx.Tx, workflowID string, node *pb.Node) error {
+	if !p.isSatisfying(node.Status) {
+		return nil
+	}
+	// Delays count from the moment a node's parents are all satisfied.
+	query := `UPDATE nodes n SET status = $1, updated_at = now(),
+			not_before = GREATEST(n.not_before, now() + n.delay_ms * interval '1 millisecond')
+		WHERE n.workflow_id = $2
+		  AND n.status = ANY($3)
+		  AND n.id::text IN (SELECT child_node_id FROM node_edges WHERE workflow_id = $2 AND parent_node_id = $4)
+		  AND NOT ` + fmt.Sprintf(unsatisfiedParentExists, "$5")
+	_, err := tx.Exec(ctx, query, int32(pb.Status_READY), workflowID, pendingStatuses, node.NodeId, p.satisfyingStatusCodes())
+	if err != nil {
+		return fmt.Errorf("failed to promote children of node %s: %w", node.NodeId, err)
+	}
+	return nil
+}
+
+func (p *PostgresStateManager) satisfyingStatuses() []pb.Status {
+	if len(p.SatisfyingStatuses) == 0 {
+		return DefaultSatisfyingStatuses
+	}
+	return p.SatisfyingStatuses
+}
+
+func (p *PostgresStateManager) satisfyingStatusCodes() []int32 {
+	statuses := p.satisfyingStatuses()
+	codes := make([]int32, len(statuses))
+	for i, s := range statuses {
+		codes[i] = int32(s)
+	}
+	return codes
+}
+
+func (p *PostgresStateManager) isSatisfying(status pb.Status) bool {
+	for _, s := range p.satisfyingStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// notBefore returns the node's not_before as a query argument, nil if unset.
+func notBefore(node *pb.Node) *time.Time {
+	if node.NotBefore == nil {
+		return nil
+	}
+	t := node.NotBefore.AsTime()
+	return &t
+}
+
+// delayMillis returns the node's delay in milliseconds as a query argument,
+// nil if it has none.
+func delayMillis(node *pb.Node) *int64 {
+	d := node.GetDelay().AsDuration()
+	if d <= 0 {
+		return nil
+	}
+	ms := d.Milliseconds()
+	return &ms
+}
+
+// unmarshalNode decodes a stored node and applies the status column, which is
+// authoritative: readiness promotion advances it without rewriting the blob.
+// Its parent_ids and child_ids are as stored; loadEdges replaces them with
+// the node's edges.
+func unmarshalNode(nodeBytes []byte, status int32) (*pb.Node, error) {
+	var node pb.Node
+	if err := proto.Unmarshal(nodeBytes, &node); err != nil {
+		return nil, err
+	}
+	node.Status = pb.Status(status)
+	return &node, nil
+}
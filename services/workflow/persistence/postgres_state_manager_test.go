@@ -2,15 +2,24 @@ package persistence
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	pb "paul.hobbs.page/aisociety/protos"
+	"paul.hobbs.page/aisociety/services/workflow/schema"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var testManager *PostgresStateManager
@@ -28,6 +37,9 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 	defer testManager.Close()
+	if err := schema.Up(ctx, testManager.pool); err != nil {
+		panic(err)
+	}
 
 	code := m.Run()
 	os.Exit(code)
@@ -41,218 +53,1376 @@ func cleanDB(t *testing.T) {
 	}
 	defer conn.Close(context.Background())
 
-	_, err = conn.Exec(context.Background(), "TRUNCATE node_edges, nodes, workflows RESTART IDENTITY CASCADE;")
+	_, err = conn.Exec(context.Background(), "TRUNCATE node_edges, nodes, workflows, triggers RESTART IDENTITY CASCADE;")
 	if err != nil {
 		t.Fatalf("failed to clean db: %v", err)
 	}
 }
 
-func TestCreateAndGetWorkflow(t *testing.T) {
+func TestCreateAndGetWorkflow(t *testing.T) {
+	cleanDB(t)
+
+	ctx := context.Background()
+	workflow := &Workflow{
+		Name:        "Test Workflow",
+		Description: "A workflow for testing",
+		Status:      pb.Status_UNKNOWN,
+	}
+
+	_, err := testManager.CreateWorkflow(ctx, workflow)
+	if err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	got, err := testManager.GetWorkflow(ctx, workflow.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+
+	if got.Name != workflow.Name || got.Description != workflow.Description || got.Status != workflow.Status {
+		t.Errorf("Got workflow %+v, want %+v", got, workflow)
+	}
+}
+
+func TestCreateAndGetNode(t *testing.T) {
+	cleanDB(t)
+
+	ctx := context.Background()
+	workflow := &Workflow{
+		Name:        "Node Test Workflow",
+		Description: "Workflow for node test",
+		Status:      pb.Status_UNKNOWN,
+	}
+
+	_, err := testManager.CreateWorkflow(ctx, workflow)
+	if err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	node := &pb.Node{
+		NodeId:      uuid.New().String(),
+		Description: "Test Node",
+	}
+
+	err = testManager.CreateNode(ctx, workflow.ID, node)
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+
+	gotNode, err := testManager.GetNode(ctx, workflow.ID, node.NodeId)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+
+	if !proto.Equal(gotNode, node) {
+		t.Errorf("Got node %+v, want %+v", gotNode, node)
+	}
+	// --- Test UpdateNode ---
+	node.Description = "Updated Description"
+	node.Status = pb.Status_RUNNING
+
+	err = testManager.UpdateNode(ctx, workflow.ID, node)
+	if err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+
+	updatedNode, err := testManager.GetNode(ctx, workflow.ID, node.NodeId)
+	if err != nil {
+		t.Fatalf("GetNode after update failed: %v", err)
+	}
+
+	if !proto.Equal(updatedNode, node) {
+		t.Errorf("After update, got node %+v, want %+v", updatedNode, node)
+	}
+}
+func TestGetNodes(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "GetNodesWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	var ids []string
+	for i := 0; i < 3; i++ {
+		node := &pb.Node{NodeId: uuid.New().String(), Description: fmt.Sprintf("node %d", i), Status: pb.Status_PASS}
+		if err := testManager.CreateNode(ctx, wf.ID, node); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+		ids = append(ids, node.NodeId)
+	}
+
+	nodes, err := testManager.GetNodes(ctx, wf.ID, []string{ids[0], ids[2], "not-a-node"})
+	if err != nil {
+		t.Fatalf("GetNodes failed: %v", err)
+	}
+	got := map[string]bool{}
+	for _, n := range nodes {
+		got[n.NodeId] = true
+	}
+	if len(nodes) != 2 || !got[ids[0]] || !got[ids[2]] {
+		t.Errorf("Expected nodes %s and %s, got %v", ids[0], ids[2], nodes)
+	}
+}
+
+func TestListWorkflows(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	// Initially, should be empty
+	ids, err := testManager.ListWorkflows(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkflows failed: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("Expected no workflows, got %d", len(ids))
+	}
+
+	// Add two workflows
+	wf1 := &Workflow{Name: "WF1", Description: "desc1", Status: pb.Status_UNKNOWN}
+	wf2 := &Workflow{Name: "WF2", Description: "desc2", Status: pb.Status_UNKNOWN}
+	id1, err := testManager.CreateWorkflow(ctx, wf1)
+	if err != nil {
+		t.Fatalf("CreateWorkflow 1 failed: %v", err)
+	}
+	id2, err := testManager.CreateWorkflow(ctx, wf2)
+	if err != nil {
+		t.Fatalf("CreateWorkflow 2 failed: %v", err)
+	}
+
+	ids, err = testManager.ListWorkflows(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkflows after insert failed: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Errorf("Expected 2 workflows, got %d", len(ids))
+	}
+	found1, found2 := false, false
+	for _, id := range ids {
+		if id == id1 {
+			found1 = true
+		}
+		if id == id2 {
+			found2 = true
+		}
+	}
+	if !found1 || !found2 {
+		t.Errorf("Workflow IDs not found in list: got %v, want %v and %v", ids, id1, id2)
+	}
+}
+
+func TestCompleteWorkflow(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	done := &Workflow{Name: "Done", Description: "desc", Status: pb.Status_UNKNOWN}
+	active := &Workflow{Name: "Active", Description: "desc", Status: pb.Status_UNKNOWN}
+	for _, wf := range []*Workflow{done, active} {
+		if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+			t.Fatalf("CreateWorkflow failed: %v", err)
+		}
+	}
+	node := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_PASS}
+	if err := testManager.CreateNode(ctx, done.ID, node); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	nodes, err := testManager.ListNodes(ctx, done.ID)
+	if err != nil || len(nodes) != 1 || nodes[0].NodeId != node.NodeId {
+		t.Fatalf("ListNodes = %v, %v; want the one node", nodes, err)
+	}
+
+	completed, err := testManager.CompleteWorkflow(ctx, done.ID, pb.Status_PASS)
+	if err != nil || !completed {
+		t.Fatalf("CompleteWorkflow = %v, %v; want true", completed, err)
+	}
+	if completed, err := testManager.CompleteWorkflow(ctx, done.ID, pb.Status_FAIL); err != nil || completed {
+		t.Errorf("Second CompleteWorkflow = %v, %v; want false", completed, err)
+	}
+
+	got, err := testManager.GetWorkflow(ctx, done.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	if got.Status != pb.Status_PASS || got.CompletedAt.IsZero() {
+		t.Errorf("Expected a completed PASS workflow, got status %v completed at %v", got.Status, got.CompletedAt)
+	}
+	ids, err := testManager.ListActiveWorkflows(ctx)
+	if err != nil || len(ids) != 1 || ids[0] != active.ID {
+		t.Errorf("ListActiveWorkflows = %v, %v; want only %s", ids, err, active.ID)
+	}
+}
+
+func TestGetWorkflow_NotFound(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	_, err := testManager.GetWorkflow(ctx, "nonexistent-id")
+	if err == nil {
+		t.Errorf("Expected error for nonexistent workflow, got nil")
+	}
+}
+
+func TestGetNode_NotFound(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	_, err := testManager.GetNode(ctx, "nonexistent-wf", "nonexistent-node")
+	if err == nil {
+		t.Errorf("Expected error for nonexistent node, got nil")
+	}
+}
+
+func TestUpdateNode_NotFound(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	node := &pb.Node{
+		NodeId:      uuid.New().String(),
+		Description: "Should not exist",
+	}
+	err := testManager.UpdateNode(ctx, "nonexistent-wf", node)
+	if err == nil {
+		t.Errorf("Expected error for updating nonexistent node, got nil")
+	}
+}
+
+func TestCreateNode_InvalidWorkflow(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	node := &pb.Node{
+		NodeId:      uuid.New().String(),
+		Description: "Orphan node",
+	}
+	err := testManager.CreateNode(ctx, "nonexistent-wf", node)
+	if err == nil {
+		t.Errorf("Expected error for creating node in nonexistent workflow, got nil")
+	}
+}
+
+func TestFindReadyNodes(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	// No workflows/nodes: should return empty
+	nodes, err := testManager.FindReadyNodes(ctx, 0)
+	if err != nil {
+		t.Fatalf("FindReadyNodes failed: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("Expected no ready nodes, got %d", len(nodes))
+	}
+
+	wf := &Workflow{Name: "ReadyNodesWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	_, err = testManager.CreateWorkflow(ctx, wf)
+	if err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	// parent -> child, plus an unrelated node that is already running.
+	parentID, childID := uuid.New().String(), uuid.New().String()
+	parent := &pb.Node{NodeId: parentID, Description: "Parent", ChildIds: []string{childID}, Status: pb.Status_BLOCKED}
+	child := &pb.Node{NodeId: childID, Description: "Child", ParentIds: []string{parentID}, Status: pb.Status_BLOCKED}
+	running := &pb.Node{NodeId: uuid.New().String(), Description: "Running", Status: pb.Status_RUNNING}
+	for _, n := range []*pb.Node{parent, child, running} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	// Only the parent has all of its (zero) dependencies satisfied.
+	nodes, err = testManager.FindReadyNodes(ctx, 0)
+	if err != nil {
+		t.Fatalf("FindReadyNodes failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Node.NodeId != parentID || nodes[0].WorkflowID != wf.ID {
+		t.Fatalf("Expected only parent %s in workflow %s to be ready, got %+v", parentID, wf.ID, nodes)
+	}
+
+	// A parent that finished without passing does not unblock its child.
+	parent.Status = pb.Status_FAIL
+	if err := testManager.UpdateNode(ctx, wf.ID, parent); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	nodes, err = testManager.FindReadyNodes(ctx, 0)
+	if err != nil {
+		t.Fatalf("FindReadyNodes failed: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("Expected no ready nodes after parent failed, got %d", len(nodes))
+	}
+
+	// Once the parent passes, the child is promoted to READY.
+	parent.Status = pb.Status_PASS
+	if err := testManager.UpdateNode(ctx, wf.ID, parent); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	gotChild, err := testManager.GetNode(ctx, wf.ID, childID)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if gotChild.Status != pb.Status_READY {
+		t.Errorf("Expected child to be promoted to READY, got %v", gotChild.Status)
+	}
+	nodes, err = testManager.FindReadyNodes(ctx, 0)
+	if err != nil {
+		t.Fatalf("FindReadyNodes failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Node.NodeId != childID {
+		t.Errorf("Expected only child %s to be ready, got %+v", childID, nodes)
+	}
+}
+
+func TestFindReadyNodesLimit(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	busy := &Workflow{Name: "BusyWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	urgent := &Workflow{Name: "UrgentWF", Description: "desc", Status: pb.Status_UNKNOWN, Priority: 5}
+	for _, wf := range []*Workflow{busy, urgent} {
+		if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+			t.Fatalf("CreateWorkflow failed: %v", err)
+		}
+	}
+	var busyIDs []string
+	for i := 0; i < 3; i++ {
+		id := uuid.New().String()
+		busyIDs = append(busyIDs, id)
+		if err := testManager.CreateNode(ctx, busy.ID, &pb.Node{NodeId: id, Status: pb.Status_READY}); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	urgentID := uuid.New().String()
+	if err := testManager.CreateNode(ctx, urgent.ID, &pb.Node{NodeId: urgentID, Status: pb.Status_READY}); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+
+	// The urgent workflow's only node is offered despite the busy workflow's
+	// older backlog.
+	nodes, err := testManager.FindReadyNodes(ctx, 2)
+	if err != nil {
+		t.Fatalf("FindReadyNodes failed: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Node.NodeId != urgentID || nodes[1].Node.NodeId != busyIDs[0] {
+		t.Errorf("Expected the urgent node, then the oldest busy node, got %+v", nodes)
+	}
+	if nodes, err := testManager.FindReadyNodes(ctx, 0); err != nil || len(nodes) != 4 {
+		t.Errorf("Expected every ready node without a limit, got %d, %v", len(nodes), err)
+	}
+}
+
+func TestFindReadyNodes_SatisfyingStatuses(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "SatisfyingWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	parentID, childID := uuid.New().String(), uuid.New().String()
+	parent := &pb.Node{NodeId: parentID, ChildIds: []string{childID}, Status: pb.Status_SKIPPED}
+	child := &pb.Node{NodeId: childID, ParentIds: []string{parentID}, Status: pb.Status_BLOCKED}
+	for _, n := range []*pb.Node{parent, child} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	sm := &PostgresStateManager{pool: testManager.pool, SatisfyingStatuses: []pb.Status{pb.Status_PASS, pb.Status_SKIPPED}}
+	nodes, err := sm.FindReadyNodes(ctx, 0)
+	if err != nil {
+		t.Fatalf("FindReadyNodes failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Node.NodeId != childID {
+		t.Errorf("Expected child of SKIPPED parent to be ready, got %+v", nodes)
+	}
+}
+
+func TestClaimReadyNodes(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "ClaimWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	const numNodes = 20
+	for i := 0; i < numNodes; i++ {
+		n := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_BLOCKED}
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	// Concurrent claimers must receive disjoint sets of nodes.
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	claimedBy := make(map[string]string)
+	for i := 0; i < 4; i++ {
+		schedulerID := fmt.Sprintf("scheduler-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				claimed, err := testManager.ClaimReadyNodes(ctx, 3, schedulerID)
+				if err != nil {
+					t.Errorf("ClaimReadyNodes failed: %v", err)
+					return
+				}
+				if len(claimed) == 0 {
+					return
+				}
+				mu.Lock()
+				for _, rn := range claimed {
+					if rn.Node.Status != pb.Status_RUNNING {
+						t.Errorf("claimed node %s has status %v, want RUNNING", rn.Node.NodeId, rn.Node.Status)
+					}
+					if rn.LeaseExpiresAt.IsZero() {
+						t.Errorf("claimed node %s has no lease expiry", rn.Node.NodeId)
+					}
+					if prev, dup := claimedBy[rn.Node.NodeId]; dup {
+						t.Errorf("node %s claimed by both %s and %s", rn.Node.NodeId, prev, schedulerID)
+					}
+					claimedBy[rn.Node.NodeId] = schedulerID
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(claimedBy) != numNodes {
+		t.Fatalf("Expected %d nodes to be claimed, got %d", numNodes, len(claimedBy))
+	}
+
+	var nodeID, owner string
+	for nodeID, owner = range claimedBy {
+		break
+	}
+	var leaseOwner *string
+	if err := testManager.pool.QueryRow(ctx, `SELECT lease_owner FROM nodes WHERE id = $1`, nodeID).Scan(&leaseOwner); err != nil {
+		t.Fatalf("lease query failed: %v", err)
+	}
+	if leaseOwner == nil || *leaseOwner != owner {
+		t.Errorf("Expected lease owner %q, got %v", owner, leaseOwner)
+	}
+
+	// Completing the node releases the lease.
+	node, err := testManager.GetNode(ctx, wf.ID, nodeID)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	node.Status = pb.Status_PASS
+	if err := testManager.UpdateNode(ctx, wf.ID, node); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if err := testManager.pool.QueryRow(ctx, `SELECT lease_owner FROM nodes WHERE id = $1`, nodeID).Scan(&leaseOwner); err != nil {
+		t.Fatalf("lease query failed: %v", err)
+	}
+	if leaseOwner != nil {
+		t.Errorf("Expected lease to be released, still owned by %q", *leaseOwner)
+	}
+}
+
+func TestClaimNodes(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "ClaimNodesWF", Description: "desc", Status: pb.Status_UNKNOWN, Priority: 3, Owner: "alice"}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	first := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_BLOCKED, Priority: 2}
+	second := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_BLOCKED}
+	for _, n := range []*pb.Node{first, second} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	ready, err := testManager.FindReadyNodes(ctx, 0)
+	if err != nil || len(ready) != 2 {
+		t.Fatalf("FindReadyNodes = %v, %v; want two nodes", ready, err)
+	}
+	for _, rn := range ready {
+		if rn.WorkflowPriority != 3 || rn.Owner != "alice" || rn.ReadySince.IsZero() {
+			t.Errorf("Expected workflow priority and owner on ready node, got %+v", rn)
+		}
+	}
+
+	// Claim in reverse order; a node claimed by another scheduler is skipped.
+	if _, err := testManager.ClaimNodes(ctx, "other", ready[:1]); err != nil {
+		t.Fatalf("ClaimNodes failed: %v", err)
+	}
+	claimed, err := testManager.ClaimNodes(ctx, "sched-1", []*ReadyNode{ready[1], ready[0]})
+	if err != nil {
+		t.Fatalf("ClaimNodes failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].Node.NodeId != ready[1].Node.NodeId {
+		t.Fatalf("Expected only the unclaimed node to be claimed, got %+v", claimed)
+	}
+	if claimed[0].Node.Status != pb.Status_RUNNING || claimed[0].LeaseExpiresAt.IsZero() || claimed[0].Owner != "alice" {
+		t.Errorf("Expected a leased RUNNING node carrying its workflow's owner, got %+v", claimed[0])
+	}
+}
+
+func TestRecoverExpiredLeases(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "LeaseWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	node := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_BLOCKED}
+	if err := testManager.CreateNode(ctx, wf.ID, node); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+
+	sm := &PostgresStateManager{pool: testManager.pool, LeaseDuration: 50 * time.Millisecond}
+	claimed, err := sm.ClaimReadyNodes(ctx, 1, "dead-scheduler")
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want one node", claimed, err)
+	}
+	if _, err := sm.RenewLease(ctx, wf.ID, node.NodeId, "other-scheduler"); err != ErrLeaseLost {
+		t.Errorf("RenewLease by non-owner = %v, want ErrLeaseLost", err)
+	}
+	if _, err := sm.RenewLease(ctx, wf.ID, node.NodeId, "dead-scheduler"); err != nil {
+		t.Errorf("RenewLease by owner failed: %v", err)
+	}
+
+	// Nothing is recovered while the lease is live.
+	recovered, err := sm.RecoverExpiredLeases(ctx, pb.Status_READY, "test", 10)
+	if err != nil {
+		t.Fatalf("RecoverExpiredLeases failed: %v", err)
+	}
+	if len(recovered) != 0 {
+		t.Fatalf("Expected no recovered nodes before expiry, got %d", len(recovered))
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	recovered, err = sm.RecoverExpiredLeases(ctx, pb.Status_READY, "test", 10)
+	if err != nil {
+		t.Fatalf("RecoverExpiredLeases failed: %v", err)
+	}
+	if len(recovered) != 1 || recovered[0].Node.NodeId != node.NodeId {
+		t.Fatalf("Expected node %s to be recovered, got %+v", node.NodeId, recovered)
+	}
+
+	got, err := sm.GetNode(ctx, wf.ID, node.NodeId)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if got.Status != pb.Status_READY {
+		t.Errorf("Expected recovered node to be READY, got %v", got.Status)
+	}
+	updates := got.GetNodeStatus().GetProgress()
+	if len(updates) != 1 || updates[0].Status != pb.Status_READY || !strings.Contains(updates[0].GetMessage(), "dead-scheduler") {
+		t.Errorf("Expected a status update naming the expired lease owner, got %v", updates)
+	}
+	if _, err := sm.RenewLease(ctx, wf.ID, node.NodeId, "dead-scheduler"); err != ErrLeaseLost {
+		t.Errorf("RenewLease after recovery = %v, want ErrLeaseLost", err)
+	}
+}
+
+func TestUpdateLeasedNode(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "LeasedUpdateWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	node := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_BLOCKED}
+	if err := testManager.CreateNode(ctx, wf.ID, node); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 1, "sched-1"); err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want one node", claimed, err)
+	}
+
+	node.Status = pb.Status_PASS
+	if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-2", node); err != ErrLeaseLost {
+		t.Errorf("UpdateLeasedNode by non-owner = %v, want ErrLeaseLost", err)
+	}
+	if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-1", node); err != nil {
+		t.Fatalf("UpdateLeasedNode by owner failed: %v", err)
+	}
+	node.Status = pb.Status_FAIL
+	if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-1", node); err != ErrLeaseLost {
+		t.Errorf("UpdateLeasedNode after completion = %v, want ErrLeaseLost", err)
+	}
+	got, err := testManager.GetNode(ctx, wf.ID, node.NodeId)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if got.Status != pb.Status_PASS {
+		t.Errorf("Expected the owner's update to stick, got %v", got.Status)
+	}
+}
+
+func TestReleaseClaims(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "ReleaseWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := testManager.CreateNode(ctx, wf.ID, &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_READY}); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	claimed, err := testManager.ClaimReadyNodes(ctx, 2, "sched-1")
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want two nodes", claimed, err)
+	}
+	finished := claimed[1].Node
+	finished.Status = pb.Status_PASS
+	if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-1", finished); err != nil {
+		t.Fatalf("UpdateLeasedNode failed: %v", err)
+	}
+
+	if n, err := testManager.ReleaseClaims(ctx, "sched-2", claimed); err != nil || n != 0 {
+		t.Errorf("ReleaseClaims by non-owner = %d, %v; want 0", n, err)
+	}
+	if n, err := testManager.ReleaseClaims(ctx, "sched-1", claimed); err != nil || n != 1 {
+		t.Fatalf("ReleaseClaims = %d, %v; want only the unfinished node released", n, err)
+	}
+	got, err := testManager.GetNode(ctx, wf.ID, claimed[0].Node.NodeId)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if got.Status != pb.Status_READY || len(got.GetNodeStatus().GetProgress()) != 1 {
+		t.Errorf("Expected a READY node noting the release, got %v with %v", got.Status, got.GetNodeStatus().GetProgress())
+	}
+	if _, err := testManager.RenewLease(ctx, wf.ID, got.NodeId, "sched-1"); err != ErrLeaseLost {
+		t.Errorf("RenewLease after release = %v, want ErrLeaseLost", err)
+	}
+	if reclaimed, err := testManager.ClaimReadyNodes(ctx, 2, "sched-2"); err != nil || len(reclaimed) != 1 {
+		t.Errorf("ClaimReadyNodes after release = %v, %v; want the released node", reclaimed, err)
+	}
+}
+
+func TestFailurePolicies(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     pb.FailurePolicy
+		nodePolicy pb.FailurePolicy
+		// Expected statuses of the failed node's child and grandchild, of an
+		// independent pending node and of an independent running node.
+		want [4]pb.Status
+	}{
+		{
+			name: "default skips descendants",
+			want: [4]pb.Status{pb.Status_SKIPPED, pb.Status_SKIPPED, pb.Status_BLOCKED, pb.Status_RUNNING},
+		},
+		{
+			name:   "continue",
+			policy: pb.FailurePolicy_CONTINUE,
+			want:   [4]pb.Status{pb.Status_BLOCKED, pb.Status_BLOCKED, pb.Status_BLOCKED, pb.Status_RUNNING},
+		},
+		{
+			name:   "fail fast",
+			policy: pb.FailurePolicy_FAIL_FAST,
+			want:   [4]pb.Status{pb.Status_SKIPPED, pb.Status_SKIPPED, pb.Status_SKIPPED, pb.Status_SKIPPED},
+		},
+		{
+			name:       "node overrides workflow",
+			policy:     pb.FailurePolicy_FAIL_FAST,
+			nodePolicy: pb.FailurePolicy_CONTINUE,
+			want:       [4]pb.Status{pb.Status_BLOCKED, pb.Status_BLOCKED, pb.Status_BLOCKED, pb.Status_RUNNING},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanDB(t)
+			ctx := context.Background()
+			wf := &Workflow{Name: "FailureWF", Description: "desc", Status: pb.Status_UNKNOWN, FailurePolicy: tt.policy}
+			if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+				t.Fatalf("CreateWorkflow failed: %v", err)
+			}
+			if got, err := testManager.GetWorkflow(ctx, wf.ID); err != nil || got.FailurePolicy != tt.policy {
+				t.Fatalf("GetWorkflow = %+v, %v; want failure policy %v", got, err, tt.policy)
+			}
+
+			// failed -> child -> grandchild, plus independent pending and running nodes.
+			failedID, childID, grandchildID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+			failed := &pb.Node{NodeId: failedID, ChildIds: []string{childID}, Status: pb.Status_RUNNING, FailurePolicy: tt.nodePolicy}
+			child := &pb.Node{NodeId: childID, ParentIds: []string{failedID}, ChildIds: []string{grandchildID}, Status: pb.Status_BLOCKED}
+			grandchild := &pb.Node{NodeId: grandchildID, ParentIds: []string{childID}, Status: pb.Status_BLOCKED}
+			pendingID, runningID := uuid.New().String(), uuid.New().String()
+			other := &pb.Node{NodeId: uuid.New().String(), ChildIds: []string{pendingID}, Status: pb.Status_RUNNING}
+			pending := &pb.Node{NodeId: pendingID, ParentIds: []string{other.NodeId}, Status: pb.Status_BLOCKED}
+			running := &pb.Node{NodeId: runningID, Status: pb.Status_RUNNING}
+			for _, n := range []*pb.Node{failed, child, grandchild, other, pending, running} {
+				if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+					t.Fatalf("CreateNode failed: %v", err)
+				}
+			}
+
+			failed.Status = pb.Status_TASK_ERROR
+			if err := testManager.UpdateNode(ctx, wf.ID, failed); err != nil {
+				t.Fatalf("UpdateNode failed: %v", err)
+			}
+			for i, id := range []string{childID, grandchildID, pendingID, runningID} {
+				got, err := testManager.GetNode(ctx, wf.ID, id)
+				if err != nil {
+					t.Fatalf("GetNode failed: %v", err)
+				}
+				if got.Status != tt.want[i] {
+					t.Errorf("node %d: status %v, want %v", i, got.Status, tt.want[i])
+				}
+				if got.Status == pb.Status_SKIPPED {
+					updates := got.GetNodeStatus().GetProgress()
+					if len(updates) != 1 || !strings.Contains(updates[0].GetMessage(), failedID) {
+						t.Errorf("node %d: expected a status update naming the failed node, got %v", i, updates)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCancelNodeAndWorkflow(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "CancelWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	// running -> child, plus an unrelated pending node and a finished one.
+	runningID, childID := uuid.New().String(), uuid.New().String()
+	running := &pb.Node{NodeId: runningID, ChildIds: []string{childID}, Status: pb.Status_BLOCKED}
+	child := &pb.Node{NodeId: childID, ParentIds: []string{runningID}, Status: pb.Status_BLOCKED}
+	other := &pb.Node{NodeId: uuid.New().String(), ParentIds: []string{uuid.New().String()}, Status: pb.Status_BLOCKED}
+	done := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_PASS}
+	for _, n := range []*pb.Node{running, child, other, done} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1"); err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want only the root node", claimed, err)
+	}
+
+	canceled, err := testManager.CancelNode(ctx, wf.ID, runningID, "wrong prompt")
+	if err != nil {
+		t.Fatalf("CancelNode failed: %v", err)
+	}
+	if len(canceled) != 2 || canceled[0] != runningID || canceled[1] != childID {
+		t.Errorf("Expected the node and its child to be canceled, got %v", canceled)
+	}
+	if _, err := testManager.RenewLease(ctx, wf.ID, runningID, "sched-1"); err != ErrLeaseLost {
+		t.Errorf("RenewLease on canceled node = %v, want ErrLeaseLost", err)
+	}
+	got, err := testManager.GetNode(ctx, wf.ID, runningID)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	updates := got.GetNodeStatus().GetProgress()
+	if got.Status != pb.Status_SKIPPED || len(updates) != 1 || updates[0].GetMessage() != "canceled: wrong prompt" {
+		t.Errorf("Expected a SKIPPED node recording the reason, got %v with %v", got.Status, updates)
+	}
+	if canceled, err := testManager.CancelNode(ctx, wf.ID, done.NodeId, ""); err != nil || len(canceled) != 0 {
+		t.Errorf("CancelNode on a finished node = %v, %v; want nothing canceled", canceled, err)
+	}
+	if _, err := testManager.CancelNode(ctx, wf.ID, uuid.New().String(), ""); err != ErrNodeNotFound {
+		t.Errorf("CancelNode on a missing node = %v, want ErrNodeNotFound", err)
+	}
+
+	canceled, err = testManager.CancelWorkflow(ctx, wf.ID, "")
+	if err != nil {
+		t.Fatalf("CancelWorkflow failed: %v", err)
+	}
+	if len(canceled) != 1 || canceled[0] != other.NodeId {
+		t.Errorf("Expected only the remaining pending node to be canceled, got %v", canceled)
+	}
+	if _, err := testManager.CancelWorkflow(ctx, uuid.New().String(), ""); err != ErrWorkflowNotFound {
+		t.Errorf("CancelWorkflow on a missing workflow = %v, want ErrWorkflowNotFound", err)
+	}
+}
+
+func TestSetWorkflowPaused(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "PauseWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	for _, id := range []string{uuid.New().String(), uuid.New().String()} {
+		if err := testManager.CreateNode(ctx, wf.ID, &pb.Node{NodeId: id, Status: pb.Status_READY}); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	claimed, err := testManager.ClaimReadyNodes(ctx, 1, "sched-1")
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want one node", claimed, err)
+	}
+	inFlight := claimed[0].Node.NodeId
+
+	if err := testManager.SetWorkflowPaused(ctx, wf.ID, true); err != nil {
+		t.Fatalf("SetWorkflowPaused failed: %v", err)
+	}
+	if got, err := testManager.GetWorkflow(ctx, wf.ID); err != nil || got.PausedAt.IsZero() {
+		t.Fatalf("GetWorkflow = %+v, %v; want a paused workflow", got, err)
+	}
+	if paused, err := testManager.PausedWorkflows(ctx, []string{wf.ID, uuid.New().String()}); err != nil ||
+		len(paused) != 1 || paused[0] != wf.ID {
+		t.Errorf("PausedWorkflows = %v, %v; want [%s]", paused, err, wf.ID)
+	}
+	if ready, err := testManager.FindReadyNodes(ctx, 0); err != nil || len(ready) != 0 {
+		t.Errorf("FindReadyNodes on a paused workflow = %v, %v; want none", ready, err)
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1"); err != nil || len(claimed) != 0 {
+		t.Errorf("ClaimReadyNodes on a paused workflow = %v, %v; want none", claimed, err)
+	}
+	// The node claimed before the pause still holds its lease.
+	if _, err := testManager.RenewLease(ctx, wf.ID, inFlight, "sched-1"); err != nil {
+		t.Errorf("RenewLease on the in-flight node failed: %v", err)
+	}
+
+	if err := testManager.SetWorkflowPaused(ctx, wf.ID, false); err != nil {
+		t.Fatalf("SetWorkflowPaused failed: %v", err)
+	}
+	if got, err := testManager.GetWorkflow(ctx, wf.ID); err != nil || !got.PausedAt.IsZero() {
+		t.Fatalf("GetWorkflow = %+v, %v; want a resumed workflow", got, err)
+	}
+	if paused, err := testManager.PausedWorkflows(ctx, []string{wf.ID}); err != nil || len(paused) != 0 {
+		t.Errorf("PausedWorkflows after resuming = %v, %v; want none", paused, err)
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1"); err != nil || len(claimed) != 1 {
+		t.Errorf("ClaimReadyNodes after resuming = %v, %v; want the remaining node", claimed, err)
+	}
+	if err := testManager.SetWorkflowPaused(ctx, uuid.New().String(), true); err != ErrWorkflowNotFound {
+		t.Errorf("SetWorkflowPaused on a missing workflow = %v, want ErrWorkflowNotFound", err)
+	}
+}
+
+func TestNotBeforeAndDelay(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "TimedWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	parentID, childID := uuid.New().String(), uuid.New().String()
+	due := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_READY, NotBefore: timestamppb.New(time.Now().Add(-time.Minute))}
+	later := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_READY, Type: pb.NodeType_TIMER, NotBefore: timestamppb.New(time.Now().Add(time.Hour))}
+	parent := &pb.Node{NodeId: parentID, ChildIds: []string{childID}, Status: pb.Status_BLOCKED}
+	child := &pb.Node{NodeId: childID, ParentIds: []string{parentID}, Status: pb.Status_BLOCKED, Delay: durationpb.New(time.Hour)}
+	for _, n := range []*pb.Node{due, later, parent, child} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	readyIDs := func() map[string]bool {
+		nodes, err := testManager.FindReadyNodes(ctx, 0)
+		if err != nil {
+			t.Fatalf("FindReadyNodes failed: %v", err)
+		}
+		ids := map[string]bool{}
+		for _, n := range nodes {
+			ids[n.Node.NodeId] = true
+		}
+		return ids
+	}
+
+	if ids := readyIDs(); !ids[due.NodeId] || !ids[parentID] || ids[later.NodeId] || len(ids) != 2 {
+		t.Errorf("Expected only the due node and the parent to be ready, got %v", ids)
+	}
+
+	// Updating a node never brings its not_before forward.
+	later.NotBefore = timestamppb.New(time.Now().Add(-time.Hour))
+	if err := testManager.UpdateNode(ctx, wf.ID, later); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if ids := readyIDs(); ids[later.NodeId] {
+		t.Errorf("Expected node %s to stay gated after moving its not_before earlier", later.NodeId)
+	}
+
+	// The child's delay starts once its parent passes.
+	parent.Status = pb.Status_PASS
+	if err := testManager.UpdateNode(ctx, wf.ID, parent); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if got, err := testManager.GetNode(ctx, wf.ID, childID); err != nil || got.Status != pb.Status_READY {
+		t.Fatalf("GetNode = %v, %v; want the child promoted to READY", got, err)
+	}
+	if ids := readyIDs(); ids[childID] {
+		t.Errorf("Expected delayed child %s not to be ready yet", childID)
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1"); err != nil || len(claimed) != 1 || claimed[0].Node.NodeId != due.NodeId {
+		t.Errorf("ClaimReadyNodes = %v, %v; want only the due node", claimed, err)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "ApprovalWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	// Two approval gates, each guarding a child.
+	approveID, rejectID := uuid.New().String(), uuid.New().String()
+	approvedChild, rejectedChild := uuid.New().String(), uuid.New().String()
+	for _, n := range []*pb.Node{
+		{NodeId: approveID, Type: pb.NodeType_APPROVAL, ChildIds: []string{approvedChild}, Status: pb.Status_READY},
+		{NodeId: rejectID, Type: pb.NodeType_APPROVAL, ChildIds: []string{rejectedChild}, Status: pb.Status_READY},
+		{NodeId: approvedChild, ParentIds: []string{approveID}, Status: pb.Status_BLOCKED},
+		{NodeId: rejectedChild, ParentIds: []string{rejectID}, Status: pb.Status_BLOCKED},
+	} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	// The scheduler parks claimed approval nodes.
+	claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1")
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want both approval nodes", claimed, err)
+	}
+	for _, rn := range claimed {
+		rn.Node.Status = pb.Status_WAITING_FOR_APPROVAL
+		if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-1", rn.Node); err != nil {
+			t.Fatalf("UpdateLeasedNode failed: %v", err)
+		}
+	}
+	pending, err := testManager.ListPendingApprovals(ctx, wf.ID)
+	if err != nil || len(pending) != 2 || pending[0].WorkflowID != wf.ID {
+		t.Fatalf("ListPendingApprovals = %v, %v; want both approval nodes", pending, err)
+	}
+	if pending, err := testManager.ListPendingApprovals(ctx, uuid.New().String()); err != nil || len(pending) != 0 {
+		t.Errorf("ListPendingApprovals for another workflow = %v, %v; want none", pending, err)
+	}
+
+	approver := &pb.Caller{Agent: "founder"}
+	node, err := testManager.DecideApproval(ctx, wf.ID, approveID, &pb.Approval{Approved: true, Approver: approver, Comment: "ship it"})
+	if err != nil {
+		t.Fatalf("DecideApproval failed: %v", err)
+	}
+	if node.Status != pb.Status_PASS || node.Approval.GetApprover().GetAgent() != "founder" || node.Approval.DecidedAt == nil {
+		t.Errorf("Expected an approved node recording its approver, got %v", node)
+	}
+	if _, err := testManager.DecideApproval(ctx, wf.ID, rejectID, &pb.Approval{Approver: approver}); err != nil {
+		t.Fatalf("DecideApproval failed: %v", err)
+	}
+
+	for id, want := range map[string]pb.Status{
+		approveID:     pb.Status_PASS,
+		approvedChild: pb.Status_READY,
+		rejectID:      pb.Status_FAIL,
+		rejectedChild: pb.Status_SKIPPED,
+	} {
+		got, err := testManager.GetNode(ctx, wf.ID, id)
+		if err != nil {
+			t.Fatalf("GetNode failed: %v", err)
+		}
+		if got.Status != want {
+			t.Errorf("Expected node %s to be %v, got %v", id, want, got.Status)
+		}
+	}
+	if pending, err := testManager.ListPendingApprovals(ctx, ""); err != nil || len(pending) != 0 {
+		t.Errorf("ListPendingApprovals after deciding = %v, %v; want none", pending, err)
+	}
+	if _, err := testManager.DecideApproval(ctx, wf.ID, approveID, &pb.Approval{Approved: true}); err != ErrNotWaitingForApproval {
+		t.Errorf("DecideApproval on a decided node = %v, want ErrNotWaitingForApproval", err)
+	}
+	if _, err := testManager.DecideApproval(ctx, wf.ID, uuid.New().String(), &pb.Approval{}); err != ErrNodeNotFound {
+		t.Errorf("DecideApproval on a missing node = %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestMapExpansion(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "MapWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	mapID, reduceID := uuid.New().String(), uuid.New().String()
+	mapNode := &pb.Node{
+		NodeId: mapID, Type: pb.NodeType_MAP, ChildIds: []string{reduceID}, Status: pb.Status_READY,
+		AssignedTask: &pb.Task{Id: "all", Subtasks: []*pb.Task{{Id: "a"}, {Id: "b"}, {Id: "c"}}},
+		MapOptions:   &pb.MapOptions{MaxConcurrency: 2, Template: &pb.Node{Description: "worker"}},
+	}
+	reduce := &pb.Node{NodeId: reduceID, Type: pb.NodeType_REDUCE, ParentIds: []string{mapID}, Status: pb.Status_BLOCKED}
+	for _, n := range []*pb.Node{mapNode, reduce} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1"); err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want the map node", claimed, err)
+	}
+	mapNode.Status = pb.Status_PASS
+	if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-1", mapNode); err != nil {
+		t.Fatalf("UpdateLeasedNode failed: %v", err)
+	}
+	// Recording the pass again does not expand the node twice.
+	if err := testManager.UpdateNode(ctx, wf.ID, mapNode); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+
+	nodes, err := testManager.ListNodes(ctx, wf.ID)
+	if err != nil || len(nodes) != 5 {
+		t.Fatalf("ListNodes = %d nodes, %v; want the map, reduce and 3 item nodes", len(nodes), err)
+	}
+	gotReduce, err := testManager.GetNode(ctx, wf.ID, reduceID)
+	if err != nil || len(gotReduce.ParentIds) != 4 || gotReduce.Status != pb.Status_BLOCKED {
+		t.Fatalf("GetNode = %v, %v; want a blocked reduce node waiting on the map node and its items", gotReduce, err)
+	}
+	items := gotReduce.ParentIds[1:]
+	for i, id := range items {
+		item, err := testManager.GetNode(ctx, wf.ID, id)
+		if err != nil {
+			t.Fatalf("GetNode failed: %v", err)
+		}
+		if item.AssignedTask.GetId() != []string{"a", "b", "c"}[i] || item.Description != "worker" || item.ChildIds[0] != reduceID {
+			t.Errorf("unexpected item node %v", item)
+		}
+	}
+
+	// Only two items run at once, in order.
+	claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1")
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want the first two items", claimed, err)
+	}
+	for _, rn := range claimed {
+		if rn.Node.NodeId == items[2] {
+			t.Errorf("claimed the third item over the concurrency limit")
+		}
+	}
+	claimed[0].Node.Status = pb.Status_PASS
+	if err := testManager.UpdateLeasedNode(ctx, wf.ID, "sched-1", claimed[0].Node); err != nil {
+		t.Fatalf("UpdateLeasedNode failed: %v", err)
+	}
+	if claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1"); err != nil || len(claimed) != 1 || claimed[0].Node.NodeId != items[2] {
+		t.Errorf("ClaimReadyNodes = %v, %v; want the third item once a slot frees up", claimed, err)
+	}
+}
+
+func TestMapExpansionFailsOnInvalidOutput(t *testing.T) {
 	cleanDB(t)
-
 	ctx := context.Background()
-	workflow := &Workflow{
-		Name:        "Test Workflow",
-		Description: "A workflow for testing",
-		Status:      pb.Status_UNKNOWN,
-	}
-
-	_, err := testManager.CreateWorkflow(ctx, workflow)
-	if err != nil {
+	wf := &Workflow{Name: "MapOutputWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
 		t.Fatalf("CreateWorkflow failed: %v", err)
 	}
+	node := &pb.Node{
+		NodeId: uuid.New().String(), Type: pb.NodeType_MAP, Status: pb.Status_BLOCKED,
+		MapOptions: &pb.MapOptions{Source: pb.MapOptions_OUTPUT_ITEMS},
+	}
+	if err := testManager.CreateNode(ctx, wf.ID, node); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
 
-	got, err := testManager.GetWorkflow(ctx, workflow.ID)
-	if err != nil {
-		t.Fatalf("GetWorkflow failed: %v", err)
+	node.Status = pb.Status_PASS
+	node.AssignedTask = &pb.Task{Results: []*pb.Task_Result{{Status: pb.Status_PASS, Output: "not a list"}}}
+	if err := testManager.UpdateNode(ctx, wf.ID, node); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if got, err := testManager.GetNode(ctx, wf.ID, node.NodeId); err != nil || got.Status != pb.Status_TASK_ERROR {
+		t.Errorf("GetNode = %v, %v; want TASK_ERROR", got, err)
 	}
 
-	if got.Name != workflow.Name || got.Description != workflow.Description || got.Status != workflow.Status {
-		t.Errorf("Got workflow %+v, want %+v", got, workflow)
+	node.Status = pb.Status_PASS
+	node.AssignedTask.Results[0].Output = `["x", {"y": 1}]`
+	if err := testManager.UpdateNode(ctx, wf.ID, node); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if nodes, err := testManager.ListNodes(ctx, wf.ID); err != nil || len(nodes) != 3 {
+		t.Errorf("ListNodes = %v, %v; want the map node and 2 items", nodes, err)
 	}
 }
 
-func TestCreateAndGetNode(t *testing.T) {
+func TestBranch(t *testing.T) {
 	cleanDB(t)
-
 	ctx := context.Background()
-	workflow := &Workflow{
-		Name:        "Node Test Workflow",
-		Description: "Workflow for node test",
-		Status:      pb.Status_UNKNOWN,
-	}
-
-	_, err := testManager.CreateWorkflow(ctx, workflow)
-	if err != nil {
+	wf := &Workflow{Name: "BranchWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
 		t.Fatalf("CreateWorkflow failed: %v", err)
 	}
-
-	node := &pb.Node{
-		NodeId:      uuid.New().String(),
-		Description: "Test Node",
+	// critic -> branch -> {revise, approve -> publish, log}
+	critic, branch, revise, approve, publish, logID := uuid.New().String(), uuid.New().String(),
+		uuid.New().String(), uuid.New().String(), uuid.New().String(), uuid.New().String()
+	branchNode := &pb.Node{
+		NodeId: branch, Type: pb.NodeType_BRANCH, ParentIds: []string{critic},
+		ChildIds: []string{revise, approve, logID}, Status: pb.Status_BLOCKED,
+		BranchOptions: &pb.BranchOptions{
+			Cases:           []*pb.BranchCase{{When: &pb.ResultPredicate{Status: pb.Status_FAIL, SummaryContains: "REJECT"}, ChildIds: []string{revise}}},
+			DefaultChildIds: []string{approve},
+		},
+	}
+	criticNode := &pb.Node{NodeId: critic, ChildIds: []string{branch}, Status: pb.Status_READY}
+	for _, n := range []*pb.Node{
+		criticNode,
+		branchNode,
+		{NodeId: revise, ParentIds: []string{branch}, Status: pb.Status_BLOCKED},
+		{NodeId: approve, ParentIds: []string{branch}, ChildIds: []string{publish}, Status: pb.Status_BLOCKED},
+		{NodeId: publish, ParentIds: []string{approve}, Status: pb.Status_BLOCKED},
+		{NodeId: logID, ParentIds: []string{branch}, Status: pb.Status_BLOCKED},
+	} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
 	}
 
-	err = testManager.CreateNode(ctx, workflow.ID, node)
-	if err != nil {
-		t.Fatalf("CreateNode failed: %v", err)
+	// The critic ran fine but rejected the RFC.
+	criticNode.Status = pb.Status_PASS
+	criticNode.AssignedTask = &pb.Task{Results: []*pb.Task_Result{{Status: pb.Status_FAIL, Summary: "Rejected: unclear scope"}}}
+	if err := testManager.UpdateNode(ctx, wf.ID, criticNode); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	branchNode.Status = pb.Status_PASS
+	if err := testManager.UpdateNode(ctx, wf.ID, branchNode); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
 	}
 
-	gotNode, err := testManager.GetNode(ctx, workflow.ID, node.NodeId)
+	for id, want := range map[string]pb.Status{
+		revise:  pb.Status_READY,
+		logID:   pb.Status_READY,
+		approve: pb.Status_FILTERED,
+		publish: pb.Status_FILTERED,
+	} {
+		got, err := testManager.GetNode(ctx, wf.ID, id)
+		if err != nil {
+			t.Fatalf("GetNode failed: %v", err)
+		}
+		if got.Status != want {
+			t.Errorf("Expected node %s to be %v, got %v", id, want, got.Status)
+		}
+	}
+	got, err := testManager.GetNode(ctx, wf.ID, branch)
 	if err != nil {
 		t.Fatalf("GetNode failed: %v", err)
 	}
+	if taken := got.BranchOptions.GetTakenChildIds(); len(taken) != 2 || taken[0] != revise || taken[1] != logID {
+		t.Errorf("Expected the branch to record taking %s and %s, got %v", revise, logID, taken)
+	}
+}
 
-	if !proto.Equal(gotNode, node) {
-		t.Errorf("Got node %+v, want %+v", gotNode, node)
+func TestSubworkflow(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "ParentWF", Description: "desc", Status: pb.Status_UNKNOWN, Owner: "founder"}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	template := &pb.CreateWorkflowRequest{Nodes: []*pb.Node{
+		{NodeId: "research", ChildIds: []string{"write"}, Status: pb.Status_READY,
+			AssignedTask: &pb.Task{Goal: "research {{topic}}"}},
+		{NodeId: "write", ParentIds: []string{"research"}, Status: pb.Status_BLOCKED},
+	}}
+	// One sub-workflow runs to completion; the other is canceled.
+	finishedID, canceledID, afterID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	for _, n := range []*pb.Node{
+		{NodeId: finishedID, Type: pb.NodeType_SUBWORKFLOW, ChildIds: []string{afterID}, Status: pb.Status_READY,
+			SubworkflowOptions: &pb.SubworkflowOptions{Template: template, Inputs: map[string]string{"topic": "tides"}}},
+		{NodeId: canceledID, Type: pb.NodeType_SUBWORKFLOW, Status: pb.Status_READY,
+			SubworkflowOptions: &pb.SubworkflowOptions{Template: template}},
+		{NodeId: afterID, ParentIds: []string{finishedID}, Status: pb.Status_BLOCKED},
+	} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
 	}
-	// --- Test UpdateNode ---
-	node.Description = "Updated Description"
-	node.Status = pb.Status_RUNNING
 
-	err = testManager.UpdateNode(ctx, workflow.ID, node)
-	if err != nil {
-		t.Fatalf("UpdateNode failed: %v", err)
+	claimed, err := testManager.ClaimReadyNodes(ctx, 10, "sched-1")
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("ClaimReadyNodes = %v, %v; want both subworkflow nodes", claimed, err)
+	}
+	children := map[string]string{}
+	for _, rn := range claimed {
+		childID, err := testManager.StartSubworkflow(ctx, wf.ID, "sched-1", rn.Node)
+		if err != nil {
+			t.Fatalf("StartSubworkflow failed: %v", err)
+		}
+		if rn.Node.Status != pb.Status_WAITING_FOR_WORKFLOW || rn.Node.SubworkflowOptions.ChildWorkflowId != childID {
+			t.Errorf("Expected node %s to wait for child %s, got %v", rn.Node.NodeId, childID, rn.Node)
+		}
+		children[rn.Node.NodeId] = childID
+		if _, err := testManager.StartSubworkflow(ctx, wf.ID, "sched-1", rn.Node); err != ErrLeaseLost {
+			t.Errorf("Starting a waiting node again = %v, want ErrLeaseLost", err)
+		}
 	}
 
-	updatedNode, err := testManager.GetNode(ctx, workflow.ID, node.NodeId)
+	parent, err := testManager.GetWorkflow(ctx, wf.ID)
+	if err != nil || len(parent.ChildWorkflowIDs) != 2 {
+		t.Fatalf("GetWorkflow = %v, %v; want both child workflows", parent, err)
+	}
+	child, err := testManager.GetWorkflow(ctx, children[finishedID])
 	if err != nil {
-		t.Fatalf("GetNode after update failed: %v", err)
+		t.Fatalf("GetWorkflow failed: %v", err)
 	}
-
-	if !proto.Equal(updatedNode, node) {
-		t.Errorf("After update, got node %+v, want %+v", updatedNode, node)
+	if child.ParentWorkflowID != wf.ID || child.ParentNodeID != finishedID || child.Owner != "founder" {
+		t.Errorf("Expected the child to link to its parent node and inherit its owner, got %+v", child)
 	}
-}
-func TestListWorkflows(t *testing.T) {
-	cleanDB(t)
-	ctx := context.Background()
 
-	// Initially, should be empty
-	ids, err := testManager.ListWorkflows(ctx)
-	if err != nil {
-		t.Fatalf("ListWorkflows failed: %v", err)
+	// Run the child: its nodes got fresh IDs and the inputs.
+	childNodes, err := testManager.ListNodes(ctx, child.ID)
+	if err != nil || len(childNodes) != 2 {
+		t.Fatalf("ListNodes = %v, %v; want the template's nodes", childNodes, err)
+	}
+	for _, n := range childNodes {
+		if n.NodeId == "research" || n.NodeId == "write" {
+			t.Errorf("Expected child node %s to get a fresh ID", n.NodeId)
+		}
+		if n.AssignedTask != nil && n.AssignedTask.Goal != "research tides" {
+			t.Errorf("Expected the inputs to be substituted, got goal %q", n.AssignedTask.Goal)
+		}
+		summary := "researched"
+		if len(n.ChildIds) == 0 {
+			summary = "written"
+		}
+		n.Status = pb.Status_PASS
+		n.AssignedTask = &pb.Task{Results: []*pb.Task_Result{{Status: pb.Status_PASS, Summary: summary}}}
+		if err := testManager.UpdateNode(ctx, child.ID, n); err != nil {
+			t.Fatalf("UpdateNode failed: %v", err)
+		}
 	}
-	if len(ids) != 0 {
-		t.Errorf("Expected no workflows, got %d", len(ids))
+	if completed, err := testManager.CompleteWorkflow(ctx, child.ID, pb.Status_PASS); err != nil || !completed {
+		t.Fatalf("CompleteWorkflow = %v, %v", completed, err)
 	}
 
-	// Add two workflows
-	wf1 := &Workflow{Name: "WF1", Description: "desc1", Status: pb.Status_UNKNOWN}
-	wf2 := &Workflow{Name: "WF2", Description: "desc2", Status: pb.Status_UNKNOWN}
-	id1, err := testManager.CreateWorkflow(ctx, wf1)
+	finished, err := testManager.GetNode(ctx, wf.ID, finishedID)
 	if err != nil {
-		t.Fatalf("CreateWorkflow 1 failed: %v", err)
+		t.Fatalf("GetNode failed: %v", err)
 	}
-	id2, err := testManager.CreateWorkflow(ctx, wf2)
-	if err != nil {
-		t.Fatalf("CreateWorkflow 2 failed: %v", err)
+	results := finished.GetAssignedTask().GetResults()
+	if finished.Status != pb.Status_PASS || len(results) != 1 || results[0].Output != "written" ||
+		results[0].Artifacts["workflow_id"] != child.ID {
+		t.Errorf("Expected the node to pass with the child's result, got %v", finished)
+	}
+	if after, err := testManager.GetNode(ctx, wf.ID, afterID); err != nil || after.Status != pb.Status_READY {
+		t.Errorf("Expected the node after the subworkflow to be READY, got %v, %v", after, err)
 	}
 
-	ids, err = testManager.ListWorkflows(ctx)
-	if err != nil {
-		t.Fatalf("ListWorkflows after insert failed: %v", err)
+	// Canceling a waiting node cancels its child workflow.
+	if _, err := testManager.CancelNode(ctx, wf.ID, canceledID, "no longer needed"); err != nil {
+		t.Fatalf("CancelNode failed: %v", err)
 	}
-	if len(ids) != 2 {
-		t.Errorf("Expected 2 workflows, got %d", len(ids))
+	canceledNodes, err := testManager.ListNodes(ctx, children[canceledID])
+	if err != nil {
+		t.Fatalf("ListNodes failed: %v", err)
 	}
-	found1, found2 := false, false
-	for _, id := range ids {
-		if id == id1 {
-			found1 = true
-		}
-		if id == id2 {
-			found2 = true
+	for _, n := range canceledNodes {
+		if n.Status != pb.Status_SKIPPED {
+			t.Errorf("Expected node %s of the canceled child to be SKIPPED, got %v", n.NodeId, n.Status)
 		}
 	}
-	if !found1 || !found2 {
-		t.Errorf("Workflow IDs not found in list: got %v, want %v and %v", ids, id1, id2)
-	}
 }
 
-func TestGetWorkflow_NotFound(t *testing.T) {
+func TestTriggers(t *testing.T) {
 	cleanDB(t)
 	ctx := context.Background()
-	_, err := testManager.GetWorkflow(ctx, "nonexistent-id")
-	if err == nil {
-		t.Errorf("Expected error for nonexistent workflow, got nil")
+	now := time.Now().UTC().Truncate(time.Minute)
+	due := &Trigger{
+		Name:            "nightly",
+		Schedule:        "@daily",
+		Template:        &pb.CreateWorkflowRequest{Nodes: []*pb.Node{{NodeId: "report"}}, Priority: 3},
+		MissedRunPolicy: pb.MissedRunPolicy_CATCH_UP_ALL,
+		NextRunAt:       now.Add(-time.Hour),
+	}
+	later := &Trigger{Schedule: "@hourly", Template: &pb.CreateWorkflowRequest{}, NextRunAt: now.Add(time.Hour)}
+	for _, tr := range []*Trigger{due, later} {
+		if _, err := testManager.CreateTrigger(ctx, tr); err != nil {
+			t.Fatalf("CreateTrigger failed: %v", err)
+		}
 	}
-}
 
-func TestGetNode_NotFound(t *testing.T) {
-	cleanDB(t)
-	ctx := context.Background()
-	_, err := testManager.GetNode(ctx, "nonexistent-wf", "nonexistent-node")
-	if err == nil {
-		t.Errorf("Expected error for nonexistent node, got nil")
+	all, err := testManager.ListTriggers(ctx)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("ListTriggers = %v, %v; want 2 triggers", all, err)
+	}
+	dueNow, err := testManager.ListDueTriggers(ctx, now)
+	if err != nil || len(dueNow) != 1 || dueNow[0].ID != due.ID {
+		t.Fatalf("ListDueTriggers = %v, %v; want only %s", dueNow, err, due.ID)
+	}
+	got := dueNow[0]
+	if got.Name != "nightly" || got.MissedRunPolicy != pb.MissedRunPolicy_CATCH_UP_ALL ||
+		!proto.Equal(got.Template, due.Template) || !got.NextRunAt.Equal(due.NextRunAt) || !got.LastRunAt.IsZero() {
+		t.Errorf("Got trigger %+v, want %+v", got, due)
 	}
-}
 
-func TestUpdateNode_NotFound(t *testing.T) {
-	cleanDB(t)
-	ctx := context.Background()
-	node := &pb.Node{
-		NodeId:      uuid.New().String(),
-		Description: "Should not exist",
+	next := now.Add(23 * time.Hour)
+	// A run whose workflow cannot be created leaves the trigger due.
+	reportID := uuid.New().String()
+	broken := &Workflow{Nodes: []*pb.Node{{NodeId: reportID}, {NodeId: reportID}}}
+	if ok, err := testManager.FireTrigger(ctx, due.ID, got.NextRunAt, next, []*Workflow{broken}, now); err == nil || ok {
+		t.Errorf("FireTrigger with a broken workflow = %v, %v; want an error", ok, err)
 	}
-	err := testManager.UpdateNode(ctx, "nonexistent-wf", node)
-	if err == nil {
-		t.Errorf("Expected error for updating nonexistent node, got nil")
+	if dueNow, err := testManager.ListDueTriggers(ctx, now); err != nil || len(dueNow) != 1 {
+		t.Errorf("ListDueTriggers after a failed run = %v, %v; want the trigger still due", dueNow, err)
+	}
+	if ids, err := testManager.ListWorkflows(ctx); err != nil || len(ids) != 0 {
+		t.Errorf("ListWorkflows after a failed run = %v, %v; want none", ids, err)
 	}
-}
 
-func TestCreateNode_InvalidWorkflow(t *testing.T) {
-	cleanDB(t)
-	ctx := context.Background()
-	node := &pb.Node{
-		NodeId:      uuid.New().String(),
-		Description: "Orphan node",
+	run := &Workflow{Priority: 3, Nodes: []*pb.Node{{NodeId: reportID}}}
+	if ok, err := testManager.FireTrigger(ctx, due.ID, got.NextRunAt, next, []*Workflow{run}, now); err != nil || !ok {
+		t.Fatalf("FireTrigger = %v, %v; want true", ok, err)
 	}
-	err := testManager.CreateNode(ctx, "nonexistent-wf", node)
-	if err == nil {
-		t.Errorf("Expected error for creating node in nonexistent workflow, got nil")
+	if nodes, err := testManager.ListNodes(ctx, run.ID); err != nil || len(nodes) != 1 || nodes[0].NodeId != reportID {
+		t.Errorf("ListNodes of the triggered workflow = %v, %v; want its node", nodes, err)
+	}
+	again := &Workflow{Nodes: []*pb.Node{{NodeId: uuid.New().String()}}}
+	if ok, err := testManager.FireTrigger(ctx, due.ID, got.NextRunAt, next.Add(time.Hour), []*Workflow{again}, now); err != nil || ok {
+		t.Errorf("Second FireTrigger from the same run = %v, %v; want false", ok, err)
+	}
+	if ids, err := testManager.ListWorkflows(ctx); err != nil || len(ids) != 1 {
+		t.Errorf("ListWorkflows = %v, %v; want only the first run's workflow", ids, err)
+	}
+	if dueNow, err := testManager.ListDueTriggers(ctx, now); err != nil || len(dueNow) != 0 {
+		t.Errorf("ListDueTriggers after advancing = %v, %v; want none", dueNow, err)
+	}
+	all, err = testManager.ListTriggers(ctx)
+	if err != nil || len(all) != 2 || all[0].LastWorkflowID != run.ID || !all[0].LastRunAt.Equal(now) || !all[0].NextRunAt.Equal(next) {
+		t.Errorf("ListTriggers = %+v, %v; want the recorded run", all, err)
 	}
-}
 
-func TestFindReadyNodes(t *testing.T) {
-	cleanDB(t)
-	ctx := context.Background()
-	// No workflows/nodes: should return empty
-	nodes, err := testManager.FindReadyNodes(ctx)
-	if err != nil {
-		t.Fatalf("FindReadyNodes failed: %v", err)
+	if err := testManager.DeleteTrigger(ctx, due.ID); err != nil {
+		t.Fatalf("DeleteTrigger failed: %v", err)
 	}
-	if len(nodes) != 0 {
-		t.Errorf("Expected no ready nodes, got %d", len(nodes))
+	if err := testManager.DeleteTrigger(ctx, due.ID); err != ErrTriggerNotFound {
+		t.Errorf("DeleteTrigger on a deleted trigger = %v, want ErrTriggerNotFound", err)
 	}
+}
 
-	// Create a workflow and a node with Status_READY
-	wf := &Workflow{Name: "ReadyNodesWF", Description: "desc", Status: pb.Status_UNKNOWN}
-	_, err = testManager.CreateWorkflow(ctx, wf)
-	if err != nil {
+func TestListenNodeEvents(t *testing.T) {
+	cleanDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wf := &Workflow{Name: "NotifyWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
 		t.Fatalf("CreateWorkflow failed: %v", err)
 	}
-	node := &pb.Node{
-		NodeId:      uuid.New().String(),
-		Description: "Ready Node",
-		Status:      pb.Status_RUNNING, // Not ready
-	}
-	err = testManager.CreateNode(ctx, wf.ID, node)
+
+	events, err := testManager.ListenNodeEvents(ctx)
 	if err != nil {
+		t.Fatalf("ListenNodeEvents failed: %v", err)
+	}
+	expectEvent := func(what string) {
+		t.Helper()
+		select {
+		case got := <-events:
+			if got != wf.ID {
+				t.Errorf("%s: got event for workflow %q, want %q", what, got, wf.ID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s: no node event received", what)
+		}
+	}
+
+	node := &pb.Node{NodeId: uuid.New().String(), Status: pb.Status_BLOCKED}
+	if err := testManager.CreateNode(ctx, wf.ID, node); err != nil {
 		t.Fatalf("CreateNode failed: %v", err)
 	}
-	// Update node to READY
-	node.Status = pb.Status_PASS // Let's assume PASS is considered ready for this test
-	err = testManager.UpdateNode(ctx, wf.ID, node)
-	if err != nil {
+	expectEvent("CreateNode")
+
+	node.Status = pb.Status_PASS
+	if err := testManager.UpdateNode(ctx, wf.ID, node); err != nil {
 		t.Fatalf("UpdateNode failed: %v", err)
 	}
-	// Now FindReadyNodes should return at least one node
-	nodes, err = testManager.FindReadyNodes(ctx)
-	if err != nil {
-		t.Fatalf("FindReadyNodes after insert failed: %v", err)
-	}
-	if len(nodes) == 0 {
-		t.Errorf("Expected at least one ready node, got 0")
+	expectEvent("UpdateNode")
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event channel not closed after cancel")
 	}
 }
 
@@ -314,6 +1484,195 @@ func TestApplyNodeEdits_Unit(t *testing.T) {
 	}
 }
 
+func TestApplyNodeEditsRejectsInvalidGraphs(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "ValidateEditsWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	aID, bID, cID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	a := &pb.Node{NodeId: aID, ChildIds: []string{bID}}
+	b := &pb.Node{NodeId: bID, ParentIds: []string{aID}}
+	for _, n := range []*pb.Node{a, b} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	insert := func(n *pb.Node) *pb.NodeEdit { return &pb.NodeEdit{Type: pb.NodeEdit_INSERT, Node: n} }
+	update := func(n *pb.Node) *pb.NodeEdit { return &pb.NodeEdit{Type: pb.NodeEdit_UPDATE, Node: n} }
+
+	// A child of a missing node is rejected, inserting nothing.
+	orphan := &pb.Node{NodeId: uuid.New().String(), ParentIds: []string{uuid.New().String()}}
+	if err := testManager.ApplyNodeEdits(ctx, wf.ID, []*pb.NodeEdit{insert(orphan)}); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("ApplyNodeEdits with a dangling parent = %v, want ErrInvalidGraph", err)
+	}
+	if _, err := testManager.GetNode(ctx, wf.ID, orphan.NodeId); err == nil {
+		t.Errorf("Expected the rejected edits to be rolled back")
+	}
+
+	// A child that only lists its parent is enough for an edge.
+	c := &pb.Node{NodeId: cID, ParentIds: []string{bID}}
+	if err := testManager.ApplyNodeEdits(ctx, wf.ID, []*pb.NodeEdit{insert(c)}); err != nil {
+		t.Fatalf("ApplyNodeEdits failed: %v", err)
+	}
+
+	// Closing the loop c -> a is rejected with the path of the cycle.
+	a.ParentIds = []string{cID}
+	c.ChildIds = []string{aID}
+	err := testManager.ApplyNodeEdits(ctx, wf.ID, []*pb.NodeEdit{update(a), update(c)})
+	if !errors.Is(err, ErrInvalidGraph) || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("ApplyNodeEdits closing a cycle = %v, want a cycle error", err)
+	}
+	if got, err := testManager.GetNode(ctx, wf.ID, aID); err != nil || len(got.ParentIds) != 0 {
+		t.Errorf("Expected the cycle to be rolled back, got %v, %v", got, err)
+	}
+}
+
+func TestNodeEdgesAreAuthoritative(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	wf := &Workflow{Name: "EdgesWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	// a -> b is listed on both sides, b -> c only by c.
+	aID, bID, cID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	nodes := []*pb.Node{
+		{NodeId: aID, ChildIds: []string{bID}},
+		{NodeId: bID, ParentIds: []string{aID}},
+		{NodeId: cID, ParentIds: []string{bID}},
+	}
+	for _, n := range nodes {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	var count int
+	if err := testManager.pool.QueryRow(ctx, `SELECT count(*) FROM node_edges WHERE workflow_id = $1`, wf.ID).Scan(&count); err != nil {
+		t.Fatalf("counting edges failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 edges, got %d", count)
+	}
+
+	got, err := testManager.ListNodes(ctx, wf.ID)
+	if err != nil {
+		t.Fatalf("ListNodes failed: %v", err)
+	}
+	want := map[string][2][]string{
+		aID: {nil, {bID}},
+		bID: {{aID}, {cID}},
+		cID: {{bID}, nil},
+	}
+	for _, n := range got {
+		if !reflect.DeepEqual(n.ParentIds, want[n.NodeId][0]) || !reflect.DeepEqual(n.ChildIds, want[n.NodeId][1]) {
+			t.Errorf("node %s: got parents %v and children %v, want %v", n.NodeId, n.ParentIds, n.ChildIds, want[n.NodeId])
+		}
+	}
+
+	// Updating b without c removes the edge, wherever it was listed.
+	update := &pb.NodeEdit{Type: pb.NodeEdit_UPDATE, Node: &pb.Node{NodeId: bID, ParentIds: []string{aID}}}
+	if err := testManager.ApplyNodeEdits(ctx, wf.ID, []*pb.NodeEdit{update}); err != nil {
+		t.Fatalf("ApplyNodeEdits failed: %v", err)
+	}
+	c, err := testManager.GetNode(ctx, wf.ID, cID)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if len(c.ParentIds) != 0 {
+		t.Errorf("Expected c to have no parents left, got %v", c.ParentIds)
+	}
+}
+
+func TestApplyAgentEdits(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+	defer func(policy EditPolicy) { testManager.EditPolicy = policy }(testManager.EditPolicy)
+	testManager.EditPolicy = EditPolicy{MaxNodes: 6, MaxEditDepth: 1}
+
+	wf := &Workflow{Name: "AgentEditsWF", Description: "desc", Status: pb.Status_UNKNOWN}
+	if _, err := testManager.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	// The planner has finished and left a pending and a finished child;
+	// another finished node is unrelated to it.
+	plannerID, draftID, doneID, otherID := uuid.New().String(), uuid.New().String(), uuid.New().String(), uuid.New().String()
+	for _, n := range []*pb.Node{
+		{NodeId: plannerID, ChildIds: []string{draftID, doneID}, Status: pb.Status_PASS},
+		{NodeId: draftID, ParentIds: []string{plannerID}, Status: pb.Status_BLOCKED},
+		{NodeId: doneID, ParentIds: []string{plannerID}, Status: pb.Status_PASS},
+		{NodeId: otherID, Status: pb.Status_PASS},
+	} {
+		if err := testManager.CreateNode(ctx, wf.ID, n); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+
+	researchID, reviewID := uuid.New().String(), uuid.New().String()
+	edits := []*pb.NodeEdit{
+		// Accepted: inserting a step before the draft links it both ways.
+		{Type: pb.NodeEdit_INSERT, Node: &pb.Node{NodeId: researchID, ParentIds: []string{plannerID}, ChildIds: []string{draftID}}},
+		{Type: pb.NodeEdit_UPDATE, Node: &pb.Node{NodeId: draftID, Description: "Write the draft",
+			ParentIds: []string{plannerID, researchID}, Status: pb.Status_BLOCKED}},
+		// Rejected: not downstream of the planner, or finished.
+		{Type: pb.NodeEdit_DELETE, Node: &pb.Node{NodeId: otherID}},
+		{Type: pb.NodeEdit_UPDATE, Node: &pb.Node{NodeId: plannerID, Description: "rewritten"}},
+		{Type: pb.NodeEdit_INSERT, Node: &pb.Node{ParentIds: []string{otherID}}},
+		{Type: pb.NodeEdit_UPDATE, Node: &pb.Node{NodeId: doneID, ParentIds: []string{plannerID}, Description: "rewritten"}},
+		// Rejected: only running the draft may change its status.
+		{Type: pb.NodeEdit_UPDATE, Node: &pb.Node{NodeId: draftID, ParentIds: []string{plannerID, researchID}, Status: pb.Status_PASS}},
+		// The sixth node is accepted, pending whatever it claims; the seventh
+		// is over the limit.
+		{Type: pb.NodeEdit_INSERT, Node: &pb.Node{NodeId: reviewID, ParentIds: []string{draftID},
+			Status: pb.Status_PASS, IsFinal: true, Attempts: []*pb.Attempt{{Number: 1, Status: pb.Status_PASS}}}},
+		{Type: pb.NodeEdit_INSERT, Node: &pb.Node{ParentIds: []string{draftID}}},
+	}
+	rejected, err := testManager.ApplyAgentEdits(ctx, wf.ID, plannerID, edits)
+	if err != nil {
+		t.Fatalf("ApplyAgentEdits failed: %v", err)
+	}
+	if len(rejected) != 6 {
+		t.Fatalf("Expected 6 rejected edits, got %v", rejected)
+	}
+	for i, want := range []string{"not a descendant", "not a descendant", "is not node", "is final", "its status", "6 nodes"} {
+		if !strings.Contains(rejected[i].Reason, want) {
+			t.Errorf("Expected rejection %d to mention %q, got %q", i, want, rejected[i].Reason)
+		}
+	}
+
+	planner, err := testManager.GetNode(ctx, wf.ID, plannerID)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if len(planner.RejectedEdits) != 6 || len(planner.ChildIds) != 3 || planner.Description == "rewritten" {
+		t.Errorf("Expected the planner to record its rejected edits and link the new step, got %v", planner)
+	}
+	research, err := testManager.GetNode(ctx, wf.ID, researchID)
+	if err != nil || research.EditDepth != 1 {
+		t.Fatalf("Expected the inserted node at edit depth 1, got %v, %v", research, err)
+	}
+	if draft, err := testManager.GetNode(ctx, wf.ID, draftID); err != nil || draft.Description != "Write the draft" || len(draft.ChildIds) != 1 {
+		t.Errorf("Expected the draft to be updated with a new child, got %v, %v", draft, err)
+	}
+	if review, err := testManager.GetNode(ctx, wf.ID, reviewID); err != nil || review.Status != pb.Status_BLOCKED ||
+		review.IsFinal || len(review.Attempts) != 0 {
+		t.Errorf("Expected the inserted review to be pending without execution state, got %v, %v", review, err)
+	}
+	if _, err := testManager.GetNode(ctx, wf.ID, otherID); err != nil {
+		t.Errorf("Expected the unrelated node to survive, got %v", err)
+	}
+
+	// Nodes inserted by edits may not insert nodes deeper than MaxEditDepth.
+	rejected, err = testManager.ApplyAgentEdits(ctx, wf.ID, researchID, []*pb.NodeEdit{
+		{Type: pb.NodeEdit_INSERT, Node: &pb.Node{ParentIds: []string{researchID}}},
+	})
+	if err != nil || len(rejected) != 1 || !strings.Contains(rejected[0].Reason, "edit depth") {
+		t.Errorf("Expected the insert to be rejected for its depth, got %v, %v", rejected, err)
+	}
+}
+
 func TestClose_Idempotent(t *testing.T) {
 	// Just ensure Close can be called multiple times without panic
 	err := testManager.Close()
@@ -511,6 +1870,9 @@ func isExpectedError(err error) bool {
 	if msg == "" {
 		return false
 	}
+	if errors.Is(err, ErrInvalidGraph) {
+		return true
+	}
 	expectedSubstrings := []string{
 		"node not found",
 		"duplicate key",
@@ -2,11 +2,14 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/jackc/pgx/v4"
+
+	"paul.hobbs.page/aisociety/services/workflow/schema"
 )
 
 func TestSchemaTablesExist(t *testing.T) {
@@ -42,7 +45,7 @@ func TestSchemaTablesExist(t *testing.T) {
 	}
 	fmt.Printf("Existing tables: %v\n", existingTables)
 
-	expectedTables := []string{"workflows", "nodes", "node_edges"}
+	expectedTables := []string{"workflows", "nodes", "node_edges", "triggers", "schema_migrations"}
 
 	for _, table := range expectedTables {
 		found := false
@@ -57,3 +60,79 @@ func TestSchemaTablesExist(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaMigrations(t *testing.T) {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, os.Getenv("TEST_DATABASE_URL"))
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer conn.Close(ctx)
+	latest, err := schema.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	// Leave the schema up to date for the other tests, whatever happens.
+	defer func() {
+		if err := schema.Up(ctx, conn); err != nil {
+			t.Fatalf("Up failed: %v", err)
+		}
+	}()
+
+	if v, err := schema.Version(ctx, conn); err != nil || v != latest {
+		t.Fatalf("Version = %d, %v; want %d", v, err, latest)
+	}
+	if err := schema.To(ctx, conn, 0); err != nil {
+		t.Fatalf("migrating down failed: %v", err)
+	}
+	var exists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('workflows') IS NOT NULL`).Scan(&exists); err != nil || exists {
+		t.Errorf("expected migrating down to drop the workflows table, got %v, %v", exists, err)
+	}
+	if err := schema.Up(ctx, conn); err != nil {
+		t.Fatalf("migrating up failed: %v", err)
+	}
+	if v, err := schema.Version(ctx, conn); err != nil || v != latest {
+		t.Fatalf("Version = %d, %v; want %d", v, err, latest)
+	}
+
+	// A database created from the former schema.sql, which holds the first
+	// migration's tables but no schema_migrations, is adopted and upgraded.
+	if err := schema.To(ctx, conn, 0); err != nil {
+		t.Fatalf("migrating down failed: %v", err)
+	}
+	migrations, err := schema.Migrations()
+	if err != nil {
+		t.Fatalf("Migrations failed: %v", err)
+	}
+	if _, err := conn.Exec(ctx, migrations[0].Up); err != nil {
+		t.Fatalf("creating the baseline schema failed: %v", err)
+	}
+	if _, err := conn.Exec(ctx, `DROP TABLE schema_migrations`); err != nil {
+		t.Fatalf("dropping schema_migrations failed: %v", err)
+	}
+	if err := schema.Up(ctx, conn); err != nil {
+		t.Fatalf("migrating the baseline schema up failed: %v", err)
+	}
+	if v, err := schema.Version(ctx, conn); err != nil || v != latest {
+		t.Fatalf("Version = %d, %v; want %d", v, err, latest)
+	}
+	if _, err := conn.Exec(ctx, `SELECT priority, paused_at, parent_workflow_id FROM workflows LIMIT 0`); err != nil {
+		t.Errorf("expected the adopted schema to gain the later workflow columns: %v", err)
+	}
+	if _, err := conn.Exec(ctx, `SELECT lease_owner, not_before, fanout_parent FROM nodes LIMIT 0`); err != nil {
+		t.Errorf("expected the adopted schema to gain the later node columns: %v", err)
+	}
+	if _, err := conn.Exec(ctx, `SELECT next_run_at FROM triggers LIMIT 0`); err != nil {
+		t.Errorf("expected the adopted schema to gain the triggers table: %v", err)
+	}
+
+	// A database migrated by a newer release is left alone.
+	if _, err := conn.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, 'from_the_future')`, latest+1); err != nil {
+		t.Fatalf("recording a future migration failed: %v", err)
+	}
+	defer conn.Exec(ctx, `DELETE FROM schema_migrations WHERE version > $1`, latest)
+	if err := schema.Up(ctx, conn); !errors.Is(err, schema.ErrSchemaTooNew) {
+		t.Errorf("Up on a newer schema = %v, want ErrSchemaTooNew", err)
+	}
+}
@@ -3,38 +3,210 @@ package persistence
 import (
 	"context"
 	"errors"
+	"time"
 
 	pb "paul.hobbs.page/aisociety/protos"
 )
 
 var ErrWorkflowNotFound = errors.New("workflow not found")
 
+var ErrNodeNotFound = errors.New("node not found")
+
+var ErrTriggerNotFound = errors.New("trigger not found")
+
+// ErrNotWaitingForApproval is returned when deciding on a node that is not
+// waiting for approval, e.g. because it was already decided or canceled.
+var ErrNotWaitingForApproval = errors.New("node is not waiting for approval")
+
+// ErrLeaseLost is returned when a scheduler tries to renew or update a lease it
+// no longer holds, e.g. because the lease expired and the node was recovered.
+var ErrLeaseLost = errors.New("node lease lost")
+
 // StateManager defines the interface for workflow state persistence operations.
 // It abstracts the database operations for storing and retrieving workflow data.
 type StateManager interface {
 	// Workflow operations
+
+	// CreateWorkflow stores a workflow along with its nodes, atomically, and
+	// returns its generated ID.
 	CreateWorkflow(ctx context.Context, workflow *Workflow) (string, error)
 	GetWorkflow(ctx context.Context, workflowID string) (*Workflow, error)
 	ListWorkflows(ctx context.Context) ([]string, error)
+	// ListActiveWorkflows returns the IDs of workflows that have not completed.
+	ListActiveWorkflows(ctx context.Context) ([]string, error)
+	// CompleteWorkflow records the final status of a workflow. It reports
+	// false, without changing anything, if the workflow had already completed.
+	// If the workflow was started by a SUBWORKFLOW node still waiting for it,
+	// the node finishes with the same status and a result summarizing it.
+	CompleteWorkflow(ctx context.Context, workflowID string, status pb.Status) (bool, error)
+	// StartSubworkflow creates the child workflow of a SUBWORKFLOW node held
+	// under schedulerID's lease and moves the node to WAITING_FOR_WORKFLOW,
+	// updating node to match. It returns the child's ID, or ErrLeaseLost,
+	// changing nothing, if the lease is no longer held.
+	StartSubworkflow(ctx context.Context, workflowID, schedulerID string, node *pb.Node) (string, error)
 
 	// Node operations
 	CreateNode(ctx context.Context, workflowID string, node *pb.Node) error
 	GetNode(ctx context.Context, workflowID, nodeID string) (*pb.Node, error)
 	UpdateNode(ctx context.Context, workflowID string, node *pb.Node) error
+	// UpdateLeasedNode is UpdateNode for the scheduler holding the node's
+	// lease. It returns ErrLeaseLost, changing nothing, if the node is no
+	// longer RUNNING under schedulerID's lease, e.g. because it was canceled.
+	UpdateLeasedNode(ctx context.Context, workflowID, schedulerID string, node *pb.Node) error
+	// GetNodes returns the nodes of a workflow with the given IDs, skipping
+	// IDs that do not exist. The result is in no particular order.
+	GetNodes(ctx context.Context, workflowID string, nodeIDs []string) ([]*pb.Node, error)
+	// ListNodes returns every node of a workflow.
+	ListNodes(ctx context.Context, workflowID string) ([]*pb.Node, error)
 	ApplyNodeEdits(ctx context.Context, workflowID string, edits []*pb.NodeEdit) error
+	// ApplyAgentEdits applies the edits produced by the agent of node nodeID,
+	// one at a time, under the state manager's EditPolicy. Edits that break
+	// the policy, or would leave an invalid graph, are skipped and recorded
+	// with the reason in the node's rejected_edits. It returns the rejected
+	// edits.
+	ApplyAgentEdits(ctx context.Context, workflowID, nodeID string, edits []*pb.NodeEdit) ([]*pb.RejectedEdit, error)
+
+	// CancelWorkflow moves every unfinished node of a workflow to SKIPPED,
+	// recording reason, and revokes the leases of those in flight. It returns
+	// the IDs of the canceled nodes.
+	CancelWorkflow(ctx context.Context, workflowID, reason string) ([]string, error)
+	// CancelNode cancels a single unfinished node like CancelWorkflow, along
+	// with the pending nodes that can no longer run because of it. It returns
+	// no IDs if the node had already finished.
+	CancelNode(ctx context.Context, workflowID, nodeID, reason string) ([]string, error)
+	// SetWorkflowPaused pauses or resumes a workflow. While a workflow is
+	// paused none of its nodes are found ready or claimed; nodes already
+	// running run to completion, while schedulers release the claimed nodes
+	// they have not started yet. Pausing a paused workflow, or resuming a
+	// running one, is a no-op.
+	SetWorkflowPaused(ctx context.Context, workflowID string, paused bool) error
+	// PausedWorkflows returns which of the given workflows are paused.
+	PausedWorkflows(ctx context.Context, workflowIDs []string) ([]string, error)
+
+	// Approval operations
+
+	// ListPendingApprovals returns the nodes waiting for approval, oldest
+	// first, in workflowID or in every workflow if it is empty.
+	ListPendingApprovals(ctx context.Context, workflowID string) ([]*PendingApproval, error)
+	// DecideApproval records the decision on a node waiting for approval and
+	// moves it to PASS if approved, or FAIL otherwise. It returns the updated
+	// node, or ErrNotWaitingForApproval if the node is not waiting.
+	DecideApproval(ctx context.Context, workflowID, nodeID string, approval *pb.Approval) (*pb.Node, error)
 
 	// Query operations
-	FindReadyNodes(ctx context.Context) ([]*pb.Node, error)
+
+	// FindReadyNodes returns up to limit nodes whose dependencies are all
+	// satisfied and which have not yet been dispatched, or all of them if
+	// limit is not positive.
+	FindReadyNodes(ctx context.Context, limit int) ([]*ReadyNode, error)
+
+	// ClaimReadyNodes atomically transitions up to limit ready nodes to RUNNING
+	// and leases them to schedulerID, so that concurrent schedulers never
+	// dispatch the same node twice.
+	ClaimReadyNodes(ctx context.Context, limit int, schedulerID string) ([]*ReadyNode, error)
+
+	// RenewLease extends the lease schedulerID holds on a RUNNING node and
+	// returns the new expiry, or ErrLeaseLost if the lease is no longer held.
+	RenewLease(ctx context.Context, workflowID, nodeID, schedulerID string) (time.Time, error)
+
+	// ClaimNodes claims the given ready nodes like ClaimReadyNodes, letting the
+	// caller decide which nodes to run. Nodes that are no longer ready or are
+	// being claimed concurrently are left out of the result.
+	ClaimNodes(ctx context.Context, schedulerID string, nodes []*ReadyNode) ([]*ReadyNode, error)
+
+	// RecoverExpiredLeases moves up to limit RUNNING nodes whose lease has
+	// expired to status, recording reason in each node's status log.
+	RecoverExpiredLeases(ctx context.Context, status pb.Status, reason string, limit int) ([]*ReadyNode, error)
+
+	// ReleaseClaims hands the given nodes back to READY, without recording an
+	// outcome, so that another scheduler can claim them. Nodes schedulerID no
+	// longer leases are left alone. It returns how many nodes were released.
+	ReleaseClaims(ctx context.Context, schedulerID string, nodes []*ReadyNode) (int, error)
+
+	// Trigger operations
+
+	// CreateTrigger stores a trigger and returns its generated ID.
+	CreateTrigger(ctx context.Context, trigger *Trigger) (string, error)
+	ListTriggers(ctx context.Context) ([]*Trigger, error)
+	// DeleteTrigger removes a trigger, or returns ErrTriggerNotFound.
+	DeleteTrigger(ctx context.Context, triggerID string) error
+	// ListDueTriggers returns the triggers whose next run is at or before now.
+	ListDueTriggers(ctx context.Context, now time.Time) ([]*Trigger, error)
+	// FireTrigger creates the workflows of a trigger's due runs, moves its
+	// next run from from to next and records the last workflow as its latest
+	// run, all or nothing. It reports false, changing nothing, if the next
+	// run is no longer from, e.g. because another replica fired it first, so
+	// that every run is started at most once.
+	FireTrigger(ctx context.Context, triggerID string, from, next time.Time, workflows []*Workflow, firedAt time.Time) (bool, error)
 
 	// Close the state manager and release resources
 	Close() error
 }
 
+// Trigger starts a new workflow from Template every time Schedule fires.
+type Trigger struct {
+	ID              string
+	Name            string
+	Schedule        string // Cron expression, evaluated in UTC
+	Template        *pb.CreateWorkflowRequest
+	MissedRunPolicy pb.MissedRunPolicy
+	NextRunAt       time.Time
+	LastRunAt       time.Time // Zero until the first run
+	LastWorkflowID  string
+	CreatedAt       time.Time
+}
+
+// PendingApproval is a node waiting for approval.
+type PendingApproval struct {
+	WorkflowID   string
+	Node         *pb.Node
+	WaitingSince time.Time
+}
+
+// ReadyNode pairs a node that is ready for dispatch with the ID of the workflow that owns it.
+type ReadyNode struct {
+	WorkflowID string
+	Node       *pb.Node
+
+	// LeaseExpiresAt is set on nodes returned by ClaimReadyNodes.
+	LeaseExpiresAt time.Time
+
+	// WorkflowPriority and Owner are copied from the owning workflow, and
+	// ReadySince is when the node last changed, for scheduling policies.
+	WorkflowPriority int32
+	Owner            string
+	ReadySince       time.Time
+
+	// WorkflowFailurePolicy is the failure policy of the owning workflow.
+	WorkflowFailurePolicy pb.FailurePolicy
+}
+
+// Priority is the effective scheduling priority of the node.
+func (r *ReadyNode) Priority() int32 {
+	return r.WorkflowPriority + r.Node.GetPriority()
+}
+
 // Workflow represents a workflow entity as stored in the database
 type Workflow struct {
 	ID          string
 	Name        string
 	Description string
 	Status      pb.Status
-	Nodes       []*pb.Node // In-memory representation of nodes
+	Priority    int32
+	Owner       string
+	// FailurePolicy applies to nodes that do not set their own.
+	FailurePolicy pb.FailurePolicy
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   time.Time  // Zero until the workflow completes
+	PausedAt      time.Time  // Zero unless the workflow is paused
+	Nodes         []*pb.Node // In-memory representation of nodes; CreateWorkflow stores them too
+
+	// ParentWorkflowID and ParentNodeID identify the SUBWORKFLOW node that
+	// started the workflow, if any.
+	ParentWorkflowID string
+	ParentNodeID     string
+	// ChildWorkflowIDs are the workflows started by the workflow's
+	// SUBWORKFLOW nodes, oldest first. Only GetWorkflow fills them in.
+	ChildWorkflowIDs []string
 }
@@ -0,0 +1,199 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"google.golang.org/protobuf/proto"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+// InstantiateTemplate copies a workflow template, giving every node a fresh
+// ID, since node IDs are unique across workflows. Parent and child references
+// between template nodes follow the new IDs. Inputs are substituted for
+// {{name}} placeholders in node descriptions and task goals.
+func InstantiateTemplate(template *pb.CreateWorkflowRequest, inputs map[string]string) *pb.CreateWorkflowRequest {
+	req := proto.Clone(template).(*pb.CreateWorkflowRequest)
+	ids := make(map[string]string, len(req.Nodes))
+	for _, n := range req.Nodes {
+		ids[n.NodeId] = uuid.New().String()
+	}
+	remap := func(refs []string) {
+		for i, id := range refs {
+			if newID, ok := ids[id]; ok {
+				refs[i] = newID
+			}
+		}
+	}
+	var pairs []string
+	for name, value := range inputs {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	substitute := strings.NewReplacer(pairs...).Replace
+	for _, n := range req.Nodes {
+		n.NodeId = ids[n.NodeId]
+		remap(n.ParentIds)
+		remap(n.ChildIds)
+		if len(inputs) > 0 {
+			n.Description = substitute(n.Description)
+			substituteGoals(n.AssignedTask, substitute)
+		}
+	}
+	return req
+}
+
+func substituteGoals(task *pb.Task, substitute func(string) string) {
+	if task == nil {
+		return
+	}
+	task.Goal = substitute(task.Goal)
+	for _, sub := range task.Subtasks {
+		substituteGoals(sub, substitute)
+	}
+}
+
+func (p *PostgresStateManager) StartSubworkflow(ctx context.Context, workflowID, schedulerID string, node *pb.Node) (string, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var owner string
+	var priority int32
+	err = tx.QueryRow(ctx,
+		`SELECT w.owner, w.priority FROM nodes n JOIN workflows w ON w.id = n.workflow_id
+		 WHERE n.workflow_id = $1 AND n.id = $2 AND n.lease_owner = $3 AND n.status = $4
+		 FOR UPDATE OF n`,
+		workflowID, node.NodeId, schedulerID, int32(pb.Status_RUNNING)).Scan(&owner, &priority)
+	if err == pgx.ErrNoRows {
+		return "", ErrLeaseLost
+	}
+	if err != nil {
+		return "", fmt.Errorf("StartSubworkflow lease check failed: %w", err)
+	}
+
+	opts := node.GetSubworkflowOptions()
+	req := InstantiateTemplate(opts.GetTemplate(), opts.GetInputs())
+	// Children inherit the owner and priority of the workflow that started
+	// them unless the template sets its own.
+	if req.Owner == "" {
+		req.Owner = owner
+	}
+	if req.Priority == 0 {
+		req.Priority = priority
+	}
+
+	var childID string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO workflows (name, description, status, priority, owner, failure_policy, parent_workflow_id, parent_node_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		"", node.Description, int32(pb.Status_UNKNOWN), req.Priority, req.Owner,
+		int32(EffectiveFailurePolicy(nil, req.FailurePolicy)), workflowID, node.NodeId).Scan(&childID)
+	if err != nil {
+		return "", fmt.Errorf("StartSubworkflow insert failed: %w", err)
+	}
+	for _, n := range req.Nodes {
+		if err := p.createNodeTx(ctx, tx, childID, n); err != nil {
+			return "", fmt.Errorf("StartSubworkflow node insert failed: %w", err)
+		}
+	}
+
+	opts.ChildWorkflowId = childID
+	node.Status = pb.Status_WAITING_FOR_WORKFLOW
+	appendStatusUpdate(node, node.Status, fmt.Sprintf("started child workflow %s", childID))
+	if err := p.updateNodeTx(ctx, tx, workflowID, node); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return childID, nil
+}
+
+// finishParentNode hands the final status of a child workflow to the
+// SUBWORKFLOW node waiting for it, if any, along with a result summarizing the
+// child's leaf nodes.
+func (p *PostgresStateManager) finishParentNode(ctx context.Context, tx pgx.Tx, childID string, status pb.Status) error {
+	var parentWorkflowID string
+	var statusCode int32
+	var nodeBytes []byte
+	err := tx.QueryRow(ctx,
+		`SELECT n.workflow_id::text, COALESCE(n.status, 0), n.node
+		 FROM workflows w JOIN nodes n ON n.workflow_id = w.parent_workflow_id AND n.id = w.parent_node_id
+		 WHERE w.id = $1 AND n.status = $2
+		 FOR UPDATE OF n`,
+		childID, int32(pb.Status_WAITING_FOR_WORKFLOW)).Scan(&parentWorkflowID, &statusCode, &nodeBytes)
+	if err == pgx.ErrNoRows {
+		// Not a child workflow, or its parent node was canceled.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find parent node: %w", err)
+	}
+	node, err := unmarshalNode(nodeBytes, statusCode)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal parent node: %w", err)
+	}
+
+	output, err := leafSummaries(ctx, tx, childID)
+	if err != nil {
+		return err
+	}
+	if node.AssignedTask == nil {
+		node.AssignedTask = &pb.Task{}
+	}
+	node.AssignedTask.Results = append(node.AssignedTask.Results, &pb.Task_Result{
+		Status:    status,
+		Summary:   fmt.Sprintf("child workflow %s finished with %v", childID, status),
+		Output:    output,
+		Artifacts: map[string]string{"workflow_id": childID},
+	})
+	node.Status = status
+	appendStatusUpdate(node, status, fmt.Sprintf("child workflow %s finished", childID))
+	return p.updateNodeTx(ctx, tx, parentWorkflowID, node)
+}
+
+// leafSummaries joins the summaries of the latest results of a workflow's
+// nodes that have no children, one per line.
+func leafSummaries(ctx context.Context, tx pgx.Tx, workflowID string) (string, error) {
+	nodes, err := listNodesTx(ctx, tx, workflowID)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, n := range nodes {
+		if len(n.ChildIds) > 0 {
+			continue
+		}
+		if results := n.GetAssignedTask().GetResults(); len(results) > 0 {
+			if summary := results[len(results)-1].Summary; summary != "" {
+				lines = append(lines, summary)
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// cancelChildWorkflows cancels the unfinished nodes of the workflows started
+// by nodes, and of their children in turn.
+func cancelChildWorkflows(ctx context.Context, tx pgx.Tx, nodes []*pb.Node, reason string) error {
+	for _, n := range nodes {
+		childID := n.GetSubworkflowOptions().GetChildWorkflowId()
+		if childID == "" {
+			continue
+		}
+		_, err := skipNodes(ctx, tx, childID, fmt.Sprintf("%s (parent node %s)", reason, n.NodeId),
+			`SELECT node FROM nodes WHERE workflow_id = $1 AND status = ANY($2) FOR UPDATE`,
+			childID, unfinishedStatuses)
+		if err != nil {
+			return fmt.Errorf("failed to cancel child workflow %s: %w", childID, err)
+		}
+	}
+	return nil
+}
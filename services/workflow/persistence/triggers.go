@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "paul.hobbs.page/aisociety/protos"
+)
+
+const triggerColumns = `id::text, name, schedule, template, missed_run_policy, next_run_at,
+	last_run_at, last_workflow_id, created_at`
+
+func (p *PostgresStateManager) CreateTrigger(ctx context.Context, trigger *Trigger) (string, error) {
+	templateBytes, err := proto.Marshal(trigger.Template)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal trigger template: %w", err)
+	}
+	err = p.pool.QueryRow(ctx,
+		`INSERT INTO triggers (name, schedule, template, missed_run_policy, next_run_at)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		trigger.Name, trigger.Schedule, templateBytes, int32(trigger.MissedRunPolicy), trigger.NextRunAt,
+	).Scan(&trigger.ID)
+	if err != nil {
+		return "", fmt.Errorf("CreateTrigger insert failed: %w", err)
+	}
+	return trigger.ID, nil
+}
+
+func (p *PostgresStateManager) ListTriggers(ctx context.Context) ([]*Trigger, error) {
+	return p.queryTriggers(ctx, `SELECT `+triggerColumns+` FROM triggers ORDER BY created_at`)
+}
+
+func (p *PostgresStateManager) DeleteTrigger(ctx context.Context, triggerID string) error {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM triggers WHERE id::text = $1`, triggerID)
+	if err != nil {
+		return fmt.Errorf("DeleteTrigger failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTriggerNotFound
+	}
+	return nil
+}
+
+func (p *PostgresStateManager) ListDueTriggers(ctx context.Context, now time.Time) ([]*Trigger, error) {
+	return p.queryTriggers(ctx,
+		`SELECT `+triggerColumns+` FROM triggers WHERE next_run_at <= $1 ORDER BY next_run_at`, now)
+}
+
+func (p *PostgresStateManager) FireTrigger(ctx context.Context, triggerID string, from, next time.Time, workflows []*Workflow, firedAt time.Time) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Advancing first locks the trigger, so a replica firing the same run
+	// concurrently waits for this transaction and then finds it advanced.
+	tag, err := tx.Exec(ctx,
+		`UPDATE triggers SET next_run_at = $1 WHERE id::text = $2 AND next_run_at = $3`,
+		next, triggerID, from)
+	if err != nil {
+		return false, fmt.Errorf("FireTrigger advance failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	for _, wf := range workflows {
+		if err := p.createWorkflowTx(ctx, tx, wf); err != nil {
+			return false, err
+		}
+	}
+	if len(workflows) > 0 {
+		_, err = tx.Exec(ctx,
+			`UPDATE triggers SET last_run_at = $1, last_workflow_id = $2 WHERE id::text = $3`,
+			firedAt, workflows[len(workflows)-1].ID, triggerID)
+		if err != nil {
+			return false, fmt.Errorf("FireTrigger failed to record the run: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (p *PostgresStateManager) queryTriggers(ctx context.Context, query string, args ...interface{}) ([]*Trigger, error) {
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("trigger query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []*Trigger
+	for rows.Next() {
+		var t Trigger
+		var templateBytes []byte
+		var policy int32
+		var lastRunAt *time.Time
+		if err := rows.Scan(&t.ID, &t.Name, &t.Schedule, &templateBytes, &policy, &t.NextRunAt,
+			&lastRunAt, &t.LastWorkflowID, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("trigger scan failed: %w", err)
+		}
+		t.Template = &pb.CreateWorkflowRequest{}
+		if err := proto.Unmarshal(templateBytes, t.Template); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal template of trigger %s: %w", t.ID, err)
+		}
+		t.MissedRunPolicy = pb.MissedRunPolicy(policy)
+		if lastRunAt != nil {
+			t.LastRunAt = *lastRunAt
+		}
+		triggers = append(triggers, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("trigger rows error: %w", err)
+	}
+	return triggers, nil
+}
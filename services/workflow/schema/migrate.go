@@ -0,0 +1,229 @@
+// Package schema holds the versioned migrations of the workflow database and
+// applies them. Migrations are embedded in the binaries that use them, so a
+// binary always knows which schema versions it can run against.
+package schema
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey identifies the advisory lock that serializes migrations
+// when several replicas start at once.
+const migrationLockKey = 0x61697363686d61 // "aischma"
+
+// ErrSchemaTooNew is returned when the database has migrations applied that
+// this binary does not know, i.e. it was migrated by a newer release.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary supports")
+
+// Migration is one version step of the schema.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migrations returns the embedded migrations in version order. Versions start
+// at 1 and have no gaps, and every migration can be reverted.
+func Migrations() ([]*Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		m := migrationFileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", e.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		sql, err := migrationFiles.ReadFile("migrations/" + e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", e.Name(), err)
+		}
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(sql)
+		} else {
+			mig.Down = string(sql)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", m.Version)
+		}
+	}
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func Latest() (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	return len(migrations), nil
+}
+
+// DB is satisfied by *pgx.Conn and *pgxpool.Pool.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Version returns the schema version of the database: the newest migration
+// applied, or 0 if none is.
+func Version(ctx context.Context, db DB) (int, error) {
+	var version int
+	err := db.QueryRow(ctx,
+		`SELECT CASE WHEN to_regclass('schema_migrations') IS NULL THEN 0
+		             ELSE (SELECT COALESCE(MAX(version), 0) FROM schema_migrations) END`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Up applies every migration the database is missing. It fails with
+// ErrSchemaTooNew, without changing anything, if the database is newer than
+// the embedded migrations; binaries call it on startup so that they never run
+// against a schema they do not know.
+func Up(ctx context.Context, db DB) error {
+	latest, err := Latest()
+	if err != nil {
+		return err
+	}
+	return To(ctx, db, latest)
+}
+
+// To applies or reverts migrations, one transaction each, until the database
+// is at version. Like Up, it refuses to touch a database newer than the
+// embedded migrations.
+func To(ctx context.Context, db DB, version int) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	if version < 0 || version > len(migrations) {
+		return fmt.Errorf("unknown schema version %d; the latest is %d", version, len(migrations))
+	}
+	for {
+		done, err := step(ctx, db, migrations, version)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// step applies or reverts the migration that brings the database one version
+// closer to target, reporting whether it was at target already. It holds the
+// migration lock throughout, so that concurrent callers take turns.
+func step(ctx context.Context, db DB, migrations []*Migration, target int) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(migrationLockKey)); err != nil {
+		return false, fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	_, err = tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return false, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	current, err := Version(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if current > len(migrations) {
+		return false, fmt.Errorf("%w: the database is at version %d, this binary knows up to %d",
+			ErrSchemaTooNew, current, len(migrations))
+	}
+	if current == 0 {
+		// Databases created from the former schema.sql have at least the
+		// tables of the first migration, but no record of it. Whatever they
+		// gained from later releases of that file, the later migrations only
+		// add what is missing, so they bring such databases up to date too.
+		var adopted bool
+		if err := tx.QueryRow(ctx, `SELECT to_regclass('workflows') IS NOT NULL`).Scan(&adopted); err != nil {
+			return false, fmt.Errorf("failed to look for an existing schema: %w", err)
+		}
+		if adopted {
+			if err := record(ctx, tx, migrations[0]); err != nil {
+				return false, err
+			}
+			log.Printf("Adopted existing schema as version %d", migrations[0].Version)
+			current = migrations[0].Version
+		}
+	}
+	if current == target {
+		return true, tx.Commit(ctx)
+	}
+
+	if current < target {
+		m := migrations[current]
+		if _, err := tx.Exec(ctx, m.Up); err != nil {
+			return false, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if err := record(ctx, tx, m); err != nil {
+			return false, err
+		}
+		log.Printf("Applied migration %d (%s)", m.Version, m.Name)
+	} else {
+		m := migrations[current-1]
+		if _, err := tx.Exec(ctx, m.Down); err != nil {
+			return false, fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+			return false, fmt.Errorf("failed to unrecord migration %d: %w", m.Version, err)
+		}
+		log.Printf("Reverted migration %d (%s)", m.Version, m.Name)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return false, nil
+}
+
+func record(ctx context.Context, tx pgx.Tx, m *Migration) error {
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations failed: %v", err)
+	}
+	latest, err := Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if latest != len(migrations) || latest < 2 {
+		t.Fatalf("Latest = %d with %d migrations", latest, len(migrations))
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %d has version %d", i, m.Version)
+		}
+		if strings.Contains(m.Up, "BEGIN") || strings.Contains(m.Down, "BEGIN") {
+			t.Errorf("migration %d manages its own transaction; each runs in one already", m.Version)
+		}
+	}
+	if m := migrations[0]; m.Name != "initial" || !strings.Contains(m.Up, "CREATE TABLE workflows") {
+		t.Errorf("expected the first migration to create the initial schema, got %s", m.Name)
+	}
+	if m := migrations[len(migrations)-1]; m.Name != "unique_node_edges" {
+		t.Errorf("expected the last migration to make edges unique, got %s", m.Name)
+	}
+	// Databases adopted at version 1 may already have anything a later
+	// migration adds, so those migrations must not fail on it.
+	for _, m := range migrations[1:] {
+		for _, stmt := range []string{"CREATE TABLE ", "CREATE INDEX ", "ADD COLUMN "} {
+			if strings.Count(m.Up, stmt) != strings.Count(m.Up, stmt+"IF NOT EXISTS ") {
+				t.Errorf("migration %d uses %q without IF NOT EXISTS", m.Version, strings.TrimSpace(stmt))
+			}
+		}
+	}
+}
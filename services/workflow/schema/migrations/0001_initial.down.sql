@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS node_edges;
+DROP TABLE IF EXISTS nodes;
+DROP TABLE IF EXISTS workflows;
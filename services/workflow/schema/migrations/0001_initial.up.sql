@@ -1,6 +1,6 @@
--- PostgreSQL schema for Workflow Service
--- Stores workflows as graphs of nodes with relationships
--- Generated based on workflow_node.proto and service documentation
+-- Initial schema of the Workflow Service: workflows as graphs of nodes with
+-- relationships, as created by the former schema.sql before any later
+-- migration. Databases created from that file are adopted at this version.
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
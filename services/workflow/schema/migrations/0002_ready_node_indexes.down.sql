@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_node_edges_child;
+DROP INDEX IF EXISTS idx_nodes_status;
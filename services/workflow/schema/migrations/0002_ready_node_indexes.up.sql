@@ -0,0 +1,3 @@
+-- Readiness is computed from node statuses and the edges into each node.
+CREATE INDEX IF NOT EXISTS idx_nodes_status ON nodes(status);
+CREATE INDEX IF NOT EXISTS idx_node_edges_child ON node_edges(workflow_id, child_node_id);
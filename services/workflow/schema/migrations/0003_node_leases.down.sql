@@ -0,0 +1,2 @@
+ALTER TABLE nodes DROP COLUMN IF EXISTS lease_expires_at;
+ALTER TABLE nodes DROP COLUMN IF EXISTS lease_owner;
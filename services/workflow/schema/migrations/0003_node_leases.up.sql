@@ -0,0 +1,3 @@
+-- Schedulers claim nodes with a lease, so replicas never double-dispatch.
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS lease_owner TEXT;  -- ID of the scheduler that claimed this node while RUNNING
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
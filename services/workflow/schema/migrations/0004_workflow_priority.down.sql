@@ -0,0 +1,2 @@
+ALTER TABLE workflows DROP COLUMN IF EXISTS owner;
+ALTER TABLE workflows DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,2 @@
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;  -- scheduling priority; higher is dispatched first
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS owner TEXT NOT NULL DEFAULT '';  -- principal fair-share scheduling accounts the workflow to
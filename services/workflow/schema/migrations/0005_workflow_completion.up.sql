@@ -0,0 +1 @@
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;  -- set, with the final status, once every node is done
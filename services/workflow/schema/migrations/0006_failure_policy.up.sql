@@ -0,0 +1 @@
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS failure_policy INT NOT NULL DEFAULT 0;  -- protobuf: FailurePolicy enum, for nodes that set none
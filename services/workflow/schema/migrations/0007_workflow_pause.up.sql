@@ -0,0 +1 @@
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS paused_at TIMESTAMPTZ;  -- set while no new nodes may be dispatched
@@ -0,0 +1,14 @@
+-- Cron schedules that start a new workflow from a template on every run
+CREATE TABLE IF NOT EXISTS triggers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL DEFAULT '',
+    schedule TEXT NOT NULL,            -- cron expression, evaluated in UTC
+    template BYTEA NOT NULL,           -- protobuf: CreateWorkflowRequest
+    missed_run_policy INT NOT NULL DEFAULT 0,  -- protobuf: MissedRunPolicy enum
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_workflow_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_triggers_next_run_at ON triggers(next_run_at);
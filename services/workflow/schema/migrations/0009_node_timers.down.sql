@@ -0,0 +1,2 @@
+ALTER TABLE nodes DROP COLUMN IF EXISTS delay_ms;
+ALTER TABLE nodes DROP COLUMN IF EXISTS not_before;
@@ -0,0 +1,2 @@
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS not_before TIMESTAMPTZ;  -- not ready before this time: Node.not_before, pushed back by Node.delay
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS delay_ms BIGINT;  -- Node.delay, re-applied when the node's parents are satisfied
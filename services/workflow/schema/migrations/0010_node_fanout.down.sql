@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_nodes_fanout_parent;
+ALTER TABLE nodes DROP COLUMN IF EXISTS fanout_limit;
+ALTER TABLE nodes DROP COLUMN IF EXISTS fanout_index;
+ALTER TABLE nodes DROP COLUMN IF EXISTS fanout_parent;
@@ -0,0 +1,6 @@
+-- Item nodes expanded from a MAP node
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS fanout_parent UUID;  -- the MAP node this item node was expanded from
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS fanout_index INT;  -- position of the item among its MAP node's items
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS fanout_limit INT;  -- MapOptions.max_concurrency of the MAP node, NULL if unlimited
+
+CREATE INDEX IF NOT EXISTS idx_nodes_fanout_parent ON nodes(fanout_parent);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_workflows_parent_node_id;
+DROP INDEX IF EXISTS idx_workflows_parent_workflow_id;
+ALTER TABLE workflows DROP COLUMN IF EXISTS parent_node_id;
+ALTER TABLE workflows DROP COLUMN IF EXISTS parent_workflow_id;
@@ -0,0 +1,6 @@
+-- Child workflows started by a SUBWORKFLOW node
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS parent_workflow_id UUID REFERENCES workflows(id) ON DELETE SET NULL;
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS parent_node_id UUID;  -- the SUBWORKFLOW node that started the workflow
+
+CREATE INDEX IF NOT EXISTS idx_workflows_parent_workflow_id ON workflows(parent_workflow_id);
+CREATE INDEX IF NOT EXISTS idx_workflows_parent_node_id ON workflows(parent_node_id);
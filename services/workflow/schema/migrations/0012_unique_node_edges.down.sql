@@ -0,0 +1,2 @@
+-- The edges removed on the way up are not restored.
+ALTER TABLE node_edges DROP CONSTRAINT IF EXISTS node_edges_workflow_id_parent_node_id_child_node_id_key;
@@ -0,0 +1,26 @@
+-- node_edges is the single source of truth for the graph, so each edge is
+-- stored once. Removes the edges that the unique constraint or the graph
+-- rules forbid, then adds the constraint, which databases created from the
+-- former schema.sql may already have.
+
+-- Edges whose parent or child is not a node of the edge's workflow, e.g. left
+-- behind by nodes that listed a missing or deleted node.
+DELETE FROM node_edges e
+WHERE NOT EXISTS (SELECT 1 FROM nodes n WHERE n.workflow_id = e.workflow_id AND n.id::text = e.parent_node_id)
+   OR NOT EXISTS (SELECT 1 FROM nodes n WHERE n.workflow_id = e.workflow_id AND n.id::text = e.child_node_id);
+
+-- Self loops, which no node could ever get past.
+DELETE FROM node_edges WHERE parent_node_id = child_node_id;
+
+-- Duplicates, written once for each of the two nodes that listed the edge;
+-- the oldest copy is kept.
+DELETE FROM node_edges e
+USING node_edges d
+WHERE e.workflow_id = d.workflow_id
+  AND e.parent_node_id = d.parent_node_id
+  AND e.child_node_id = d.child_node_id
+  AND (e.created_at, e.id) > (d.created_at, d.id);
+
+ALTER TABLE node_edges DROP CONSTRAINT IF EXISTS node_edges_workflow_id_parent_node_id_child_node_id_key;
+ALTER TABLE node_edges ADD CONSTRAINT node_edges_workflow_id_parent_node_id_child_node_id_key
+    UNIQUE (workflow_id, parent_node_id, child_node_id);